@@ -107,10 +107,15 @@ func (s baseRequests) SetErrorReporter(f func(error)) {
 	}
 }
 
-func (s baseRequests) Boring(isBoring bool) {
+// Boring returns true only if all of the base requests honored it
+func (s baseRequests) Boring(isBoring bool) bool {
+	honored := true
 	for _, request := range s.baseRequests {
-		request.Boring(isBoring)
+		if !request.Boring(isBoring) {
+			honored = false
+		}
 	}
+	return honored
 }
 
 func (s baseRequests) Final() {
@@ -156,9 +161,20 @@ func (s baseSpans) ID() string {
 	return strings.Join(ids, "/")
 }
 
-func (s baseSpans) Boring(b bool) {
+// Boring returns true only if all of the base spans honored it
+func (s baseSpans) Boring(b bool) bool {
+	honored := true
+	for _, span := range s {
+		if !span.Boring(b) {
+			honored = false
+		}
+	}
+	return honored
+}
+
+func (s baseSpans) Metric(k *xopat.MetricAttribute, v float64, t time.Time) {
 	for _, span := range s {
-		span.Boring(b)
+		span.Metric(k, v, t)
 	}
 }
 
@@ -186,10 +202,21 @@ func (p prefillings) PrefillComplete(m string) xopbase.Prefilled {
 	return prefilled
 }
 
+// Line drops base lines that are xopbase.SkipLine. If all of them are
+// SkipLine, then SkipLine is returned so that the line can be skipped.
 func (p prefilleds) Line(level xopnum.Level, t time.Time, frames []runtime.Frame) xopbase.Line {
-	lines := make(lines, len(p))
-	for i, prefilled := range p {
-		lines[i] = prefilled.Line(level, t, frames)
+	lines := make(lines, 0, len(p))
+	for _, prefilled := range p {
+		line := prefilled.Line(level, t, frames)
+		if line != xopbase.SkipLine {
+			lines = append(lines, line)
+		}
+	}
+	switch len(lines) {
+	case 0:
+		return xopbase.SkipLine
+	case 1:
+		return lines[0]
 	}
 	return lines
 }
@@ -230,6 +257,12 @@ func (l lines) Model(k string, v xopbase.ModelArg) {
 	}
 }
 
+func (l lines) Table(k string, v xopbase.SimpleTable) {
+	for _, line := range l {
+		line.Table(k, v)
+	}
+}
+
 func (p prefillings) Any(k xopat.K, v xopbase.ModelArg) {
 	for _, prefilling := range p {
 		prefilling.Any(k, v)
@@ -0,0 +1,56 @@
+package xop_test
+
+import (
+	"testing"
+
+	"github.com/xoplog/xop-go/xoptest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoringTagged(t *testing.T) {
+	tLog := xoptest.New(t)
+	log := tLog.Logger()
+	assert.False(t, log.IsBoring(), "not boring by default")
+	log.Boring()
+	assert.True(t, log.IsBoring(), "honored")
+	child := log.Sub().Fork("child")
+	assert.True(t, child.IsBoring(), "child sees request")
+	log.Info().Msg("still boring")
+	assert.True(t, log.IsBoring(), "info doesn't change boring")
+	log.Done()
+
+	require.Len(t, tLog.Recorder().Requests, 1)
+	md := tLog.Recorder().Requests[0].SpanMetadata.Get("boring")
+	if assert.NotNil(t, md, "tagged") {
+		assert.Equal(t, true, md.Value)
+	}
+	require.Len(t, tLog.Recorder().Spans, 1)
+	assert.Nil(t, tLog.Recorder().Spans[0].SpanMetadata.Get("boring"), "spans are not tagged")
+}
+
+func TestBoringThenError(t *testing.T) {
+	tLog := xoptest.New(t)
+	log := tLog.Logger()
+	log.Boring()
+	child := log.Sub().Fork("child")
+	child.Error().Msg("oops")
+	assert.False(t, log.IsBoring(), "error makes it not boring")
+	log.Boring()
+	assert.False(t, log.IsBoring(), "cannot become boring again")
+	log.Done()
+
+	md := tLog.Recorder().Requests[0].SpanMetadata.Get("boring")
+	if assert.NotNil(t, md, "tagged") {
+		assert.Equal(t, false, md.Value)
+	}
+}
+
+func TestNotBoringWithoutBoring(t *testing.T) {
+	tLog := xoptest.New(t)
+	log := tLog.Logger()
+	log.Error().Msg("oops")
+	log.Done()
+	assert.Nil(t, tLog.Recorder().Requests[0].SpanMetadata.Get("boring"), "not tagged")
+}
@@ -0,0 +1,182 @@
+// This file is generated, DO NOT EDIT.  It comes from the corresponding .zzzgo file
+
+package xop
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/xoplog/xop-go/xopat"
+	"github.com/xoplog/xop-go/xopbase"
+	"github.com/xoplog/xop-go/xopconst"
+	"github.com/xoplog/xop-go/xopnum"
+	"github.com/xoplog/xop-go/xoptrace"
+)
+
+// budgetLine implements xopbase.Line by passing everything through
+// while estimating the size of the line. It is used when there is
+// a byte budget: see LogSettings.Budget.
+type budgetLine struct {
+	line   xopbase.Line
+	shared *shared
+	size   int64
+}
+
+var _ xopbase.Line = &budgetLine{}
+
+// overBudget is called for each line that is above the minimum log level
+// when there is a budget.  It returns true if the line should be dropped.
+func (logger *Logger) overBudget(level xopnum.Level) bool {
+	shared := logger.shared
+	count := atomic.AddInt64(&shared.BudgetLines, 1)
+	if !(logger.settings.budgetLines > 0 && count > int64(logger.settings.budgetLines)) &&
+		!(logger.settings.budgetBytes > 0 && atomic.LoadInt64(&shared.BudgetBytes) >= int64(logger.settings.budgetBytes)) {
+		return false
+	}
+	if logger.settings.budgetSample > 0 &&
+		(atomic.AddInt64(&shared.BudgetOver, 1)-1)%int64(logger.settings.budgetSample) == 0 {
+		return false
+	}
+	// Line() accepts any level so clamp it to fit BudgetDropped
+	if level < 0 {
+		level = 0
+	} else if level > xopnum.MaxLevel {
+		level = xopnum.MaxLevel
+	}
+	atomic.AddInt64(&shared.BudgetDropped[level], 1)
+	return true
+}
+
+// reportBudget records, as request metadata, the number of lines that were
+// dropped due to the budget.
+func (logger *Logger) reportBudget() {
+	var dropped map[string]int64
+	for level := range logger.shared.BudgetDropped {
+		if count := atomic.LoadInt64(&logger.shared.BudgetDropped[level]); count > 0 {
+			if dropped == nil {
+				dropped = make(map[string]int64)
+			}
+			dropped[xopnum.Level(level).String()] = count
+		}
+	}
+	if dropped != nil {
+		logger.Request().Any(xopconst.LinesDropped, dropped)
+	}
+}
+
+func (b *budgetLine) done() {
+	atomic.AddInt64(&b.shared.BudgetBytes, b.size)
+}
+
+func (b *budgetLine) Enum(k *xopat.EnumAttribute, v xopat.Enum) {
+	b.size += int64(len(k.Key()) + len(v.String()))
+	b.line.Enum(k, v)
+}
+
+func (b *budgetLine) Msg(msg string) {
+	b.size += int64(len(msg))
+	b.line.Msg(msg)
+	b.done()
+}
+
+func (b *budgetLine) Template(template string) {
+	b.size += int64(len(template))
+	b.line.Template(template)
+	b.done()
+}
+
+func (b *budgetLine) Model(msg string, v xopbase.ModelArg) {
+	b.size += int64(len(msg)) + estimateSize(v)
+	b.line.Model(msg, v)
+	b.done()
+}
+
+func (b *budgetLine) Table(msg string, t xopbase.SimpleTable) {
+	b.size += int64(len(msg)) + estimateSize(t)
+	b.line.Table(msg, t)
+	b.done()
+}
+
+func (b *budgetLine) Link(msg string, v xoptrace.Trace) {
+	b.size += int64(len(msg)) + 55
+	b.line.Link(msg, v)
+	b.done()
+}
+
+func (b *budgetLine) Any(k xopat.K, v xopbase.ModelArg) {
+	b.size += int64(len(k)) + estimateSize(v)
+	b.line.Any(k, v)
+}
+
+func (b *budgetLine) Bool(k xopat.K, v bool) {
+	b.size += int64(len(k)) + estimateSize(v)
+	b.line.Bool(k, v)
+}
+
+func (b *budgetLine) Duration(k xopat.K, v time.Duration) {
+	b.size += int64(len(k)) + estimateSize(v)
+	b.line.Duration(k, v)
+}
+
+func (b *budgetLine) Time(k xopat.K, v time.Time) {
+	b.size += int64(len(k)) + estimateSize(v)
+	b.line.Time(k, v)
+}
+
+func (b *budgetLine) Float64(k xopat.K, v float64, dt xopbase.DataType) {
+	b.size += int64(len(k)) + estimateSize(v)
+	b.line.Float64(k, v, dt)
+}
+
+func (b *budgetLine) Int64(k xopat.K, v int64, dt xopbase.DataType) {
+	b.size += int64(len(k)) + estimateSize(v)
+	b.line.Int64(k, v, dt)
+}
+
+func (b *budgetLine) String(k xopat.K, v string, dt xopbase.DataType) {
+	b.size += int64(len(k)) + estimateSize(v)
+	b.line.String(k, v, dt)
+}
+
+func (b *budgetLine) Uint64(k xopat.K, v uint64, dt xopbase.DataType) {
+	b.size += int64(len(k)) + estimateSize(v)
+	b.line.Uint64(k, v, dt)
+}
+
+// estimateSize is a rough guess at how many bytes a value will
+// take when encoded.
+func estimateSize(v interface{}) int64 {
+	switch t := v.(type) {
+	case string:
+		return int64(len(t))
+	case bool:
+		return 5
+	case time.Time:
+		return 30
+	case xopbase.SimpleTable:
+		size := estimateSize(t.Header())
+		for _, row := range t.Rows() {
+			size += estimateSize(row)
+		}
+		return size
+	case []string:
+		size := int64(len(t))
+		for _, s := range t {
+			size += int64(len(s))
+		}
+		return size
+	case xopbase.ModelArg:
+		if t.Encoded != nil {
+			return int64(len(t.Encoded))
+		}
+		switch m := t.Model.(type) {
+		case string:
+			return int64(len(m))
+		case []byte:
+			return int64(len(m))
+		}
+		return 100
+	default:
+		return 10
+	}
+}
@@ -0,0 +1,110 @@
+package xop_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/xoplog/xop-go"
+	"github.com/xoplog/xop-go/xopat"
+	"github.com/xoplog/xop-go/xopbase"
+	"github.com/xoplog/xop-go/xopconst"
+	"github.com/xoplog/xop-go/xopnum"
+	"github.com/xoplog/xop-go/xoprecorder"
+	"github.com/xoplog/xop-go/xoptest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func budgetRequest(t *testing.T, tLog *xoptest.Logger, lines int, bytes int, sample int) *xop.Logger {
+	return xop.NewSeed(
+		xop.WithBase(tLog),
+		xop.WithSettings(func(settings *xop.LogSettings) {
+			settings.Budget(lines, bytes, sample)
+		}),
+	).Request(t.Name())
+}
+
+func TestBudgetLines(t *testing.T) {
+	tLog := xoptest.New(t)
+	log := budgetRequest(t, tLog, 5, 0, 0)
+	step := log.Sub().Step("step")
+	for i := 0; i < 4; i++ {
+		step.Info().Int("i", i).Msg("step")
+	}
+	step.Done()
+	for i := 0; i < 3; i++ {
+		log.Info().Int("i", i).Msg("info")
+		log.Warn().Int("i", i).Msg("warn")
+	}
+	log.Done()
+
+	assert.Equal(t, 4, tLog.Recorder().CountLines(xoprecorder.MessageEquals("step")))
+	assert.Equal(t, 1, tLog.Recorder().CountLines(xoprecorder.MessageEquals("info")), "shared across spans")
+	assert.Equal(t, 0, tLog.Recorder().CountLines(xoprecorder.MessageEquals("warn")))
+	require.Len(t, tLog.Recorder().Requests, 1)
+	md := tLog.Recorder().Requests[0].SpanMetadata.Get(xopconst.LinesDropped.Key().String())
+	if assert.NotNil(t, md, "dropped recorded") {
+		assert.Equal(t, map[string]int64{"info": 2, "warn": 3}, md.Value.(xopbase.ModelArg).Model)
+	}
+}
+
+func TestBudgetBytesSampled(t *testing.T) {
+	tLog := xoptest.New(t)
+	log := budgetRequest(t, tLog, 0, 1000, 3)
+	big := strings.Repeat("x", 600)
+	log.Info().String("big", big).Msg("one")
+	log.Info().String("big", big).Msg("two")
+	for i := 0; i < 7; i++ {
+		log.Info().Msg("after")
+	}
+	log.Done()
+
+	assert.Equal(t, 1, tLog.Recorder().CountLines(xoprecorder.MessageEquals("one")))
+	assert.Equal(t, 1, tLog.Recorder().CountLines(xoprecorder.MessageEquals("two")), "under budget when started")
+	assert.Equal(t, 3, tLog.Recorder().CountLines(xoprecorder.MessageEquals("after")), "sampled")
+	md := tLog.Recorder().Requests[0].SpanMetadata.Get(xopconst.LinesDropped.Key().String())
+	if assert.NotNil(t, md, "dropped recorded") {
+		assert.Equal(t, map[string]int64{"info": 4}, md.Value.(xopbase.ModelArg).Model)
+	}
+}
+
+func TestBudgetNotExceeded(t *testing.T) {
+	tLog := xoptest.New(t)
+	log := budgetRequest(t, tLog, 5, 0, 0)
+	log.Info().Msg("info")
+	log.Done()
+	assert.Nil(t, tLog.Recorder().Requests[0].SpanMetadata.Get(xopconst.LinesDropped.Key().String()))
+}
+
+func TestBudgetUnusualLevels(t *testing.T) {
+	tLog := xoptest.New(t)
+	log := budgetRequest(t, tLog, 1, 0, 0)
+	log.Info().Msg("info")
+	log.Line(xopnum.Level(22)).Msg("above alert")
+	log.Line(xopnum.Level(-3)).Msg("negative")
+	log.Done()
+
+	assert.Equal(t, 0, tLog.Recorder().CountLines(xoprecorder.MessageEquals("above alert")))
+	md := tLog.Recorder().Requests[0].SpanMetadata.Get(xopconst.LinesDropped.Key().String())
+	if assert.NotNil(t, md, "dropped recorded") {
+		assert.Equal(t, map[string]int64{"alert": 1}, md.Value.(xopbase.ModelArg).Model)
+	}
+}
+
+func TestBudgetDroppedRedacted(t *testing.T) {
+	tLog := xoptest.New(t)
+	log := xop.NewSeed(
+		xop.WithBase(tLog),
+		xop.WithRedaction(xop.NewRedactionPolicy().Key(xopconst.LinesDropped.Key(), xopat.Secret)),
+		xop.WithSettings(func(settings *xop.LogSettings) {
+			settings.Budget(1, 0, 0)
+		}),
+	).Request(t.Name())
+	log.Info().Msg("one")
+	log.Info().Msg("two")
+	log.Done()
+
+	assert.Equal(t, 0, tLog.Recorder().CountLines(xoprecorder.MessageEquals("two")))
+	assert.Nil(t, tLog.Recorder().Requests[0].SpanMetadata.Get(xopconst.LinesDropped.Key().String()), "span metadata path")
+}
@@ -0,0 +1,54 @@
+package xop_test
+
+import (
+	"testing"
+
+	"github.com/xoplog/xop-go"
+	"github.com/xoplog/xop-go/xoptest"
+	"github.com/xoplog/xop-go/xoptrace"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaggagePropagation(t *testing.T) {
+	tLog := xoptest.New(t)
+	seed := tLog.Logger().Span().SubSeed()
+	baggage := seed.Bundle().Baggage
+	require.NoError(t, baggage.Set("tenant", "acme"))
+	log := seed.Copy(xop.WithBaggage(baggage)).Request(t.Name())
+	assert.Equal(t, "tenant=acme", log.Span().TraceBaggage().String())
+	assert.True(t, seed.Bundle().Baggage.IsZero(), "original seed not modified")
+
+	require.NoError(t, log.Span().SetBaggage("flag", "on"))
+	child := log.Sub().Fork("child")
+	v, ok := child.Span().TraceBaggage().Get("flag")
+	assert.True(t, ok)
+	assert.Equal(t, "on", v)
+	assert.Equal(t, "tenant=acme,flag=on", child.Span().Bundle().Baggage.String())
+
+	child.Span().DeleteBaggage("tenant")
+	assert.Equal(t, "flag=on", child.Span().TraceBaggage().String())
+	assert.Equal(t, "tenant=acme,flag=on", log.Span().TraceBaggage().String(), "parent not modified")
+}
+
+func TestTraceStateSeedModifiers(t *testing.T) {
+	tLog := xoptest.New(t)
+	var reported []error
+	var state xoptrace.State
+	state.SetString("rojo=00f067aa0ba902b7,congo=t61rcWkgMzE")
+	seed := tLog.Logger().Span().SubSeed(
+		xop.WithTraceState(state),
+		xop.WithConfigChanges(func(c *xop.Config) {
+			c.ErrorReporter = func(err error) { reported = append(reported, err) }
+		}),
+		xop.WithTraceStateEntry("xop", "abc"),
+		xop.WithTraceStateEntry("Invalid Key", "abc"),
+	)
+	assert.Len(t, reported, 1, "invalid key reported")
+	log := seed.Request(t.Name())
+	assert.Equal(t, "xop=abc,rojo=00f067aa0ba902b7,congo=t61rcWkgMzE", log.Span().TraceState().String())
+	child := log.Sub().Fork("child", xop.WithTraceStateEntry("congo", "new"))
+	assert.Equal(t, "congo=new,xop=abc,rojo=00f067aa0ba902b7", child.Span().TraceState().String())
+	assert.Equal(t, "xop=abc,rojo=00f067aa0ba902b7,congo=t61rcWkgMzE", log.Span().TraceState().String(), "parent not modified")
+}
@@ -3,6 +3,8 @@ package xop
 import (
 	golog "log"
 	"time"
+
+	"github.com/xoplog/xop-go/xopnum"
 )
 
 type Config struct {
@@ -17,6 +19,31 @@ type Config struct {
 	// there is a an error, we don't want to completely
 	// ignore it.
 	ErrorReporter func(error)
+
+	// Sampler, if set, decides if new root traces are sampled.
+	// See Sampler.
+	Sampler Sampler
+
+	// UnsampledBoring causes requests that are part of an unsampled
+	// trace to be marked Boring(). Base loggers that honor Boring
+	// will then drop or mark them unless something is logged at
+	// the Error or Alert level.
+	UnsampledBoring bool
+
+	// UnsampledMinLevel, if set, raises the minimum log level for
+	// requests that are part of an unsampled trace. Reactive
+	// functions, such as VerbosityFromBaggage, run afterwards and
+	// may lower it again.
+	UnsampledMinLevel xopnum.Level
+
+	// Redaction, if set, is applied to span metadata, line
+	// attributes, prefilled attributes, and models.
+	// See RedactionPolicy.
+	Redaction *RedactionPolicy
+
+	// Scanner, if set, looks for secrets and PII in lines
+	// and prefilled data. See Scanner.
+	Scanner *Scanner
 }
 
 // LogLinkPrefix can be set during init(). It should not be modified
@@ -45,6 +72,45 @@ func WithB3(b bool) SeedModifier {
 	}
 }
 
+// WithSampler sets Config.Sampler which is used to decide if
+// new root traces are sampled.
+func WithSampler(sampler Sampler) SeedModifier {
+	return func(s *Seed) {
+		s.config.Sampler = sampler
+	}
+}
+
+// WithUnsampledBoring sets Config.UnsampledBoring: requests
+// that are part of an unsampled trace are marked Boring().
+func WithUnsampledBoring(b bool) SeedModifier {
+	return func(s *Seed) {
+		s.config.UnsampledBoring = b
+	}
+}
+
+// WithUnsampledMinLevel sets Config.UnsampledMinLevel: requests
+// that are part of an unsampled trace have their minimum log level
+// raised to at least level.
+func WithUnsampledMinLevel(level xopnum.Level) SeedModifier {
+	return func(s *Seed) {
+		s.config.UnsampledMinLevel = level
+	}
+}
+
+// WithRedaction sets Config.Redaction
+func WithRedaction(policy *RedactionPolicy) SeedModifier {
+	return func(s *Seed) {
+		s.config.Redaction = policy
+	}
+}
+
+// WithScanner sets Config.Scanner
+func WithScanner(scanner *Scanner) SeedModifier {
+	return func(s *Seed) {
+		s.config.Scanner = scanner
+	}
+}
+
 func WithConfig(config Config) SeedModifier {
 	return func(s *Seed) {
 		s.config = config
@@ -73,13 +73,8 @@ func CustomFromContext(getLogFromContext func(context.Context) *Logger, adjustSe
 //	package foo
 //	var adjustLogger = xop.LevelAdjuster()
 func LevelAdjuster(opts ...AdjusterOption) func(*Logger) *Logger {
-	level := adjustConfig(opts)
-	if level == 0 {
-		return func(logger *Logger) *Logger { return logger }
-	}
-	return func(logger *Logger) *Logger {
-		return logger.Sub().MinLevel(level).Logger()
-	}
+	adjuster := adjustConfig(opts)
+	return adjuster.adjust
 }
 
 // ContextLevelAdjuster returns a function that gets a logger from
@@ -94,16 +89,18 @@ func LevelAdjuster(opts ...AdjusterOption) func(*Logger) *Logger {
 // the passed level is not zero.
 // (3) The level that the logger already has.
 //
+// The level found above is registered with a LevelRegistry (DefaultLevelRegistry
+// unless WithRegistry is used) and can be overridden at runtime by package, by
+// namespace, or by request name.  Overrides are checked each time a logger is
+// obtained and each time Sub() is called on a logger that was obtained.
+//
 //	package foo
 //	var getLogger = xop.AdjustedLevelLoger(xop.FromContextOrPanic)
 func ContextLevelAdjuster(getLogFromContext func(context.Context) *Logger, opts ...AdjusterOption) func(context.Context) *Logger {
-	level := adjustConfig(opts)
-	if level == 0 {
-		return getLogFromContext
+	adjuster := adjustConfig(opts)
+	return func(ctx context.Context) *Logger {
+		return adjuster.adjust(getLogFromContext(ctx))
 	}
-	return CustomFromContext(getLogFromContext, func(sub *Sub) *Sub {
-		return sub.MinLevel(level)
-	})
 }
 
 // WithPackage overrides how the package name is found.  The
@@ -147,17 +144,49 @@ func WithSkippedFrames(additionalFramesToSkip int) AdjusterOption {
 	}
 }
 
+// WithRegistry overrides the LevelRegistry used by LevelAdjuster and
+// ContextLevelAdjuster.  The default is DefaultLevelRegistry.
+func WithRegistry(registry *LevelRegistry) AdjusterOption {
+	return func(o *adjustOptions) {
+		o.registry = registry
+	}
+}
+
 type AdjusterOption func(*adjustOptions)
 
 type adjustOptions struct {
-	pkg   string
-	env   string
-	level xopnum.Level
-	skip  int
+	pkg      string
+	env      string
+	level    xopnum.Level
+	skip     int
+	registry *LevelRegistry
+}
+
+type levelAdjuster struct {
+	registry *LevelRegistry
+	pkg      string
+	initial  xopnum.Level
 }
 
-func adjustConfig(opts []AdjusterOption) xopnum.Level {
-	var options adjustOptions
+func (a *levelAdjuster) level(logger *Logger) xopnum.Level {
+	return a.registry.level(a.pkg, logger, a.initial)
+}
+
+func (a *levelAdjuster) adjust(logger *Logger) *Logger {
+	level := a.level(logger)
+	if level == 0 || (level == logger.settings.minimumLogLevel && logger.settings.levelAdjuster == a) {
+		return logger
+	}
+	sub := logger.Sub()
+	sub.settings.minimumLogLevel = level
+	sub.settings.levelAdjuster = a
+	return sub.Logger()
+}
+
+func adjustConfig(opts []AdjusterOption) *levelAdjuster {
+	options := adjustOptions{
+		registry: DefaultLevelRegistry,
+	}
 	for _, f := range opts {
 		f(&options)
 	}
@@ -199,5 +228,14 @@ func adjustConfig(opts []AdjusterOption) xopnum.Level {
 		}
 	}
 
-	return options.level
+	key := options.pkg
+	if key == "" {
+		key = options.env
+	}
+	options.registry.register(key, options.level)
+	return &levelAdjuster{
+		registry: options.registry,
+		pkg:      key,
+		initial:  options.level,
+	}
 }
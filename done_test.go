@@ -0,0 +1,40 @@
+package xop_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xoplog/xop-go"
+	"github.com/xoplog/xop-go/xoprecorder"
+	"github.com/xoplog/xop-go/xoptest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// A Sub().Logger() of a request shares the request's span so
+// it must not become a dependent of itself.
+func TestRequestSubLoggerAfterFlush(t *testing.T) {
+	tLog := xoptest.New(t)
+	request := tLog.Logger()
+	sub := request.Sub().Logger()
+	request.Info().Msg("before")
+	request.Flush()
+	sub.Info().Msg("after")
+	request.Done()
+
+	assert.Equal(t, 1, tLog.Recorder().CountLines(xoprecorder.MessageEquals("after")))
+}
+
+// Lines are recycled within a span and must use the settings of the
+// logger that starts them.
+func TestRecycledLineSettings(t *testing.T) {
+	tLog := xoptest.New(t)
+	request := tLog.Logger()
+	request.Info().Msg("recycled")
+	sub := request.Sub().RateLimit(1, time.Hour, xop.RateLimitByMessage).Logger()
+	sub.Info().Msg("repeated")
+	sub.Info().Msg("repeated")
+	request.Done()
+
+	assert.Equal(t, 1, tLog.Recorder().CountLines(xoprecorder.MessageEquals("repeated")))
+}
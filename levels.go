@@ -0,0 +1,278 @@
+package xop
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/xoplog/xop-go/xopnum"
+
+	"github.com/pkg/errors"
+)
+
+// LevelKind is which attribute of a logger a LevelRegistry entry
+// matches.
+type LevelKind string
+
+const (
+	// LevelByPackage matches the package name that was used to create
+	// a LevelAdjuster or ContextLevelAdjuster.
+	LevelByPackage LevelKind = "package"
+	// LevelByNamespace matches the namespace of the request (see WithNamespace).
+	LevelByNamespace LevelKind = "namespace"
+	// LevelByRequest matches the name/description of the request.
+	LevelByRequest LevelKind = "request"
+)
+
+// LevelRegistry holds minimum log levels that can be changed at
+// runtime.  Loggers obtained through a LevelAdjuster or
+// ContextLevelAdjuster consult the registry each time they are
+// obtained and again each time Sub() is called on them.
+//
+// When more than one entry matches a logger, the most specific
+// wins: request name, then package, then namespace.  Any matching
+// entry takes precedence over the level the adjuster started with.
+//
+// LevelRegistry is thread-safe.
+type LevelRegistry struct {
+	lock     sync.RWMutex
+	defaults map[string]xopnum.Level
+	levels   map[LevelKind]map[string]xopnum.Level
+	debugAll int32
+}
+
+// DefaultLevelRegistry is used by LevelAdjuster and
+// ContextLevelAdjuster unless WithRegistry is used.
+var DefaultLevelRegistry = NewLevelRegistry()
+
+func NewLevelRegistry() *LevelRegistry {
+	return &LevelRegistry{
+		defaults: make(map[string]xopnum.Level),
+		levels: map[LevelKind]map[string]xopnum.Level{
+			LevelByPackage:   make(map[string]xopnum.Level),
+			LevelByNamespace: make(map[string]xopnum.Level),
+			LevelByRequest:   make(map[string]xopnum.Level),
+		},
+	}
+}
+
+// Set overrides the level for the named package, namespace, or request.
+// Setting a level of zero removes the override.
+func (r *LevelRegistry) Set(kind LevelKind, name string, level xopnum.Level) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	m, ok := r.levels[kind]
+	if !ok {
+		return errors.Errorf("unknown level kind '%s'", kind)
+	}
+	if level == 0 {
+		delete(m, name)
+	} else {
+		m[name] = level
+	}
+	return nil
+}
+
+// Get returns the override for the named package, namespace, or
+// request.  For packages without an override, it returns the level
+// that the package started with.
+func (r *LevelRegistry) Get(kind LevelKind, name string) xopnum.Level {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	if level, ok := r.levels[kind][name]; ok {
+		return level
+	}
+	if kind == LevelByPackage {
+		return r.defaults[name]
+	}
+	return 0
+}
+
+// SetDebug turns on (or off) Debug level for all loggers that
+// use the registry.
+func (r *LevelRegistry) SetDebug(on bool) {
+	if on {
+		atomic.StoreInt32(&r.debugAll, 1)
+	} else {
+		atomic.StoreInt32(&r.debugAll, 0)
+	}
+}
+
+// ToggleDebugOnSignal flips SetDebug each time one of the signals is
+// received. Typically it would be used with syscall.SIGUSR1.  Call the
+// returned function to stop listening.
+func (r *LevelRegistry) ToggleDebugOnSignal(signals ...os.Signal) (stop func()) {
+	c := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(c, signals...)
+	go func() {
+		for {
+			select {
+			case <-c:
+				r.SetDebug(atomic.LoadInt32(&r.debugAll) == 0)
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(c)
+			close(done)
+		})
+	}
+}
+
+func (r *LevelRegistry) register(pkg string, level xopnum.Level) {
+	if pkg == "" {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.defaults[pkg] = level
+}
+
+func (r *LevelRegistry) level(pkg string, logger *Logger, initial xopnum.Level) xopnum.Level {
+	level := initial
+	func() {
+		r.lock.RLock()
+		defer r.lock.RUnlock()
+		if l, ok := r.levels[LevelByRequest][logger.request.span.description]; ok {
+			level = l
+		} else if l, ok := r.levels[LevelByPackage][pkg]; ok {
+			level = l
+		} else if l, ok := r.levels[LevelByNamespace][logger.request.span.seed.sourceInfo.Namespace]; ok {
+			level = l
+		}
+	}()
+	if atomic.LoadInt32(&r.debugAll) == 1 && (level == 0 || level > xopnum.DebugLevel) {
+		return xopnum.DebugLevel
+	}
+	return level
+}
+
+// LevelSettings is the JSON representation of a LevelRegistry used
+// by LevelRegistry.ServeHTTP.  Levels are level names or numbers.
+type LevelSettings struct {
+	Debug     *bool             `json:"debug,omitempty"`
+	Package   map[string]string `json:"package,omitempty"`
+	Namespace map[string]string `json:"namespace,omitempty"`
+	Request   map[string]string `json:"request,omitempty"`
+}
+
+func (s LevelSettings) byKind() map[LevelKind]map[string]string {
+	return map[LevelKind]map[string]string{
+		LevelByPackage:   s.Package,
+		LevelByNamespace: s.Namespace,
+		LevelByRequest:   s.Request,
+	}
+}
+
+// Settings returns the current levels, including the starting
+// level of every package that has registered an adjuster.
+func (r *LevelRegistry) Settings() LevelSettings {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	debug := atomic.LoadInt32(&r.debugAll) == 1
+	settings := LevelSettings{
+		Debug:     &debug,
+		Package:   make(map[string]string),
+		Namespace: make(map[string]string),
+		Request:   make(map[string]string),
+	}
+	for pkg, level := range r.defaults {
+		if level != 0 {
+			settings.Package[pkg] = level.String()
+		} else {
+			settings.Package[pkg] = ""
+		}
+	}
+	for kind, m := range settings.byKind() {
+		for name, level := range r.levels[kind] {
+			m[name] = level.String()
+		}
+	}
+	return settings
+}
+
+// ServeHTTP makes LevelRegistry an http.Handler.  GET returns the
+// current Settings() as JSON.  PUT (or POST) accepts LevelSettings as
+// JSON and applies each entry: an empty level removes the override.
+// Entries not mentioned are left alone.
+//
+//	http.Handle("/debug/xoplevels", xop.DefaultLevelRegistry)
+//
+//	curl -X PUT -d '{"package":{"mypkg":"debug"}}' localhost:8080/debug/xoplevels
+func (r *LevelRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+	case http.MethodPut, http.MethodPost:
+		var settings LevelSettings
+		err := json.NewDecoder(req.Body).Decode(&settings)
+		if err == nil {
+			err = r.apply(settings)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	enc, _ := json.Marshal(r.Settings())
+	_, _ = w.Write(enc)
+}
+
+func (r *LevelRegistry) apply(settings LevelSettings) error {
+	type change struct {
+		kind  LevelKind
+		name  string
+		level xopnum.Level
+	}
+	var changes []change
+	// validate everything before changing anything
+	for kind, m := range settings.byKind() {
+		for name, s := range m {
+			level, err := parseLevel(s)
+			if err != nil {
+				return errors.Wrapf(err, "%s '%s'", kind, name)
+			}
+			changes = append(changes, change{kind: kind, name: name, level: level})
+		}
+	}
+	for _, c := range changes {
+		_ = r.Set(c.kind, c.name, c.level)
+	}
+	if settings.Debug != nil {
+		r.SetDebug(*settings.Debug)
+	}
+	return nil
+}
+
+// parseLevel accepts a level name or a number between
+// xopnum.TraceLevel and xopnum.MaxLevel. The empty string is
+// level zero.
+func parseLevel(s string) (xopnum.Level, error) {
+	if s == "" {
+		return 0, nil
+	}
+	level, err := xopnum.LevelString(s)
+	if err == nil {
+		return level, nil
+	}
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if i < int64(xopnum.TraceLevel) || i > int64(xopnum.MaxLevel) {
+			return 0, errors.Errorf("level %d out of range %d-%d", i, xopnum.TraceLevel, xopnum.MaxLevel)
+		}
+		return xopnum.Level(i), nil
+	}
+	return 0, errors.Errorf("invalid level '%s'", s)
+}
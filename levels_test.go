@@ -0,0 +1,100 @@
+package xop_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/xoplog/xop-go"
+	"github.com/xoplog/xop-go/xopnum"
+	"github.com/xoplog/xop-go/xoptest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevelRegistry(t *testing.T) {
+	registry := xop.NewLevelRegistry()
+	adjust := xop.LevelAdjuster(xop.WithPackage("levelpkg"), xop.WithRegistry(registry), xop.WithDefault(xopnum.InfoLevel))
+	other := xop.LevelAdjuster(xop.WithPackage("otherpkg"), xop.WithRegistry(registry))
+	tLog := xoptest.New(t)
+	log := xop.NewSeed(xop.WithBase(tLog), xop.WithNamespace("levelns")).Request("levelreq")
+
+	assert.Equal(t, xopnum.InfoLevel, adjust(log).Settings().GetMinLevel(), "default")
+	assert.Equal(t, log, other(log), "unchanged without a level")
+
+	long := adjust(log)
+	require.NoError(t, registry.Set(xop.LevelByNamespace, "levelns", xopnum.WarnLevel))
+	assert.Equal(t, xopnum.WarnLevel, adjust(log).Settings().GetMinLevel(), "namespace beats startup level")
+	assert.Equal(t, xopnum.WarnLevel, other(log).Settings().GetMinLevel(), "namespace")
+
+	require.NoError(t, registry.Set(xop.LevelByPackage, "levelpkg", xopnum.DebugLevel))
+	assert.Equal(t, xopnum.DebugLevel, adjust(log).Settings().GetMinLevel(), "package beats namespace")
+	assert.Equal(t, xopnum.InfoLevel, long.Settings().GetMinLevel(), "existing logger unchanged")
+	assert.Equal(t, xopnum.DebugLevel, long.Sub().Logger().Settings().GetMinLevel(), "existing logger Sub")
+
+	require.NoError(t, registry.Set(xop.LevelByRequest, "levelreq", xopnum.ErrorLevel))
+	assert.Equal(t, xopnum.ErrorLevel, adjust(log).Settings().GetMinLevel(), "request beats package")
+
+	registry.SetDebug(true)
+	assert.Equal(t, xopnum.DebugLevel, adjust(log).Settings().GetMinLevel(), "debug")
+	assert.Equal(t, xopnum.DebugLevel, other(log).Settings().GetMinLevel(), "debug other")
+	registry.SetDebug(false)
+
+	assert.Error(t, registry.Set("bogus", "x", xopnum.InfoLevel))
+}
+
+func TestLevelAdjusterExplicitMinLevel(t *testing.T) {
+	registry := xop.NewLevelRegistry()
+	adjust := xop.LevelAdjuster(xop.WithPackage("explicitpkg"), xop.WithRegistry(registry), xop.WithDefault(xopnum.InfoLevel))
+	tLog := xoptest.New(t)
+	log := xop.NewSeed(xop.WithBase(tLog)).Request(t.Name())
+
+	explicit := adjust(log).Sub().MinLevel(xopnum.ErrorLevel).Logger()
+	assert.Equal(t, xopnum.ErrorLevel, explicit.Sub().Logger().Settings().GetMinLevel(), "Sub")
+	assert.Equal(t, xopnum.ErrorLevel, explicit.Sub().Fork("fork").Settings().GetMinLevel(), "Fork")
+	require.NoError(t, registry.Set(xop.LevelByPackage, "explicitpkg", xopnum.DebugLevel))
+	assert.Equal(t, xopnum.ErrorLevel, explicit.Sub().Logger().Settings().GetMinLevel(), "registry change")
+	assert.Equal(t, xopnum.DebugLevel, adjust(explicit).Settings().GetMinLevel(), "adjust again")
+}
+
+func TestLevelRegistryHTTP(t *testing.T) {
+	registry := xop.NewLevelRegistry()
+	adjust := xop.LevelAdjuster(xop.WithPackage("httppkg"), xop.WithRegistry(registry), xop.WithDefault(xopnum.WarnLevel))
+	log := xoptest.New(t).Logger()
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), `"httppkg":"warn"`)
+
+	put := func(s string) int {
+		req, err := http.NewRequest(http.MethodPut, server.URL, strings.NewReader(s))
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	assert.Equal(t, http.StatusOK, put(`{"package":{"httppkg":"debug"}}`))
+	assert.Equal(t, xopnum.DebugLevel, adjust(log).Settings().GetMinLevel(), "by name")
+	assert.Equal(t, http.StatusOK, put(`{"package":{"httppkg":"17"}}`))
+	assert.Equal(t, xopnum.ErrorLevel, adjust(log).Settings().GetMinLevel(), "by number")
+	assert.Equal(t, http.StatusBadRequest, put(`{"package":{"httppkg":"loud"}}`))
+	assert.Equal(t, xopnum.ErrorLevel, adjust(log).Settings().GetMinLevel(), "invalid ignored")
+	assert.Equal(t, http.StatusBadRequest, put(`{"package":{"httppkg":"999"}}`))
+	assert.Equal(t, http.StatusBadRequest, put(`{"package":{"httppkg":"1"}}`))
+	assert.Equal(t, http.StatusBadRequest, put(`{"package":{"httppkg":"-5"}}`))
+	assert.Equal(t, xopnum.ErrorLevel, adjust(log).Settings().GetMinLevel(), "out of range ignored")
+	assert.Equal(t, http.StatusOK, put(`{"package":{"httppkg":""}}`))
+	assert.Equal(t, xopnum.WarnLevel, adjust(log).Settings().GetMinLevel(), "reset")
+	assert.Equal(t, http.StatusOK, put(`{"debug":true}`))
+	assert.Equal(t, xopnum.DebugLevel, adjust(log).Settings().GetMinLevel(), "debug")
+}
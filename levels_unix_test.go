@@ -0,0 +1,33 @@
+//go:build !windows
+
+package xop_test
+
+import (
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/xoplog/xop-go"
+	"github.com/xoplog/xop-go/xopnum"
+	"github.com/xoplog/xop-go/xoptest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevelRegistrySignal(t *testing.T) {
+	registry := xop.NewLevelRegistry()
+	adjust := xop.LevelAdjuster(xop.WithPackage("sigpkg"), xop.WithRegistry(registry), xop.WithDefault(xopnum.WarnLevel))
+	log := xoptest.New(t).Logger()
+	stop := registry.ToggleDebugOnSignal(syscall.SIGUSR1)
+	defer stop()
+
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGUSR1))
+	assert.Eventually(t, func() bool {
+		return adjust(log).Settings().GetMinLevel() == xopnum.DebugLevel
+	}, time.Second, time.Millisecond, "on")
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGUSR1))
+	assert.Eventually(t, func() bool {
+		return adjust(log).Settings().GetMinLevel() == xopnum.WarnLevel
+	}, time.Second, time.Millisecond, "off")
+}
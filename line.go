@@ -14,7 +14,8 @@ import (
 
 type Key = xopat.K
 
-// AnyWithoutRedaction
+// AnyWithoutRedaction is like Any but never copies v so v must not be
+// modified after this call. A RedactionPolicy still applies.
 // The return value must be consumed for the line to be logged.
 func (line *Line) AnyWithoutRedaction(k xopat.K, v interface{}) *Line {
 	line.line.Any(k, xopbase.ModelArg{Model: v})
@@ -29,10 +30,6 @@ func (line *Line) Any(k xopat.K, v interface{}) *Line {
 	if line.skip {
 		return line
 	}
-	if line.logger.settings.redactAny != nil {
-		line.logger.settings.redactAny(line.line, k, v, !line.logger.span.referencesKept)
-		return line
-	}
 	if line.logger.span.referencesKept {
 		// TODO: make copy function configurable
 		v = deepcopy.Copy(v)
@@ -62,47 +59,34 @@ func (line *Line) Enum(k *xopat.EnumAttribute, v xopat.Enum) *Line {
 }
 
 // Error adds a key/value pair to the current log line.
-// The default logging of an error is simply err.Error() to change
-// that, set a redaction function.
+// The error is logged as err.Error().
 // The return value must be consumed for the line to be logged.
 func (line *Line) Error(k xopat.K, v error) *Line {
 	if line.skip {
 		return line
 	}
-	if line.logger.settings.redactError != nil {
-		line.logger.settings.redactError(line.line, k, v)
-		return line
-	}
 	line.line.String(k, v.Error(), xopbase.ErrorDataType)
 	return line
 }
 
 // Stringer adds a key/value pair to the current log line.
-// The string can be redacted if a redaction function has been set.
+// The string can be redacted by a RedactionPolicy, see WithRedaction.
 // The return value must be consumed for the line to be logged.
 func (line *Line) Stringer(k xopat.K, v fmt.Stringer) *Line {
 	if line.skip {
 		return line
 	}
-	if line.logger.settings.redactString != nil {
-		line.logger.settings.redactString(line.line, k, v.String())
-		return line
-	}
 	line.line.String(k, v.String(), xopbase.StringerDataType)
 	return line
 }
 
 // String adds a key/value pair to the current log line.
-// The string can be redacted if a redaction function has been set.
+// The string can be redacted by a RedactionPolicy, see WithRedaction.
 // The return value must be consumed for the line to be logged.
 func (line *Line) String(k xopat.K, v string) *Line {
 	if line.skip {
 		return line
 	}
-	if line.logger.settings.redactString != nil {
-		line.logger.settings.redactString(line.line, k, v)
-		return line
-	}
 	line.line.String(k, v, xopbase.StringDataType)
 	return line
 }
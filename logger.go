@@ -37,13 +37,16 @@ type span struct {
 	base             xopbase.Span //nolint:structcheck // false report
 	linePool         sync.Pool    //nolint:structcheck // false report
 	boring           int32        // 0 = boring
+	markedBoring     int32        // requests only: 1 = Boring() honored by base loggers, 2 = not honored
 	buffered         bool         //nolint:structcheck // false report
 	description      string
 	stepCounter      int32 //nolint:structcheck // false report
 	forkCounter      int32 //nolint:structcheck // false report
 	detached         bool
 	dependentLock    sync.Mutex
+	bundleLock       sync.Mutex // protects seed.traceBundle after the span starts
 	activeDependents map[int32]*Logger
+	rateLimiter      *rateLimiter // protected by dependentLock, created when needed
 	doneCount        int32
 	knownActive      int32
 	logNumber        int32
@@ -61,6 +64,13 @@ type shared struct {
 	LogCount           int32
 	ActiveDetached     map[int32]*Logger
 	WaitingForDetached bool // true only when request is Done but is not yet flushed due to detached
+	HeldLock           sync.Mutex
+	Held               []*heldLine // lines held for tail-based retention
+	HeldReleased       bool        // true once the request is not boring: lines are no longer held
+	BudgetLines        int64       // lines counted against LogSettings.Budget
+	BudgetBytes        int64       // estimated bytes counted against LogSettings.Budget
+	BudgetOver         int64       // lines beyond the budget, for sampling
+	BudgetDropped      [xopnum.MaxLevel + 1]int64
 }
 
 type singleAllocRequest struct {
@@ -111,6 +121,9 @@ func (seed Seed) request(descriptionOrName string, now time.Time) *Logger {
 		logger.shared.FlushTimer.Stop()
 		logger.shared.FlushActive = 0
 	}
+	if seed.config.UnsampledBoring && !isSampled(logger.span.seed.traceBundle.Trace) {
+		logger.Boring()
+	}
 	return logger
 }
 
@@ -241,7 +254,9 @@ func (old *Logger) newChildLog(seed Seed, description string, detached bool) *Lo
 }
 
 func (logger *Logger) addMyselfAsDependent() bool {
-	if logger == logger.request {
+	if logger.span == logger.request.span {
+		// the request, or a Sub().Logger() of the request: it
+		// cannot be a dependent of itself
 		return false
 	}
 	if logger.span.detached {
@@ -320,6 +335,10 @@ func (logger *Logger) Done() {
 func (logger *Logger) recursiveDone(done bool, now time.Time) (count int32) {
 	debugPrint("recursive done,", done, ",", logger.span.description, logger.span.logNumber)
 	if done {
+		logger.flushSuppressed()
+		if logger.span == logger.request.span {
+			logger.reportBudget()
+		}
 		atomic.StoreInt32(&logger.span.knownActive, 0)
 		count = atomic.AddInt32(&logger.span.doneCount, 1)
 		logger.span.base.Done(time.Now(), true)
@@ -364,6 +383,7 @@ func (logger *Logger) done(explicit bool, now time.Time) {
 			return false
 		}() {
 			debugPrint("request was waiting, now we can flush")
+			logger.shared.discardHeld()
 			logger.request.flush()
 		}
 		debugPrint("we're detached, finished done")
@@ -385,6 +405,7 @@ func (logger *Logger) done(explicit bool, now time.Time) {
 			return true
 		}() {
 			debugPrint("...and we're flushing")
+			logger.shared.discardHeld()
 			logger.request.flush()
 			debugPrint("...done flushing")
 		}
@@ -471,12 +492,19 @@ func (logger *Logger) detachedDone(now time.Time) {
 
 // Marks this request as boring.  Any logger at the Alert or
 // Error level will mark this request as not boring.
+//
+// Buffered base loggers will not output boring requests. Other
+// base loggers will mark them with the xopconst.Boring attribute.
 func (logger *Logger) Boring() {
 	requestBoring := atomic.LoadInt32(&logger.request.span.boring)
 	if requestBoring != 0 {
 		return
 	}
-	logger.request.span.base.Boring(true)
+	if logger.request.span.base.Boring(true) {
+		atomic.StoreInt32(&logger.request.span.markedBoring, 1)
+	} else {
+		atomic.StoreInt32(&logger.request.span.markedBoring, 2)
+	}
 	// There is chance that in the time we were sending that
 	// boring=true, the the request became un-boring. If that
 	// happened, we can't tell if we're currently marked as
@@ -489,13 +517,34 @@ func (logger *Logger) Boring() {
 	logger.hasActivity(true)
 }
 
+// NotBoring marks this span and its request as not boring, just
+// as logging at the Alert or Error level does. Lines held due to
+// TailRetention are released.
+func (logger *Logger) NotBoring() {
+	logger.notBoring()
+}
+
+// IsBoring returns true if Boring() was called on the request, the
+// base loggers honored it, and nothing has since marked the request
+// as not boring.
+func (logger *Logger) IsBoring() bool {
+	return atomic.LoadInt32(&logger.request.span.boring) == 0 &&
+		atomic.LoadInt32(&logger.request.span.markedBoring) == 1
+}
+
 func (logger *Logger) notBoring() {
 	spanBoring := atomic.AddInt32(&logger.span.boring, 1)
 	if spanBoring == 1 {
-		logger.span.base.Boring(false)
+		if logger.span != logger.request.span {
+			logger.span.base.Boring(false)
+		}
 		requestBoring := atomic.AddInt32(&logger.request.span.boring, 1)
-		if requestBoring == 1 {
-			logger.request.span.base.Boring(false)
+		// For the request itself, span and request.span are the same
+		if requestBoring == 1 || logger.span == logger.request.span {
+			if atomic.LoadInt32(&logger.request.span.markedBoring) != 0 {
+				logger.request.span.base.Boring(false)
+			}
+			logger.shared.releaseHeld()
 		}
 		logger.hasActivity(true)
 	}
@@ -510,11 +559,13 @@ func (logger *Logger) notBoring() {
 // after Msg() probably won't panic, but will definitely open the
 // door to confusing inconsistent logs and race conditions.
 type Line struct {
-	logger *Logger
-	line   xopbase.Line
-	pc     []uintptr
-	stack  []runtime.Frame
-	skip   bool
+	logger    *Logger
+	line      xopbase.Line
+	pc        []uintptr
+	stack     []runtime.Frame
+	skip      bool
+	level     xopnum.Level
+	panicking bool
 }
 
 const stackFramesToExclude = 4
@@ -522,12 +573,29 @@ const stackFramesToExclude = 4
 // logLine returns *Line, not Line.  Returning Line (and
 // changing all the *Line methods to Line methods) is
 // faster for some operations but overall it's slower.
-func (logger *Logger) logLine(level xopnum.Level) *Line {
+//
+// When panicking is true, the stack is captured with panicStack and
+// the line is exempt from rate limiting and the budget.
+func (logger *Logger) logLine(level xopnum.Level, panicking bool) *Line {
 	skip := level < logger.settings.minimumLogLevel
+	if !skip && !panicking && logger.settings.rateLimit > 0 && logger.settings.rateLimitBy == RateLimitByCaller {
+		var pc [1]uintptr
+		if runtime.Callers(stackFramesToExclude, pc[:]) == 1 {
+			skip = logger.rateLimited(pc[0], level)
+		}
+	}
+	// With RateLimitByMessage, lines are not sent until their message
+	// is known and the budget is checked then.
+	deferred := !skip && !panicking && logger.settings.rateLimit > 0 && logger.settings.rateLimitBy == RateLimitByMessage
+	if !skip && !deferred && !panicking && (logger.settings.budgetLines > 0 || logger.settings.budgetBytes > 0) {
+		skip = logger.overBudget(level)
+	}
 	recycled := logger.span.linePool.Get()
 	var ll *Line
 	if recycled != nil {
 		ll = recycled.(*Line)
+		// the pool is shared by all the loggers of the span
+		ll.logger = logger
 		if ll.pc != nil {
 			ll.pc = ll.pc[:0]
 			ll.stack = ll.stack[:0]
@@ -537,7 +605,9 @@ func (logger *Logger) logLine(level xopnum.Level) *Line {
 			logger: logger,
 		}
 	}
-	if !skip && logger.settings.stackFramesWanted[level] != 0 {
+	if !skip && panicking {
+		logger.panicStack(ll)
+	} else if !skip && logger.settings.stackFramesWanted[level] != 0 {
 		// collect program counters
 		if ll.pc == nil || cap(ll.pc) < logger.settings.stackFramesWanted[level] {
 			ll.pc = make([]uintptr,
@@ -568,15 +638,51 @@ func (logger *Logger) logLine(level xopnum.Level) *Line {
 			}
 		}
 	}
-	ll.skip = skip
-	if ll.skip {
+	ll.level = level
+	ll.panicking = panicking
+	switch {
+	case skip:
+		ll.skip = true
 		ll.line = xopbase.SkipLine
-	} else {
-		ll.line = logger.prefilled.Line(level, time.Now(), ll.stack)
+	case deferred:
+		ll.skip = false
+		ll.line = logger.newDeferredLine(level, time.Now(), ll.stack)
+	default:
+		ll.line, ll.skip = logger.baseLine(level, time.Now(), ll.stack)
 	}
 	return ll
 }
 
+// baseLine creates the xopbase.Line for a line that has passed the
+// level, rate, and budget checks. Lines are held for retention or
+// passed to the base loggers, and then wrapped for budgets, scanning,
+// and redaction. It returns true if the base loggers skipped the line.
+func (logger *Logger) baseLine(level xopnum.Level, ts time.Time, stack []runtime.Frame) (xopbase.Line, bool) {
+	var line xopbase.Line
+	if level < logger.settings.retainBelow && atomic.LoadInt32(&logger.request.span.boring) == 0 {
+		line = logger.newHeldLine(level, ts, stack)
+	} else {
+		line = logger.prefilled.Line(level, ts, stack)
+		// base loggers can filter too
+		if line == xopbase.SkipLine {
+			return line, true
+		}
+		if logger.settings.budgetBytes > 0 {
+			line = &budgetLine{
+				line:   line,
+				shared: logger.shared,
+			}
+		}
+	}
+	if scanner := logger.span.seed.config.Scanner; scanner != nil {
+		line = newScanLine(line, scanner)
+	}
+	if policy := logger.span.seed.config.Redaction; policy != nil {
+		line = newRedactLine(line, policy)
+	}
+	return line, false
+}
+
 // Template is an alternative to Msg() that sends a log line.  Template
 // is a string that uses "{name}" substitutions from the data already
 // sent with the line to format that data for human consumption.
@@ -595,6 +701,10 @@ func (logger *Logger) logLine(level xopnum.Level) *Line {
 //
 // Prefilled text (PrefillText()) will be prepended to the template.
 func (line *Line) Template(template string) {
+	if line.messageRateLimited(template) {
+		line.logger.span.linePool.Put(line)
+		return
+	}
 	line.line.Template(template)
 	line.logger.span.linePool.Put(line)
 	line.logger.hasActivity(true)
@@ -604,6 +714,10 @@ func (line *Line) Template(template string) {
 // is allowed.  Without calling Msg(), Template(), Msgf(), Msgs(),
 // or Link(), Linkf(), Modelf() or Model(), the log line will not be sent or output.
 func (line *Line) Msg(msg string) {
+	if line.messageRateLimited(msg) {
+		line.logger.span.linePool.Put(line)
+		return
+	}
 	line.line.Msg(msg)
 	line.logger.span.linePool.Put(line)
 	line.logger.hasActivity(true)
@@ -611,11 +725,28 @@ func (line *Line) Msg(msg string) {
 
 // Msgf sends a log line, using fmt.Sprintf()-style formatting.
 func (line *Line) Msgf(msg string, v ...interface{}) {
+	if line.messageRateLimited(msg) {
+		line.logger.span.linePool.Put(line)
+		return
+	}
 	if !line.skip {
-		line.Msg(fmt.Sprintf(msg, v...))
+		line.line.Msg(fmt.Sprintf(msg, v...))
+		line.logger.span.linePool.Put(line)
+		line.logger.hasActivity(true)
 	}
 }
 
+// messageRateLimited applies RateLimitByMessage. The key is the
+// message, template, or format string. Lines are deferred (see
+// sendDeferred) so a suppressed line never reaches the base loggers.
+func (line *Line) messageRateLimited(msg string) bool {
+	return !line.skip &&
+		!line.panicking &&
+		line.logger.settings.rateLimit > 0 &&
+		line.logger.settings.rateLimitBy == RateLimitByMessage &&
+		line.logger.rateLimited(msg, line.level)
+}
+
 // Model and Any serve similar roles: both can log an arbitrary
 // data object.  Model terminates the log line where Any adds a key/value
 // attribute to the log line.
@@ -628,11 +759,19 @@ func (line *Line) Model(obj interface{}, msg string) {
 		line.Msg("")
 		return
 	}
+	if line.messageRateLimited(msg) {
+		line.logger.span.linePool.Put(line)
+		return
+	}
+	line.model(obj, msg)
+}
+
+func (line *Line) model(obj interface{}, msg string) {
 	if line.logger.span.referencesKept {
 		// TODO: make copy function configurable
 		obj = deepcopy.Copy(obj)
 	}
-	line.ModelImmutable(obj, msg)
+	line.modelImmutable(obj, msg)
 }
 
 // ModelImmutable can be used to log something that is not going to be further modified
@@ -642,6 +781,14 @@ func (line *Line) ModelImmutable(obj interface{}, msg string) { // TODO: documen
 		line.Msg("")
 		return
 	}
+	if line.messageRateLimited(msg) {
+		line.logger.span.linePool.Put(line)
+		return
+	}
+	line.modelImmutable(obj, msg)
+}
+
+func (line *Line) modelImmutable(obj interface{}, msg string) {
 	line.line.Model(msg, xopbase.ModelArg{
 		Model: obj,
 	})
@@ -654,7 +801,11 @@ func (line *Line) Modelf(obj interface{}, msg string, v ...interface{}) { // TOD
 		line.Msg("")
 		return
 	}
-	line.Model(obj, fmt.Sprintf(msg, v...))
+	if line.messageRateLimited(msg) {
+		line.logger.span.linePool.Put(line)
+		return
+	}
+	line.model(obj, fmt.Sprintf(msg, v...))
 }
 
 func (line *Line) ModelImmutablef(obj interface{}, msg string, v ...interface{}) { // TODO: document
@@ -662,7 +813,11 @@ func (line *Line) ModelImmutablef(obj interface{}, msg string, v ...interface{})
 		line.Msg("")
 		return
 	}
-	line.ModelImmutable(obj, fmt.Sprintf(msg, v...))
+	if line.messageRateLimited(msg) {
+		line.logger.span.linePool.Put(line)
+		return
+	}
+	line.modelImmutable(obj, fmt.Sprintf(msg, v...))
 }
 
 func (line *Line) Linkf(link xoptrace.Trace, msg string, v ...interface{}) {
@@ -670,29 +825,65 @@ func (line *Line) Linkf(link xoptrace.Trace, msg string, v ...interface{}) {
 		line.Msg("")
 		return
 	}
-	line.Link(link, fmt.Sprintf(msg, v...))
+	if line.messageRateLimited(msg) {
+		line.logger.span.linePool.Put(line)
+		return
+	}
+	line.link(link, fmt.Sprintf(msg, v...))
 }
+
 func (line *Line) Link(link xoptrace.Trace, msg string) {
+	if line.messageRateLimited(msg) {
+		line.logger.span.linePool.Put(line)
+		return
+	}
+	line.link(link, msg)
+}
+
+func (line *Line) link(link xoptrace.Trace, msg string) {
 	line.line.Link(msg, link)
 	line.logger.span.linePool.Put(line)
 	line.logger.hasActivity(true)
 }
 
+// Table sends a log line with tabular data. Use it instead of Model()
+// for query results, comparison matrices, and the like: base loggers
+// can present tables as tables. The table is read before Table returns
+// so it may be modified afterwards.
+func (line *Line) Table(t xopbase.SimpleTable, msg string) {
+	if line.messageRateLimited(msg) {
+		line.logger.span.linePool.Put(line)
+		return
+	}
+	line.line.Table(msg, t)
+	line.logger.span.linePool.Put(line)
+	line.logger.hasActivity(true)
+}
+
 // Line starts a log line at the specified log level.  If the log level
 // is below the minimum log level, the line will be discarded.
-func (logger *Logger) Line(level xopnum.Level) *Line { return logger.logLine(level) }
+func (logger *Logger) Line(level xopnum.Level) *Line { return logger.logLine(level, false) }
 func (logger *Logger) Debug() *Line                  { return logger.Line(xopnum.DebugLevel) }
 func (logger *Logger) Trace() *Line                  { return logger.Line(xopnum.TraceLevel) }
 func (logger *Logger) Log() *Line                    { return logger.Line(xopnum.LogLevel) }
 func (logger *Logger) Info() *Line                   { return logger.Line(xopnum.InfoLevel) }
 func (logger *Logger) Warn() *Line                   { return logger.Line(xopnum.WarnLevel) }
 func (logger *Logger) Error() *Line {
-	logger.notBoring()
-	return logger.Line(xopnum.ErrorLevel)
+	return logger.Line(xopnum.ErrorLevel).notBoring()
 }
 func (logger *Logger) Alert() *Line {
-	logger.notBoring()
-	return logger.Line(xopnum.AlertLevel)
+	return logger.Line(xopnum.AlertLevel).notBoring()
+}
+
+// notBoring marks the request as not boring. For lines that are
+// deferred for RateLimitByMessage, that waits until the line is sent.
+func (line *Line) notBoring() *Line {
+	if h, ok := line.line.(*heldLine); ok && h.deferred {
+		h.notBoring = true
+	} else {
+		line.logger.notBoring()
+	}
+	return line
 }
 
 func (line *Line) Msgs(v ...interface{}) { line.Msg(fmt.Sprint(v...)) }
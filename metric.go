@@ -0,0 +1,65 @@
+package xop
+
+import (
+	"time"
+
+	"github.com/xoplog/xop-go/xopat"
+)
+
+// Counter is used to add to a counter metric that is
+// associated with a Span.
+type Counter struct {
+	span *Span
+	k    *xopat.CounterAttribute
+}
+
+// Gauge is used to set the value of a gauge metric that is
+// associated with a Span.
+type Gauge struct {
+	span *Span
+	k    *xopat.GaugeAttribute
+}
+
+// Histogram is used to record observations for a histogram metric
+// that is associated with a Span.
+type Histogram struct {
+	span *Span
+	k    *xopat.HistogramAttribute
+}
+
+// Counter returns a Counter that records to the current Span.
+// The returned value may be kept and re-used.
+func (span *Span) Counter(k *xopat.CounterAttribute) Counter {
+	return Counter{span: span, k: k}
+}
+
+// Gauge returns a Gauge that records to the current Span.
+// The returned value may be kept and re-used.
+func (span *Span) Gauge(k *xopat.GaugeAttribute) Gauge {
+	return Gauge{span: span, k: k}
+}
+
+// Histogram returns a Histogram that records to the current Span.
+// The returned value may be kept and re-used.
+func (span *Span) Histogram(k *xopat.HistogramAttribute) Histogram {
+	return Histogram{span: span, k: k}
+}
+
+// Add increments the counter. Counters are not expected
+// to go down so v should not be negative.
+func (c Counter) Add(v float64) {
+	c.span.base.Metric(&c.k.MetricAttribute, v, time.Now())
+	c.span.eft()
+}
+
+// Set records the current value of the gauge.
+func (g Gauge) Set(v float64) {
+	g.span.base.Metric(&g.k.MetricAttribute, v, time.Now())
+	g.span.eft()
+}
+
+// Observe records one observation for the histogram.
+func (h Histogram) Observe(v float64) {
+	h.span.base.Metric(&h.k.MetricAttribute, v, time.Now())
+	h.span.eft()
+}
@@ -0,0 +1,121 @@
+package xop
+
+import (
+	"runtime"
+	"strings"
+
+	"github.com/xoplog/xop-go/xopnum"
+
+	"github.com/pkg/errors"
+)
+
+// PanicTrap is returned by Logger.TrapPanic. Its methods must be
+// invoked directly with defer so that they can recover() the panic.
+//
+//	defer log.TrapPanic().ReturnError(&err)
+//	defer log.TrapPanic().Rethrow()
+type PanicTrap struct {
+	logger *Logger
+}
+
+// TrapPanic is used to make sure that a panic does not
+// cause the loss of the logs for the request. When a panic is
+// recovered, it is logged as an Alert that includes the panic value
+// and the full stack. Then all the base loggers are synchronously
+// flushed, including buffered ones.
+func (logger *Logger) TrapPanic() PanicTrap {
+	return PanicTrap{logger: logger}
+}
+
+// ReturnError recovers a panic, logs it, flushes, and then
+// assigns an error that wraps the panic value to *err.  If
+// there is no panic, *err is not modified.
+func (pt PanicTrap) ReturnError(err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	pt.logPanic(r)
+	if e, ok := r.(error); ok {
+		*err = errors.Wrap(e, "recovered panic")
+	} else {
+		*err = errors.Errorf("recovered panic: %v", r)
+	}
+}
+
+// Rethrow recovers a panic, logs it, flushes, and then
+// panics again with the same value.
+func (pt PanicTrap) Rethrow() {
+	r := recover()
+	if r == nil {
+		return
+	}
+	pt.logPanic(r)
+	panic(r)
+}
+
+func (pt PanicTrap) logPanic(r interface{}) {
+	line := pt.logger.panicLine()
+	switch v := r.(type) {
+	case error:
+		line = line.Error(Key("panic"), v)
+	case string:
+		line = line.String(Key("panic"), v)
+	default:
+		line = line.Any(Key("panic"), v)
+	}
+	line.Msgf("recovered panic: %v", r)
+	pt.logger.Flush()
+}
+
+// panicLine is logLine for AlertLevel but with the entire stack
+// of the panicking goroutine.
+func (logger *Logger) panicLine() *Line {
+	logger.notBoring()
+	return logger.logLine(xopnum.AlertLevel, true)
+}
+
+// panicStack captures the entire stack of the panicking goroutine
+// into ll.pc and ll.stack. The normal stack capture in logLine
+// stops at the first frame in the runtime and when called from a
+// deferred function, that frame is runtime.gopanic.
+func (logger *Logger) panicStack(ll *Line) {
+	pc := ll.pc[:cap(ll.pc)]
+	if len(pc) < 64 {
+		pc = make([]uintptr, 64)
+	}
+	for {
+		n := runtime.Callers(stackFramesToExclude, pc)
+		if n < len(pc) {
+			pc = pc[:n]
+			break
+		}
+		pc = make([]uintptr, len(pc)*2)
+	}
+	var all []runtime.Frame
+	start := 0
+	frames := runtime.CallersFrames(pc)
+	for {
+		frame, more := frames.Next()
+		all = append(all, frame)
+		if frame.Function == "runtime.gopanic" {
+			start = len(all)
+		}
+		if !more {
+			break
+		}
+	}
+	stack := ll.stack[:0]
+	for _, frame := range all[start:] {
+		if strings.Contains(frame.File, "/runtime/") {
+			continue
+		}
+		frame.File = logger.settings.stackFilenameRewrite(frame.File)
+		if frame.File == "" {
+			break
+		}
+		stack = append(stack, frame)
+	}
+	ll.pc = pc
+	ll.stack = stack
+}
@@ -0,0 +1,129 @@
+package xop_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/xoplog/xop-go"
+	"github.com/xoplog/xop-go/xopat"
+	"github.com/xoplog/xop-go/xopnum"
+	"github.com/xoplog/xop-go/xoprecorder"
+	"github.com/xoplog/xop-go/xoptest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrapPanicReturnError(t *testing.T) {
+	tLog := xoptest.New(t)
+	log := tLog.Logger()
+	f := func() (err error) {
+		defer log.TrapPanic().ReturnError(&err)
+		panic("oops")
+	}
+	err := f()
+	require.Error(t, err)
+	assert.Equal(t, "recovered panic: oops", err.Error())
+	lines := tLog.Recorder().FindLines(xoprecorder.MessageEquals("recovered panic: oops"))
+	require.Len(t, lines, 1)
+	assert.Equal(t, xopnum.AlertLevel, lines[0].Level)
+	assert.Equal(t, "oops", lines[0].Data["panic"])
+	if assert.NotEmpty(t, lines[0].Stack, "stack") {
+		assert.Contains(t, lines[0].Stack[0].Function, "TestTrapPanicReturnError")
+	}
+}
+
+func TestTrapPanicReturnErrorWraps(t *testing.T) {
+	tLog := xoptest.New(t)
+	log := tLog.Logger()
+	sentinel := errors.New("sentinel")
+	f := func() (err error) {
+		defer log.TrapPanic().ReturnError(&err)
+		panic(sentinel)
+	}
+	err := f()
+	assert.True(t, errors.Is(err, sentinel), "wrapped")
+}
+
+func TestTrapPanicNoPanic(t *testing.T) {
+	tLog := xoptest.New(t)
+	log := tLog.Logger()
+	f := func() (err error) {
+		defer log.TrapPanic().ReturnError(&err)
+		return nil
+	}
+	assert.NoError(t, f())
+	assert.Empty(t, tLog.Recorder().Lines)
+}
+
+func TestTrapPanicRethrow(t *testing.T) {
+	tLog := xoptest.New(t)
+	log := tLog.Logger()
+	assert.PanicsWithValue(t, "again", func() {
+		defer log.TrapPanic().Rethrow()
+		panic("again")
+	})
+	assert.Equal(t, 1, tLog.Recorder().CountLines(xoprecorder.MessageEquals("recovered panic: again")))
+}
+
+func TestTrapPanicScanner(t *testing.T) {
+	log, rLog := scanLog(t, xop.NewScanner(xop.ScanMask))
+	f := func() (err error) {
+		defer log.TrapPanic().ReturnError(&err)
+		panic("no account for me@example.com")
+	}
+	require.Error(t, f())
+	lines := rLog.FindLines(xoprecorder.MessageEquals("recovered panic: no account for [redacted:email]"))
+	require.Len(t, lines, 1, "message scanned")
+	assert.Equal(t, "no account for [redacted:email]", lines[0].Data["panic"], "value scanned")
+	assert.Equal(t, xopnum.AlertLevel, lines[0].Level)
+}
+
+func TestTrapPanicRedaction(t *testing.T) {
+	log, rLog := redactRequest(t, xop.NewRedactionPolicy().
+		Action(xopat.Secret, xop.RedactionMask).
+		Key("panic", xopat.Secret))
+	assert.Panics(t, func() {
+		defer log.TrapPanic().Rethrow()
+		panic("pw")
+	})
+	lines := rLog.FindLines(xoprecorder.MessageEquals("recovered panic: pw"))
+	require.Len(t, lines, 1)
+	assert.Equal(t, "[redacted]", lines[0].Data["panic"])
+}
+
+func TestTrapPanicNotLimited(t *testing.T) {
+	for name, by := range map[string]xop.RateLimitKey{
+		"caller":  xop.RateLimitByCaller,
+		"message": xop.RateLimitByMessage,
+	} {
+		by := by
+		t.Run(name, func(t *testing.T) {
+			tLog := xoptest.New(t)
+			request := xop.NewSeed(
+				xop.WithBase(tLog),
+				xop.WithSettings(func(settings *xop.LogSettings) {
+					settings.Budget(1, 0, 0)
+				}),
+			).Request(t.Name())
+			request.Info().Msg("uses the budget")
+			log := request.Sub().RateLimit(1, time.Hour, by).Logger()
+			for i := 0; i < 3; i++ {
+				func() {
+					defer func() { _ = recover() }()
+					defer log.TrapPanic().Rethrow()
+					panic("again")
+				}()
+			}
+			request.Done()
+
+			lines := tLog.Recorder().FindLines(xoprecorder.MessageEquals("recovered panic: again"))
+			require.Len(t, lines, 3, "every panic is logged")
+			for _, line := range lines {
+				assert.Equal(t, xopnum.AlertLevel, line.Level)
+			}
+			assert.Equal(t, 0, tLog.Recorder().CountLines(xoprecorder.MessageEquals("suppressed 2 similar lines")), "no summary")
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package xop
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/xoplog/xop-go/xopat"
+	"github.com/xoplog/xop-go/xopbase"
+	"github.com/xoplog/xop-go/xopnum"
+)
+
+// RateLimitKey selects what lines are grouped by for RateLimit
+type RateLimitKey int
+
+const (
+	// RateLimitByCaller groups lines by the program counter of the code
+	// that started the line.
+	RateLimitByCaller RateLimitKey = iota
+	// RateLimitByMessage groups lines by their message, template, or
+	// format string. Model, Link, and Table lines are grouped by their
+	// message too.
+	RateLimitByMessage
+)
+
+// rateLimiter tracks lines per key for one span
+type rateLimiter struct {
+	lock    sync.Mutex
+	entries map[interface{}]*rateEntry
+}
+
+type rateEntry struct {
+	windowStart time.Time
+	count       int
+	suppressed  int
+	level       xopnum.Level
+}
+
+// suppressed is a summary of lines that were not logged
+type suppressed struct {
+	key   interface{}
+	count int
+	level xopnum.Level
+}
+
+// rateLimited returns true if the line should be suppressed. If a window
+// for the key has closed with suppressed lines, the summary of those lines
+// is logged first.
+func (logger *Logger) rateLimited(key interface{}, level xopnum.Level) bool {
+	now := time.Now()
+	limiter := logger.span.getRateLimiter()
+	var summary suppressed
+	allowed := func() bool {
+		limiter.lock.Lock()
+		defer limiter.lock.Unlock()
+		e, ok := limiter.entries[key]
+		if !ok {
+			e = &rateEntry{windowStart: now}
+			limiter.entries[key] = e
+		} else if now.Sub(e.windowStart) >= logger.settings.rateLimitInterval {
+			summary = suppressed{key: key, count: e.suppressed, level: e.level}
+			*e = rateEntry{windowStart: now}
+		}
+		if e.count < logger.settings.rateLimit {
+			e.count++
+			return true
+		}
+		e.suppressed++
+		if level > e.level {
+			e.level = level
+		}
+		return false
+	}()
+	if summary.count > 0 {
+		logger.logSuppressed(summary)
+		logger.hasActivity(true)
+	}
+	return !allowed
+}
+
+// newDeferredLine is used for RateLimitByMessage. The line is held
+// until it is complete and then, if it wasn't suppressed, it is sent
+// by sendDeferred. Suppressed lines are never seen by the base loggers
+// and do not count against the budget.
+func (logger *Logger) newDeferredLine(level xopnum.Level, ts time.Time, stack []runtime.Frame) *heldLine {
+	h := logger.newHeldLine(level, ts, stack)
+	h.deferred = true
+	// the line is sent before its terminator returns so there is
+	// no need to copy models
+	h.copyModels = false
+	return h
+}
+
+func (logger *Logger) sendDeferred(h *heldLine) {
+	if h.notBoring {
+		logger.notBoring()
+	}
+	if (logger.settings.budgetLines > 0 || logger.settings.budgetBytes > 0) && logger.overBudget(h.level) {
+		return
+	}
+	line, skip := logger.baseLine(h.level, h.ts, h.stack)
+	if skip {
+		return
+	}
+	for _, op := range h.ops {
+		op(line)
+	}
+}
+
+func (span *span) getRateLimiter() *rateLimiter {
+	span.dependentLock.Lock()
+	defer span.dependentLock.Unlock()
+	if span.rateLimiter == nil {
+		span.rateLimiter = &rateLimiter{
+			entries: make(map[interface{}]*rateEntry),
+		}
+	}
+	return span.rateLimiter
+}
+
+// flushSuppressed logs summaries for all keys that have suppressed
+// lines. It is called when the span is done.
+func (logger *Logger) flushSuppressed() {
+	summaries := func() []suppressed {
+		logger.span.dependentLock.Lock()
+		limiter := logger.span.rateLimiter
+		logger.span.dependentLock.Unlock()
+		if limiter == nil {
+			return nil
+		}
+		limiter.lock.Lock()
+		defer limiter.lock.Unlock()
+		var summaries []suppressed
+		for key, e := range limiter.entries {
+			if e.suppressed > 0 {
+				summaries = append(summaries, suppressed{key: key, count: e.suppressed, level: e.level})
+				e.suppressed = 0
+			}
+		}
+		return summaries
+	}()
+	for _, summary := range summaries {
+		logger.logSuppressed(summary)
+	}
+}
+
+func (logger *Logger) logSuppressed(summary suppressed) {
+	line, skip := logger.baseLine(summary.level, time.Now(), nil)
+	if skip {
+		return
+	}
+	line.Int64(xopat.K("suppressed"), int64(summary.count), xopbase.IntDataType)
+	switch key := summary.key.(type) {
+	case uintptr:
+		if fn := runtime.FuncForPC(key); fn != nil {
+			file, lineNumber := fn.FileLine(key)
+			line.String(xopat.K("caller"), logger.settings.stackFilenameRewrite(file)+":"+strconv.Itoa(lineNumber), xopbase.StringDataType)
+		}
+	case string:
+		line.String(xopat.K("similarTo"), key, xopbase.StringDataType)
+	}
+	line.Msg(fmt.Sprintf("suppressed %s similar lines", commas(summary.count)))
+}
+
+// commas formats 4211 as "4,211"
+func commas(n int) string {
+	s := strconv.Itoa(n)
+	for i := len(s) - 3; i > 0 && s[i-1] != '-'; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
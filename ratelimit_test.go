@@ -0,0 +1,125 @@
+package xop_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xoplog/xop-go"
+	"github.com/xoplog/xop-go/xopbase"
+	"github.com/xoplog/xop-go/xopnum"
+	"github.com/xoplog/xop-go/xoprecorder"
+	"github.com/xoplog/xop-go/xoptest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitByCaller(t *testing.T) {
+	tLog := xoptest.New(t)
+	request := tLog.Logger()
+	log := request.Sub().RateLimit(3, time.Hour, xop.RateLimitByCaller).Logger()
+	for i := 0; i < 4213; i++ {
+		log.Info().Int("i", i).Msg("loop")
+		if i%1000 == 0 {
+			log.Warn().Msg("other site")
+		}
+	}
+	request.Done()
+
+	assert.Equal(t, 3, tLog.Recorder().CountLines(xoprecorder.MessageEquals("loop")), "limited")
+	assert.Equal(t, 3, tLog.Recorder().CountLines(xoprecorder.MessageEquals("other site")), "separate call site")
+	summary := tLog.Recorder().FindLines(xoprecorder.MessageEquals("suppressed 4,210 similar lines"))
+	require.Len(t, summary, 1, "summary at done")
+	assert.Equal(t, int64(4210), summary[0].Data["suppressed"])
+	assert.Contains(t, summary[0].Data["caller"], "ratelimit_test.go:")
+	assert.Equal(t, xopnum.InfoLevel, summary[0].Level)
+	assert.Equal(t, 1, tLog.Recorder().CountLines(xoprecorder.MessageEquals("suppressed 2 similar lines")), "other site summary")
+}
+
+func TestRateLimitByMessage(t *testing.T) {
+	tLog := xoptest.New(t)
+	log := tLog.Logger()
+	step := log.Sub().RateLimit(1, 50*time.Millisecond, xop.RateLimitByMessage).Step("step")
+	step.Info().String("s", "a").Template("item {s}")
+	step.Info().String("s", "b").Template("item {s}")
+	step.Info().Msgf("formatted %d", 1)
+	step.Info().Msgf("formatted %d", 2)
+	step.Debug().Msg("different")
+	time.Sleep(60 * time.Millisecond)
+	step.Info().String("s", "c").Template("item {s}")
+	step.Done()
+	log.Done()
+
+	assert.Equal(t, 1, tLog.Recorder().CountLines(xoprecorder.MessageEquals("item a")))
+	assert.Equal(t, 0, tLog.Recorder().CountLines(xoprecorder.MessageEquals("item b")), "suppressed")
+	assert.Equal(t, 1, tLog.Recorder().CountLines(xoprecorder.MessageEquals("item c")), "new window")
+	assert.Equal(t, 1, tLog.Recorder().CountLines(xoprecorder.MessageEquals("formatted 1")))
+	assert.Equal(t, 0, tLog.Recorder().CountLines(xoprecorder.MessageEquals("formatted 2")), "keyed by format")
+	assert.Equal(t, 1, tLog.Recorder().CountLines(xoprecorder.MessageEquals("different")))
+	summaries := tLog.Recorder().FindLines(xoprecorder.MessageEquals("suppressed 1 similar lines"))
+	require.Len(t, summaries, 2, "window close and done")
+	similar := []interface{}{summaries[0].Data["similarTo"], summaries[1].Data["similarTo"]}
+	assert.ElementsMatch(t, []interface{}{"item {s}", "formatted %d"}, similar)
+}
+
+func TestRateLimitSummaryScanned(t *testing.T) {
+	rLog := xoprecorder.New()
+	request := xop.NewSeed(
+		xop.WithBase(rLog),
+		xop.WithScanner(xop.NewScanner(xop.ScanMask)),
+	).Request(t.Name())
+	log := request.Sub().RateLimit(1, time.Hour, xop.RateLimitByMessage).Logger()
+	log.Info().Msg("mail to me@example.com failed")
+	log.Info().Msg("mail to me@example.com failed")
+	request.Done()
+
+	summary := rLog.FindLines(xoprecorder.MessageEquals("suppressed 1 similar lines"))
+	require.Len(t, summary, 1)
+	assert.Equal(t, "mail to [redacted:email] failed", summary[0].Data["similarTo"])
+}
+
+func TestRateLimitByMessageTerminators(t *testing.T) {
+	tLog := xoptest.New(t)
+	request := tLog.Logger()
+	log := request.Sub().RateLimit(1, time.Hour, xop.RateLimitByMessage).Logger()
+	table := xopbase.TableData{Columns: []string{"a"}, Cells: [][]string{{"1"}}}
+	for i := 0; i < 3; i++ {
+		log.Info().Model(map[string]int{"i": i}, "model")
+		log.Info().Modelf(i, "modelf %d", i)
+		log.Info().Link(request.Span().Trace(), "link")
+		log.Info().Table(table, "table")
+	}
+	request.Done()
+
+	for _, msg := range []string{"model", "modelf 0", "link", "table"} {
+		assert.Equalf(t, 1, tLog.Recorder().CountLines(xoprecorder.MessageEquals(msg)), "one %s", msg)
+	}
+	assert.Equal(t, 0, tLog.Recorder().CountLines(xoprecorder.MessageEquals("modelf 1")), "keyed by format")
+	assert.Equal(t, 4, tLog.Recorder().CountLines(xoprecorder.MessageEquals("suppressed 2 similar lines")))
+}
+
+func TestRateLimitByMessageSuppressedNotCounted(t *testing.T) {
+	tLog := xoptest.New(t)
+	request := xop.NewSeed(
+		xop.WithBase(tLog),
+		xop.WithSettings(func(settings *xop.LogSettings) {
+			settings.Budget(3, 0, 0)
+		}),
+	).Request(t.Name())
+	request.Boring()
+	log := request.Sub().RateLimit(1, time.Hour, xop.RateLimitByMessage).Logger()
+	log.Info().Msg("oops")
+	log.Info().Msgf("oops %d", 1)
+	log.Error().Msg("oops")
+	log.Alert().Msgf("oops %d", 2)
+	assert.True(t, request.IsBoring(), "suppressed lines are not interesting")
+	log.Info().Msg("within budget")
+	log.Info().Msg("over budget")
+	request.Done()
+
+	assert.Equal(t, 1, tLog.Recorder().CountLines(xoprecorder.MessageEquals("oops")))
+	assert.Equal(t, 1, tLog.Recorder().CountLines(xoprecorder.MessageEquals("oops 1")))
+	assert.Equal(t, 0, tLog.Recorder().CountLines(xoprecorder.MessageEquals("oops 2")))
+	assert.Equal(t, 1, tLog.Recorder().CountLines(xoprecorder.MessageEquals("within budget")), "suppressed lines not charged")
+	assert.Equal(t, 0, tLog.Recorder().CountLines(xoprecorder.MessageEquals("over budget")))
+}
@@ -0,0 +1,454 @@
+// This file is generated, DO NOT EDIT.  It comes from the corresponding .zzzgo file
+
+package xop
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/xoplog/xop-go/xopat"
+	"github.com/xoplog/xop-go/xopbase"
+	"github.com/xoplog/xop-go/xoptrace"
+
+	"github.com/mohae/deepcopy"
+)
+
+// RedactionAction is what a RedactionPolicy does with values
+// of a particular xopat.Sensitivity
+type RedactionAction int
+
+const (
+	// RedactionDrop omits the value entirely. It is the action used
+	// for sensitivity classes that have not been configured.
+	RedactionDrop RedactionAction = iota
+	// RedactionPass logs the value unchanged.
+	RedactionPass
+	// RedactionMask replaces the value with a fixed string.
+	RedactionMask
+	// RedactionHash replaces the value with a keyed HMAC-SHA256 of the
+	// value so that equal values can be correlated without being revealed.
+	RedactionHash
+)
+
+// RedactionTag is the struct tag used to mark fields of models
+// (Any() and Model()) as sensitive:
+//
+//	type User struct {
+//		ID    int
+//		Email string `xopredact:"pii"`
+//	}
+//
+// Exported fields of structs, pointers to structs, slices, and arrays
+// are examined. Maps are not.
+const RedactionTag = "xopredact"
+
+// DefaultRedactionMask is the replacement for masked values
+const DefaultRedactionMask = "[redacted]"
+
+// RedactionPolicy decides what to do with sensitive values based upon
+// the xopat.Sensitivity of their attributes.  It applies to span metadata,
+// line attributes, prefilled attributes, and to fields of models that are
+// tagged with RedactionTag.
+//
+// Sensitivity classes that are not given an action are dropped. Hashing
+// requires a key: without one, values that would be hashed are dropped.
+//
+// Span metadata attributes are typed so masking and hashing are only
+// possible for String and Any attributes. Other types are dropped
+// unless their action is RedactionPass. Masked and hashed line attributes
+// and Any metadata become strings.
+//
+// A RedactionPolicy must be fully configured before it is used
+// with WithRedaction.
+type RedactionPolicy struct {
+	actions map[xopat.Sensitivity]RedactionAction
+	keys    map[xopat.K]xopat.Sensitivity
+	hashKey []byte
+	mask    string
+	types   sync.Map // reflect.Type -> bool (has tagged fields)
+}
+
+func NewRedactionPolicy() *RedactionPolicy {
+	return &RedactionPolicy{
+		actions: make(map[xopat.Sensitivity]RedactionAction),
+		keys:    make(map[xopat.K]xopat.Sensitivity),
+		mask:    DefaultRedactionMask,
+	}
+}
+
+// Action sets what is done with values of a sensitivity class
+func (p *RedactionPolicy) Action(class xopat.Sensitivity, action RedactionAction) *RedactionPolicy {
+	p.actions[class] = action
+	return p
+}
+
+// Key classifies a key.  This is for line attributes that do
+// not have a registered attribute. It overrides the Sensitivity of
+// registered attributes.
+func (p *RedactionPolicy) Key(k xopat.K, class xopat.Sensitivity) *RedactionPolicy {
+	p.keys[k] = class
+	return p
+}
+
+// HashKey sets the secret key for RedactionHash
+func (p *RedactionPolicy) HashKey(key []byte) *RedactionPolicy {
+	p.hashKey = key
+	return p
+}
+
+// Mask sets the replacement string for RedactionMask.
+func (p *RedactionPolicy) Mask(mask string) *RedactionPolicy {
+	p.mask = mask
+	return p
+}
+
+func (p *RedactionPolicy) action(class xopat.Sensitivity) RedactionAction {
+	if class == xopat.NotSensitive {
+		return RedactionPass
+	}
+	action := p.actions[class]
+	if action == RedactionHash && p.hashKey == nil {
+		return RedactionDrop
+	}
+	return action
+}
+
+func (p *RedactionPolicy) keyAction(k xopat.K) RedactionAction {
+	if class, ok := p.keys[k]; ok {
+		return p.action(class)
+	}
+	return p.action(xopat.SensitivityOf(k))
+}
+
+func (p *RedactionPolicy) attributeAction(k xopat.AttributeInterface) RedactionAction {
+	if class, ok := p.keys[k.Key()]; ok {
+		return p.action(class)
+	}
+	return p.action(k.Sensitivity())
+}
+
+func (p *RedactionPolicy) hash(v interface{}) string {
+	mac := hmac.New(sha256.New, p.hashKey)
+	switch t := v.(type) {
+	case string:
+		mac.Write([]byte(t))
+	case time.Time:
+		mac.Write([]byte(t.Format(time.RFC3339Nano)))
+	case xopbase.ModelArg:
+		if t.Encoded != nil {
+			mac.Write(t.Encoded)
+		} else {
+			enc, _ := json.Marshal(t.Model)
+			mac.Write(enc)
+		}
+	default:
+		mac.Write([]byte(fmt.Sprint(v)))
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// replacement returns the string to log in place of a value
+// for RedactionMask and RedactionHash.
+func (p *RedactionPolicy) replacement(action RedactionAction, v interface{}) string {
+	if action == RedactionHash {
+		return p.hash(v)
+	}
+	return p.mask
+}
+
+// redactModel returns v with tagged fields redacted. If there are
+// no tagged fields, v is returned as-is. Otherwise a copy is made.
+func (p *RedactionPolicy) redactModel(v interface{}) interface{} {
+	if v == nil || !p.hasTags(reflect.TypeOf(v)) {
+		return v
+	}
+	c := reflect.ValueOf(deepcopy.Copy(v))
+	if c.Kind() != reflect.Ptr {
+		// make it addressable
+		ptr := reflect.New(c.Type())
+		ptr.Elem().Set(c)
+		c = ptr.Elem()
+	}
+	p.redactValue(c)
+	return c.Interface()
+}
+
+func (p *RedactionPolicy) redactModelArg(v xopbase.ModelArg) xopbase.ModelArg {
+	if v.Encoded == nil {
+		v.Model = p.redactModel(v.Model)
+	}
+	return v
+}
+
+func (p *RedactionPolicy) redactValue(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			p.redactValue(v.Elem())
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			p.redactValue(v.Index(i))
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+			class, ok := f.Tag.Lookup(RedactionTag)
+			if !ok {
+				p.redactValue(v.Field(i))
+				continue
+			}
+			field := v.Field(i)
+			if !field.CanSet() {
+				continue
+			}
+			switch action := p.action(xopat.Sensitivity(class)); action {
+			case RedactionPass:
+			case RedactionMask, RedactionHash:
+				if field.Kind() == reflect.String {
+					field.SetString(p.replacement(action, field.String()))
+					continue
+				}
+				field.Set(reflect.Zero(f.Type))
+			default:
+				field.Set(reflect.Zero(f.Type))
+			}
+		}
+	}
+}
+
+// hasTags reports if values of type t can have fields tagged with RedactionTag.
+func (p *RedactionPolicy) hasTags(t reflect.Type) bool {
+	if has, ok := p.types.Load(t); ok {
+		return has.(bool)
+	}
+	has := typeHasTags(t, make(map[reflect.Type]struct{}))
+	p.types.Store(t, has)
+	return has
+}
+
+func typeHasTags(t reflect.Type, seen map[reflect.Type]struct{}) bool {
+	if _, ok := seen[t]; ok {
+		return false
+	}
+	seen[t] = struct{}{}
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return typeHasTags(t.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+			if _, ok := f.Tag.Lookup(RedactionTag); ok {
+				return true
+			}
+			if typeHasTags(f.Type, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// redactBuilder implements xopbase.Builder by applying a RedactionPolicy
+// to the attributes before passing them on.
+type redactBuilder struct {
+	builder xopbase.Builder
+	policy  *RedactionPolicy
+}
+
+// redactLine implements xopbase.Line. It is used when there is a
+// RedactionPolicy: see WithRedaction.
+type redactLine struct {
+	redactBuilder
+	line xopbase.Line
+}
+
+// redactPrefilling implements xopbase.Prefilling
+type redactPrefilling struct {
+	redactBuilder
+	prefilling xopbase.Prefilling
+}
+
+var (
+	_ xopbase.Line       = &redactLine{}
+	_ xopbase.Prefilling = &redactPrefilling{}
+)
+
+func newRedactLine(line xopbase.Line, policy *RedactionPolicy) *redactLine {
+	return &redactLine{
+		redactBuilder: redactBuilder{
+			builder: line,
+			policy:  policy,
+		},
+		line: line,
+	}
+}
+
+func newRedactPrefilling(prefilling xopbase.Prefilling, policy *RedactionPolicy) *redactPrefilling {
+	return &redactPrefilling{
+		redactBuilder: redactBuilder{
+			builder: prefilling,
+			policy:  policy,
+		},
+		prefilling: prefilling,
+	}
+}
+
+func (r *redactPrefilling) PrefillComplete(msg string) xopbase.Prefilled {
+	return r.prefilling.PrefillComplete(msg)
+}
+
+func (r *redactLine) Msg(msg string)                    { r.line.Msg(msg) }
+func (r *redactLine) Template(template string)          { r.line.Template(template) }
+func (r *redactLine) Link(msg string, v xoptrace.Trace) { r.line.Link(msg, v) }
+func (r *redactLine) Model(msg string, v xopbase.ModelArg) {
+	r.line.Model(msg, r.policy.redactModelArg(v))
+}
+
+// Table columns are classified by treating their headers as keys.
+func (r *redactLine) Table(msg string, t xopbase.SimpleTable) {
+	header := t.Header()
+	actions := make([]RedactionAction, len(header))
+	var redact bool
+	for i, column := range header {
+		actions[i] = r.policy.keyAction(xopat.K(column))
+		if actions[i] != RedactionPass {
+			redact = true
+		}
+	}
+	if !redact {
+		r.line.Table(msg, t)
+		return
+	}
+	redactRow := func(row []string) []string {
+		redacted := make([]string, 0, len(row))
+		for i, cell := range row {
+			action := RedactionPass
+			if i < len(actions) {
+				action = actions[i]
+			}
+			switch action {
+			case RedactionPass:
+				redacted = append(redacted, cell)
+			case RedactionMask, RedactionHash:
+				redacted = append(redacted, r.policy.replacement(action, cell))
+			}
+		}
+		return redacted
+	}
+	rows := t.Rows()
+	td := xopbase.TableData{
+		Cells: make([][]string, len(rows)),
+	}
+	for i, column := range header {
+		if actions[i] != RedactionDrop {
+			td.Columns = append(td.Columns, column)
+		}
+	}
+	for i, row := range rows {
+		td.Cells[i] = redactRow(row)
+	}
+	r.line.Table(msg, td)
+}
+
+// redacted handles values whose action is not RedactionPass
+func (b *redactBuilder) redacted(k xopat.K, action RedactionAction, v interface{}) {
+	if action == RedactionDrop {
+		return
+	}
+	b.builder.String(k, b.policy.replacement(action, v), xopbase.StringDataType)
+}
+
+func (b *redactBuilder) Enum(k *xopat.EnumAttribute, v xopat.Enum) {
+	if action := b.policy.attributeAction(k); action != RedactionPass {
+		b.redacted(k.Key(), action, v.String())
+		return
+	}
+	b.builder.Enum(k, v)
+}
+
+func (b *redactBuilder) Any(k xopat.K, v xopbase.ModelArg) {
+	if action := b.policy.keyAction(k); action != RedactionPass {
+		b.redacted(k, action, v)
+		return
+	}
+	b.builder.Any(k, b.policy.redactModelArg(v))
+}
+
+func (b *redactBuilder) Bool(k xopat.K, v bool) {
+	if action := b.policy.keyAction(k); action != RedactionPass {
+		b.redacted(k, action, v)
+		return
+	}
+	b.builder.Bool(k, v)
+}
+
+func (b *redactBuilder) Duration(k xopat.K, v time.Duration) {
+	if action := b.policy.keyAction(k); action != RedactionPass {
+		b.redacted(k, action, v)
+		return
+	}
+	b.builder.Duration(k, v)
+}
+
+func (b *redactBuilder) Time(k xopat.K, v time.Time) {
+	if action := b.policy.keyAction(k); action != RedactionPass {
+		b.redacted(k, action, v)
+		return
+	}
+	b.builder.Time(k, v)
+}
+
+func (b *redactBuilder) Float64(k xopat.K, v float64, dt xopbase.DataType) {
+	if action := b.policy.keyAction(k); action != RedactionPass {
+		b.redacted(k, action, v)
+		return
+	}
+	b.builder.Float64(k, v, dt)
+}
+
+func (b *redactBuilder) Int64(k xopat.K, v int64, dt xopbase.DataType) {
+	if action := b.policy.keyAction(k); action != RedactionPass {
+		b.redacted(k, action, v)
+		return
+	}
+	b.builder.Int64(k, v, dt)
+}
+
+func (b *redactBuilder) String(k xopat.K, v string, dt xopbase.DataType) {
+	if action := b.policy.keyAction(k); action != RedactionPass {
+		b.redacted(k, action, v)
+		return
+	}
+	b.builder.String(k, v, dt)
+}
+
+func (b *redactBuilder) Uint64(k xopat.K, v uint64, dt xopbase.DataType) {
+	if action := b.policy.keyAction(k); action != RedactionPass {
+		b.redacted(k, action, v)
+		return
+	}
+	b.builder.Uint64(k, v, dt)
+}
+
+// redaction returns the action for span metadata
+func (span *Span) redaction(k xopat.AttributeInterface) (*RedactionPolicy, RedactionAction) {
+	policy := span.logger.span.seed.config.Redaction
+	if policy == nil {
+		return nil, RedactionPass
+	}
+	return policy, policy.attributeAction(k)
+}
@@ -0,0 +1,141 @@
+package xop_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/xoplog/xop-go"
+	"github.com/xoplog/xop-go/xopat"
+	"github.com/xoplog/xop-go/xopbase"
+	"github.com/xoplog/xop-go/xoprecorder"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	redactEmail   = xopat.Make{Key: "test.redact.email", Namespace: "test", Sensitive: xopat.PII}.StringAttribute()
+	redactToken   = xopat.Make{Key: "test.redact.token", Namespace: "test", Sensitive: xopat.Secret}.StringAttribute()
+	redactAge     = xopat.Make{Key: "test.redact.age", Namespace: "test", Sensitive: xopat.PII}.IntAttribute()
+	redactAccount = xopat.Make{Key: "test.redact.account", Namespace: "test"}.AnyAttribute(redactUser{})
+	redactPlain   = xopat.Make{Key: "test.redact.plain", Namespace: "test"}.StringAttribute()
+)
+
+type redactUser struct {
+	ID       int
+	Email    string `xopredact:"pii"`
+	Password string `xopredact:"secret"`
+	Inner    *redactInner
+}
+
+type redactInner struct {
+	Phone string `xopredact:"pii"`
+}
+
+var redactHashKey = []byte("sekrit")
+
+func redactHash(s string) string {
+	mac := hmac.New(sha256.New, redactHashKey)
+	mac.Write([]byte(s))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func redactRequest(t *testing.T, policy *xop.RedactionPolicy) (*xop.Logger, *xoprecorder.Logger) {
+	rLog := xoprecorder.New()
+	return xop.NewSeed(
+		xop.WithBase(rLog),
+		xop.WithRedaction(policy),
+	).Request(t.Name()), rLog
+}
+
+func TestRedactionSpanMetadata(t *testing.T) {
+	log, rLog := redactRequest(t, xop.NewRedactionPolicy().
+		Action(xopat.PII, xop.RedactionHash).
+		HashKey(redactHashKey))
+	log.Span().String(redactEmail, "me@example.com")
+	log.Span().String(redactToken, "abc123")
+	log.Span().Int(redactAge, 33)
+	log.Span().String(redactPlain, "visible")
+	log.Span().Any(redactAccount, redactUser{ID: 3, Email: "me@example.com", Password: "pw"})
+	log.Done()
+
+	require.Len(t, rLog.Requests, 1)
+	md := &rLog.Requests[0].SpanMetadata
+	assert.Equal(t, redactHash("me@example.com"), md.Get(redactEmail.Key().String()).Value, "hashed")
+	assert.Nil(t, md.Get(redactToken.Key().String()), "unconfigured class dropped")
+	assert.Nil(t, md.Get(redactAge.Key().String()), "non-string cannot be hashed")
+	assert.Equal(t, "visible", md.Get(redactPlain.Key().String()).Value)
+	account := md.Get(redactAccount.Key().String())
+	if assert.NotNil(t, account) {
+		assert.Equal(t, redactUser{ID: 3, Email: redactHash("me@example.com")}, account.Value.(xopbase.ModelArg).Model, "tagged fields")
+	}
+}
+
+func TestRedactionLines(t *testing.T) {
+	log, rLog := redactRequest(t, xop.NewRedactionPolicy().
+		Action(xopat.PII, xop.RedactionMask).
+		Action(xopat.Secret, xop.RedactionDrop).
+		Key("password", xopat.Secret))
+	pre := log.Sub().PrefillString(redactEmail.Key(), "pre@example.com").Logger()
+	pre.Info().
+		String(redactToken.Key(), "abc123").
+		String("password", "pw").
+		Int(redactAge.Key(), 33).
+		String("plain", "visible").
+		Msg("attributes")
+	log.Info().Template("email {test.redact.email}")
+	log.Info().String(redactEmail.Key(), "me@example.com").Template("email {test.redact.email}")
+	user := &redactUser{ID: 3, Email: "me@example.com", Password: "pw", Inner: &redactInner{Phone: "555-1212"}}
+	log.Info().Model(user, "model")
+	log.Info().Any("user", user).Msg("any")
+	log.Done()
+
+	lines := rLog.FindLines(xoprecorder.MessageEquals("attributes"))
+	require.Len(t, lines, 1)
+	assert.Equal(t, "[redacted]", lines[0].Data[redactEmail.Key()], "prefill masked")
+	assert.Equal(t, "[redacted]", lines[0].Data[redactAge.Key()], "masked ints become strings")
+	assert.Equal(t, "visible", lines[0].Data["plain"])
+	assert.NotContains(t, lines[0].Data, redactToken.Key())
+	assert.NotContains(t, lines[0].Data, xopat.K("password"), "classified by key")
+	assert.Equal(t, 1, rLog.CountLines(xoprecorder.MessageEquals("email [redacted]")))
+
+	lines = rLog.FindLines(xoprecorder.MessageEquals("model"))
+	require.Len(t, lines, 1)
+	assert.Equal(t, &redactUser{ID: 3, Email: "[redacted]", Inner: &redactInner{Phone: "[redacted]"}}, lines[0].AsModel.Model)
+	lines = rLog.FindLines(xoprecorder.MessageEquals("any"))
+	require.Len(t, lines, 1)
+	assert.Equal(t, &redactUser{ID: 3, Email: "[redacted]", Inner: &redactInner{Phone: "[redacted]"}}, lines[0].Data["user"].(xopbase.ModelArg).Model)
+	assert.Equal(t, "me@example.com", user.Email, "original not modified")
+}
+
+func TestRedactionPass(t *testing.T) {
+	log, rLog := redactRequest(t, xop.NewRedactionPolicy().
+		Action(xopat.PII, xop.RedactionPass).
+		Action(xopat.Secret, xop.RedactionHash))
+	log.Info().String(redactEmail.Key(), "me@example.com").String(redactToken.Key(), "abc").Msg("line")
+	log.Done()
+
+	lines := rLog.FindLines(xoprecorder.MessageEquals("line"))
+	require.Len(t, lines, 1)
+	assert.Equal(t, "me@example.com", lines[0].Data[redactEmail.Key()])
+	assert.NotContains(t, lines[0].Data, redactToken.Key(), "hash without key drops")
+}
+
+func TestRedactionTable(t *testing.T) {
+	log, rLog := redactRequest(t, xop.NewRedactionPolicy().
+		Action(xopat.PII, xop.RedactionMask).
+		Key("password", xopat.Secret))
+	log.Info().Table(xopbase.TableData{
+		Columns: []string{"id", string(redactEmail.Key()), "password"},
+		Cells:   [][]string{{"1", "me@example.com", "pw"}},
+	}, "users")
+	log.Done()
+
+	lines := rLog.FindLines(xoprecorder.MessageEquals("users"))
+	require.Len(t, lines, 1)
+	require.NotNil(t, lines[0].AsTable)
+	assert.Equal(t, []string{"id", string(redactEmail.Key())}, lines[0].AsTable.Columns, "secret column dropped")
+	assert.Equal(t, [][]string{{"1", "[redacted]"}}, lines[0].AsTable.Cells)
+}
@@ -0,0 +1,179 @@
+// This file is generated, DO NOT EDIT.  It comes from the corresponding .zzzgo file
+
+package xop
+
+import (
+	"runtime"
+	"time"
+
+	"github.com/xoplog/xop-go/xopat"
+	"github.com/xoplog/xop-go/xopbase"
+	"github.com/xoplog/xop-go/xopnum"
+	"github.com/xoplog/xop-go/xoptrace"
+
+	"github.com/mohae/deepcopy"
+)
+
+// heldLine implements xopbase.Line by recording the calls made
+// to it so that they can be replayed later.  It is used for tail-based
+// retention: see LogSettings.TailRetention.  It is also used to defer
+// creating lines until RateLimitByMessage has decided to keep them.
+type heldLine struct {
+	logger     *Logger
+	level      xopnum.Level
+	ts         time.Time
+	stack      []runtime.Frame
+	copyModels bool
+	ops        []func(xopbase.Line)
+	deferred   bool // for RateLimitByMessage, see sendDeferred
+	notBoring  bool // deferred Error() and Alert() lines
+}
+
+var _ xopbase.Line = &heldLine{}
+
+func (logger *Logger) newHeldLine(level xopnum.Level, ts time.Time, stack []runtime.Frame) *heldLine {
+	h := &heldLine{
+		logger:     logger,
+		level:      level,
+		ts:         ts,
+		copyModels: !logger.span.referencesKept,
+	}
+	if len(stack) != 0 {
+		// the stack is reused by the line pool
+		h.stack = make([]runtime.Frame, len(stack))
+		copy(h.stack, stack)
+	}
+	return h
+}
+
+// complete is called when the line is done
+func (h *heldLine) complete() {
+	if h.deferred {
+		h.logger.sendDeferred(h)
+	} else {
+		h.logger.shared.hold(h)
+	}
+}
+
+func (h *heldLine) replay() {
+	line := h.logger.prefilled.Line(h.level, h.ts, h.stack)
+	for _, op := range h.ops {
+		op(line)
+	}
+}
+
+func (h *heldLine) Enum(k *xopat.EnumAttribute, v xopat.Enum) {
+	h.ops = append(h.ops, func(line xopbase.Line) { line.Enum(k, v) })
+}
+
+// Any values may be modified after the line is complete so
+// they're copied if the Line didn't copy them already.
+func (h *heldLine) Any(k xopat.K, v xopbase.ModelArg) {
+	if h.copyModels {
+		v.Model = deepcopy.Copy(v.Model)
+	}
+	h.ops = append(h.ops, func(line xopbase.Line) { line.Any(k, v) })
+}
+
+func (h *heldLine) Model(msg string, v xopbase.ModelArg) {
+	if h.copyModels {
+		v.Model = deepcopy.Copy(v.Model)
+	}
+	h.ops = append(h.ops, func(line xopbase.Line) { line.Model(msg, v) })
+	h.complete()
+}
+
+// Tables are copied because the caller may modify them.
+func (h *heldLine) Table(msg string, t xopbase.SimpleTable) {
+	td := xopbase.NewTableData(t)
+	h.ops = append(h.ops, func(line xopbase.Line) { line.Table(msg, td) })
+	h.complete()
+}
+
+func (h *heldLine) Link(msg string, v xoptrace.Trace) {
+	h.ops = append(h.ops, func(line xopbase.Line) { line.Link(msg, v) })
+	h.complete()
+}
+
+func (h *heldLine) Msg(msg string) {
+	h.ops = append(h.ops, func(line xopbase.Line) { line.Msg(msg) })
+	h.complete()
+}
+
+func (h *heldLine) Template(template string) {
+	h.ops = append(h.ops, func(line xopbase.Line) { line.Template(template) })
+	h.complete()
+}
+
+func (h *heldLine) Bool(k xopat.K, v bool) {
+	h.ops = append(h.ops, func(line xopbase.Line) { line.Bool(k, v) })
+}
+
+func (h *heldLine) Duration(k xopat.K, v time.Duration) {
+	h.ops = append(h.ops, func(line xopbase.Line) { line.Duration(k, v) })
+}
+
+func (h *heldLine) Time(k xopat.K, v time.Time) {
+	h.ops = append(h.ops, func(line xopbase.Line) { line.Time(k, v) })
+}
+
+func (h *heldLine) Float64(k xopat.K, v float64, dt xopbase.DataType) {
+	h.ops = append(h.ops, func(line xopbase.Line) { line.Float64(k, v, dt) })
+}
+
+func (h *heldLine) Int64(k xopat.K, v int64, dt xopbase.DataType) {
+	h.ops = append(h.ops, func(line xopbase.Line) { line.Int64(k, v, dt) })
+}
+
+func (h *heldLine) String(k xopat.K, v string, dt xopbase.DataType) {
+	h.ops = append(h.ops, func(line xopbase.Line) { line.String(k, v, dt) })
+}
+
+func (h *heldLine) Uint64(k xopat.K, v uint64, dt xopbase.DataType) {
+	h.ops = append(h.ops, func(line xopbase.Line) { line.Uint64(k, v, dt) })
+}
+
+// hold keeps a completed line until the request is known to be
+// interesting.  If that's already known, the line is sent immediately.
+func (s *shared) hold(h *heldLine) {
+	if func() bool {
+		s.HeldLock.Lock()
+		defer s.HeldLock.Unlock()
+		if s.HeldReleased {
+			return false
+		}
+		if h.logger.settings.retainMax > 0 && len(s.Held) >= h.logger.settings.retainMax {
+			s.Held[0] = nil
+			s.Held = s.Held[1:]
+		}
+		s.Held = append(s.Held, h)
+		return true
+	}() {
+		return
+	}
+	h.replay()
+}
+
+// releaseHeld sends all held lines. Lines that complete after
+// releaseHeld are not held.
+func (s *shared) releaseHeld() {
+	held := func() []*heldLine {
+		s.HeldLock.Lock()
+		defer s.HeldLock.Unlock()
+		s.HeldReleased = true
+		held := s.Held
+		s.Held = nil
+		return held
+	}()
+	for _, h := range held {
+		h.replay()
+	}
+}
+
+// discardHeld drops all held lines. It is called when the request is
+// done.
+func (s *shared) discardHeld() {
+	s.HeldLock.Lock()
+	defer s.HeldLock.Unlock()
+	s.Held = nil
+}
@@ -0,0 +1,95 @@
+package xop_test
+
+import (
+	"testing"
+
+	"github.com/xoplog/xop-go"
+	"github.com/xoplog/xop-go/xopbase"
+	"github.com/xoplog/xop-go/xopnum"
+	"github.com/xoplog/xop-go/xoprecorder"
+	"github.com/xoplog/xop-go/xoptest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func retainingRequest(t *testing.T, tLog *xoptest.Logger, max int) *xop.Logger {
+	return xop.NewSeed(
+		xop.WithBase(tLog),
+		xop.WithSettings(func(settings *xop.LogSettings) {
+			settings.MinLevel(xopnum.TraceLevel)
+			settings.TailRetention(xopnum.LogLevel, max)
+		}),
+	).Request(t.Name())
+}
+
+func TestTailRetentionBoring(t *testing.T) {
+	tLog := xoptest.New(t)
+	log := retainingRequest(t, tLog, 0)
+	log.Trace().Msg("trace")
+	log.Debug().Int("i", 1).Msg("debug")
+	log.Info().Msg("info")
+	step := log.Sub().Step("step")
+	step.Debug().Msg("step debug")
+	step.Done()
+	log.Done()
+
+	assert.Equal(t, 0, tLog.Recorder().CountLines(xoprecorder.MessageEquals("trace")), "trace discarded")
+	assert.Equal(t, 0, tLog.Recorder().CountLines(xoprecorder.MessageEquals("debug")), "debug discarded")
+	assert.Equal(t, 0, tLog.Recorder().CountLines(xoprecorder.MessageEquals("step debug")), "step debug discarded")
+	assert.Equal(t, 1, tLog.Recorder().CountLines(xoprecorder.MessageEquals("info")), "info kept")
+}
+
+func TestTailRetentionError(t *testing.T) {
+	tLog := xoptest.New(t)
+	log := retainingRequest(t, tLog, 0)
+	log.Debug().Int("i", 1).Msg("debug")
+	log.Info().Msg("info")
+	assert.Equal(t, 0, tLog.Recorder().CountLines(xoprecorder.MessageEquals("debug")), "held")
+	step := log.Sub().Step("step")
+	step.Trace().Msg("step trace")
+	step.Error().Msg("failed")
+	log.Debug().Msg("after")
+	step.Done()
+	log.Done()
+
+	debug := tLog.Recorder().FindLines(xoprecorder.MessageEquals("debug"))
+	require.Len(t, debug, 1, "debug released")
+	assert.Equal(t, int64(1), debug[0].Data["i"])
+	assert.Equal(t, xopnum.DebugLevel, debug[0].Level)
+	info := tLog.Recorder().FindLines(xoprecorder.MessageEquals("info"))
+	require.Len(t, info, 1)
+	assert.False(t, debug[0].Timestamp.After(info[0].Timestamp), "original timestamp")
+	stepTrace := tLog.Recorder().FindLines(xoprecorder.MessageEquals("step trace"))
+	require.Len(t, stepTrace, 1, "step trace released")
+	assert.Equal(t, step.Span().Trace().GetSpanID().String(), stepTrace[0].Span.Bundle.Trace.GetSpanID().String(), "released to the right span")
+	assert.Equal(t, 1, tLog.Recorder().CountLines(xoprecorder.MessageEquals("after")), "not held after error")
+}
+
+func TestTailRetentionNotBoring(t *testing.T) {
+	tLog := xoptest.New(t)
+	log := retainingRequest(t, tLog, 0)
+	m := map[string]int{"a": 1}
+	log.Debug().Any("m", m).Msg("debug")
+	m["a"] = 2
+	log.NotBoring()
+	log.Done()
+
+	debug := tLog.Recorder().FindLines(xoprecorder.MessageEquals("debug"))
+	require.Len(t, debug, 1, "released")
+	assert.Equal(t, map[string]int{"a": 1}, debug[0].Data["m"].(xopbase.ModelArg).Model, "copied when held")
+}
+
+func TestTailRetentionMax(t *testing.T) {
+	tLog := xoptest.New(t)
+	log := retainingRequest(t, tLog, 2)
+	log.Debug().Msg("one")
+	log.Debug().Msg("two")
+	log.Debug().Msg("three")
+	log.Alert().Msg("alert")
+	log.Done()
+
+	assert.Equal(t, 0, tLog.Recorder().CountLines(xoprecorder.MessageEquals("one")), "dropped")
+	assert.Equal(t, 1, tLog.Recorder().CountLines(xoprecorder.MessageEquals("two")))
+	assert.Equal(t, 1, tLog.Recorder().CountLines(xoprecorder.MessageEquals("three")))
+}
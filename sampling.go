@@ -0,0 +1,76 @@
+package xop
+
+import (
+	"encoding/binary"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/xoplog/xop-go/xoptrace"
+)
+
+// Sampler makes the sampling decision for new root traces: requests
+// that do not have a parent trace.  Requests that have a parent trace
+// use the sampling flag from the parent.  The decision is recorded in
+// the trace flags so that it is propagated to downstream services.
+//
+// Samplers must be thread-safe.
+type Sampler func(bundle xoptrace.Bundle, descriptionOrName string) bool
+
+// ProbabilitySampler samples the given fraction of new traces. The
+// decision is based on the TraceID so that it's consistent with other
+// implementations that do the same.
+func ProbabilitySampler(fraction float64) Sampler {
+	switch {
+	case fraction >= 1:
+		return func(xoptrace.Bundle, string) bool { return true }
+	case fraction <= 0:
+		return func(xoptrace.Bundle, string) bool { return false }
+	}
+	threshold := uint64(fraction * math.MaxInt64)
+	return func(bundle xoptrace.Bundle, _ string) bool {
+		return binary.BigEndian.Uint64(bundle.Trace.GetTraceID().Bytes()[8:])>>1 < threshold
+	}
+}
+
+// RateLimitedSampler samples at most perSecond new traces per second.
+// Up to one second's worth can be sampled in a burst.
+func RateLimitedSampler(perSecond float64) Sampler {
+	burst := math.Max(perSecond, 1)
+	var lock sync.Mutex
+	tokens := burst
+	last := time.Now()
+	return func(xoptrace.Bundle, string) bool {
+		lock.Lock()
+		defer lock.Unlock()
+		now := time.Now()
+		tokens = math.Min(burst, tokens+now.Sub(last).Seconds()*perSecond)
+		last = now
+		if tokens < 1 {
+			return false
+		}
+		tokens--
+		return true
+	}
+}
+
+func isSampled(trace xoptrace.Trace) bool {
+	return trace.GetFlags().Bytes()[0]&1 == 1
+}
+
+// sample applies Config.Sampler to new root traces and
+// Config.UnsampledMinLevel to unsampled traces. It runs before the
+// reactive functions so that they can lower the level again.
+func (seed Seed) sample(descriptionOrName string) Seed {
+	if seed.config.Sampler != nil && seed.traceBundle.Parent.GetTraceID().IsZero() {
+		flags := seed.traceBundle.Trace.GetFlags().Bytes()[0] &^ 1
+		if seed.config.Sampler(seed.traceBundle, descriptionOrName) {
+			flags |= 1
+		}
+		seed.traceBundle.Trace.Flags().SetBytes([]byte{flags})
+	}
+	if !isSampled(seed.traceBundle.Trace) && seed.config.UnsampledMinLevel > seed.settings.minimumLogLevel {
+		seed.settings.minimumLogLevel = seed.config.UnsampledMinLevel
+	}
+	return seed
+}
@@ -0,0 +1,89 @@
+package xop_test
+
+import (
+	"testing"
+
+	"github.com/xoplog/xop-go"
+	"github.com/xoplog/xop-go/xopnum"
+	"github.com/xoplog/xop-go/xoprecorder"
+	"github.com/xoplog/xop-go/xoptest"
+	"github.com/xoplog/xop-go/xoptrace"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSamplerRootTrace(t *testing.T) {
+	tLog := xoptest.New(t)
+	seed := xop.NewSeed(xop.WithBase(tLog), xop.WithUnsampledBoring(true))
+
+	log := seed.Copy(xop.WithSampler(xop.ProbabilitySampler(0))).Request("unsampled")
+	assert.Equal(t, "00", log.Span().Trace().GetFlags().String(), "unsampled flags")
+	assert.True(t, log.IsBoring(), "unsampled is boring")
+	child := log.Sub().Fork("child")
+	assert.Equal(t, "00", child.Span().Bundle().Trace.GetFlags().String(), "propagated to child")
+	log.Done()
+
+	log = seed.Copy(xop.WithSampler(xop.ProbabilitySampler(1))).Request("sampled")
+	assert.Equal(t, "01", log.Span().Trace().GetFlags().String(), "sampled flags")
+	assert.False(t, log.IsBoring(), "sampled is not boring")
+	log.Done()
+}
+
+func TestSamplerInboundTrace(t *testing.T) {
+	tLog := xoptest.New(t)
+	parent := xoptrace.NewTrace()
+	parent.TraceID().SetRandom()
+	parent.SpanID().SetRandom()
+	parent.Flags().SetBytes([]byte{0})
+	bundle := xoptrace.NewBundle()
+	bundle.Parent = parent
+	bundle.Trace = parent
+	bundle.Trace.SpanID().SetRandom()
+
+	log := xop.NewSeed(
+		xop.WithBase(tLog),
+		xop.WithBundle(bundle),
+		xop.WithSampler(func(xoptrace.Bundle, string) bool {
+			t.Error("sampler called for trace with a parent")
+			return true
+		}),
+		xop.WithUnsampledMinLevel(xopnum.InfoLevel),
+	).Request(t.Name())
+	assert.Equal(t, "00", log.Span().Trace().GetFlags().String(), "inbound flags kept")
+	assert.False(t, log.IsBoring(), "UnsampledBoring not set")
+	log.Debug().Msg("debug")
+	log.Info().Msg("info")
+	log.Done()
+	assert.Equal(t, 0, tLog.Recorder().CountLines(xoprecorder.MessageEquals("debug")), "debug dropped")
+	assert.Equal(t, 1, tLog.Recorder().CountLines(xoprecorder.MessageEquals("info")), "info kept")
+}
+
+func TestProbabilitySampler(t *testing.T) {
+	sampler := xop.ProbabilitySampler(0.25)
+	var sampled int
+	for i := 0; i < 4000; i++ {
+		bundle := xoptrace.NewBundle()
+		bundle.Trace.TraceID().SetRandom()
+		if sampler(bundle, "") {
+			sampled++
+		}
+		assert.Equal(t, sampler(bundle, ""), sampler(bundle, ""), "consistent")
+	}
+	assert.InDelta(t, 1000, sampled, 150)
+}
+
+func TestRateLimitedSampler(t *testing.T) {
+	sampler := xop.RateLimitedSampler(0.001)
+	bundle := xoptrace.NewBundle()
+	assert.True(t, sampler(bundle, ""), "first")
+	assert.False(t, sampler(bundle, ""), "second")
+
+	sampler = xop.RateLimitedSampler(3)
+	var sampled int
+	for i := 0; i < 10; i++ {
+		if sampler(bundle, "") {
+			sampled++
+		}
+	}
+	assert.Equal(t, 3, sampled, "burst")
+}
@@ -0,0 +1,345 @@
+// This file is generated, DO NOT EDIT.  It comes from the corresponding .zzzgo file
+
+package xop
+
+import (
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/xoplog/xop-go/xopat"
+	"github.com/xoplog/xop-go/xopbase"
+	"github.com/xoplog/xop-go/xopproto"
+	"github.com/xoplog/xop-go/xoptrace"
+)
+
+// ScanAction is what a Scanner does when it finds a match
+type ScanAction int
+
+const (
+	// ScanMask replaces matches with "[redacted:<rule name>]"
+	ScanMask ScanAction = iota
+	// ScanFlag leaves matches in place and adds ScanFlagKey to the
+	// line with the names of the rules that matched.
+	ScanFlag
+)
+
+// ScanFlagKey is the line attribute that lists the rules that
+// matched when using ScanFlag.
+const ScanFlagKey xopat.K = "scan.flagged"
+
+// ScanRule is a pattern for a Scanner to look for. If Validate
+// is set, matches that it rejects are ignored.
+type ScanRule struct {
+	Name     string
+	Pattern  *regexp.Regexp
+	Validate func(match string) bool
+}
+
+var (
+	ScanJWT = ScanRule{
+		Name:    "jwt",
+		Pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`),
+	}
+	ScanBearerToken = ScanRule{
+		Name:    "bearer",
+		Pattern: regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9\-._~+/]+=*`),
+	}
+	ScanAWSKey = ScanRule{
+		Name:    "awskey",
+		Pattern: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),
+	}
+	ScanCreditCard = ScanRule{
+		Name:     "creditcard",
+		Pattern:  regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),
+		Validate: luhn,
+	}
+	ScanEmail = ScanRule{
+		Name:    "email",
+		Pattern: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+	}
+)
+
+// DefaultScanRules returns the built-in rules: JWTs, bearer tokens, AWS access
+// key IDs, credit card numbers, and email addresses.
+func DefaultScanRules() []ScanRule {
+	return []ScanRule{ScanJWT, ScanBearerToken, ScanAWSKey, ScanCreditCard, ScanEmail}
+}
+
+// Scanner looks for secrets and PII inside of log messages, templates,
+// string attributes, and models.  Models are scanned in their encoded
+// form. When a model is masked, only the encoded form is retained.
+//
+// Scanners are expensive compared to the rest of logging: every
+// line is matched against every rule.
+type Scanner struct {
+	action ScanAction
+	rules  []ScanRule
+	masks  []string
+	counts []int64
+}
+
+// NewScanner creates a Scanner. If no rules are provided, DefaultScanRules
+// are used.  To add custom rules to the defaults:
+//
+//	xop.NewScanner(xop.ScanMask, append(xop.DefaultScanRules(), myRule)...)
+func NewScanner(action ScanAction, rules ...ScanRule) *Scanner {
+	if len(rules) == 0 {
+		rules = DefaultScanRules()
+	}
+	s := &Scanner{
+		action: action,
+		rules:  rules,
+		masks:  make([]string, len(rules)),
+		counts: make([]int64, len(rules)),
+	}
+	for i, rule := range rules {
+		s.masks[i] = "[redacted:" + rule.Name + "]"
+	}
+	return s
+}
+
+// Counts returns the number of matches found, by rule name, since the
+// Scanner was created.
+func (s *Scanner) Counts() map[string]int64 {
+	counts := make(map[string]int64)
+	for i, rule := range s.rules {
+		if count := atomic.LoadInt64(&s.counts[i]); count > 0 {
+			counts[rule.Name] += count
+		}
+	}
+	return counts
+}
+
+// scan returns v with matches masked if the action is ScanMask. The
+// names of rules that matched are added to flagged.
+func (s *Scanner) scan(v string, flagged []string) (string, []string) {
+	for i, rule := range s.rules {
+		if !rule.Pattern.MatchString(v) {
+			continue
+		}
+		var found bool
+		v = rule.Pattern.ReplaceAllStringFunc(v, func(match string) string {
+			if rule.Validate != nil && !rule.Validate(match) {
+				return match
+			}
+			atomic.AddInt64(&s.counts[i], 1)
+			found = true
+			if s.action == ScanMask {
+				return s.masks[i]
+			}
+			return match
+		})
+		if found {
+			flagged = addFlag(flagged, rule.Name)
+		}
+	}
+	return v, flagged
+}
+
+func (s *Scanner) scanModel(v xopbase.ModelArg, flagged []string) (xopbase.ModelArg, []string) {
+	v.Encode()
+	switch v.Encoding {
+	case xopproto.Encoding_JSON, xopproto.Encoding_XML, xopproto.Encoding_CSV:
+	default:
+		return v, flagged
+	}
+	enc, flagged := s.scan(string(v.Encoded), flagged)
+	if s.action == ScanMask && enc != string(v.Encoded) {
+		v.Encoded = []byte(enc)
+		v.Model = nil
+	}
+	return v, flagged
+}
+
+func addFlag(flagged []string, name string) []string {
+	for _, f := range flagged {
+		if f == name {
+			return flagged
+		}
+	}
+	return append(flagged, name)
+}
+
+// luhn validates credit card numbers
+func luhn(s string) bool {
+	var sum, n int
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && n <= 19 && sum%10 == 0
+}
+
+// scanBuilder implements xopbase.Builder by applying a Scanner
+// to string attributes and models.
+type scanBuilder struct {
+	builder xopbase.Builder
+	scanner *Scanner
+	flagged []string
+}
+
+// scanLine implements xopbase.Line. It is used when there is a
+// Scanner: see WithScanner.
+type scanLine struct {
+	scanBuilder
+	line xopbase.Line
+}
+
+// scanPrefilling implements xopbase.Prefilling
+type scanPrefilling struct {
+	scanBuilder
+	prefilling xopbase.Prefilling
+}
+
+var (
+	_ xopbase.Line       = &scanLine{}
+	_ xopbase.Prefilling = &scanPrefilling{}
+)
+
+func newScanLine(line xopbase.Line, scanner *Scanner) *scanLine {
+	return &scanLine{
+		scanBuilder: scanBuilder{
+			builder: line,
+			scanner: scanner,
+		},
+		line: line,
+	}
+}
+
+func newScanPrefilling(prefilling xopbase.Prefilling, scanner *Scanner) *scanPrefilling {
+	return &scanPrefilling{
+		scanBuilder: scanBuilder{
+			builder: prefilling,
+			scanner: scanner,
+		},
+		prefilling: prefilling,
+	}
+}
+
+// flag adds ScanFlagKey if there were any matches and the
+// action is ScanFlag
+func (b *scanBuilder) flag() {
+	if len(b.flagged) != 0 && b.scanner.action == ScanFlag {
+		b.builder.String(ScanFlagKey, strings.Join(b.flagged, ","), xopbase.StringDataType)
+	}
+}
+
+func (b *scanBuilder) text(v string) string {
+	v, b.flagged = b.scanner.scan(v, b.flagged)
+	return v
+}
+
+func (p *scanPrefilling) PrefillComplete(msg string) xopbase.Prefilled {
+	msg = p.text(msg)
+	p.flag()
+	return p.prefilling.PrefillComplete(msg)
+}
+
+func (l *scanLine) Msg(msg string) {
+	msg = l.text(msg)
+	l.flag()
+	l.line.Msg(msg)
+}
+
+func (l *scanLine) Template(template string) {
+	template = l.text(template)
+	l.flag()
+	l.line.Template(template)
+}
+
+func (l *scanLine) Link(msg string, v xoptrace.Trace) {
+	msg = l.text(msg)
+	l.flag()
+	l.line.Link(msg, v)
+}
+
+func (l *scanLine) Model(msg string, v xopbase.ModelArg) {
+	msg = l.text(msg)
+	v, l.flagged = l.scanner.scanModel(v, l.flagged)
+	l.flag()
+	l.line.Model(msg, v)
+}
+
+// Table passes along a TableData that shares unchanged
+// rows with t.
+func (l *scanLine) Table(msg string, t xopbase.SimpleTable) {
+	msg = l.text(msg)
+	rows := t.Rows()
+	td := xopbase.TableData{
+		Columns: l.cells(t.Header()),
+		Cells:   make([][]string, len(rows)),
+	}
+	for i, row := range rows {
+		td.Cells[i] = l.cells(row)
+	}
+	l.flag()
+	l.line.Table(msg, td)
+}
+
+// cells returns a copy of row if any cells were masked
+func (b *scanBuilder) cells(row []string) []string {
+	var scanned []string
+	for i, cell := range row {
+		if v := b.text(cell); v != cell {
+			if scanned == nil {
+				scanned = make([]string, len(row))
+				copy(scanned, row)
+			}
+			scanned[i] = v
+		}
+	}
+	if scanned == nil {
+		return row
+	}
+	return scanned
+}
+
+func (b *scanBuilder) Enum(k *xopat.EnumAttribute, v xopat.Enum) {
+	b.builder.Enum(k, v)
+}
+
+func (b *scanBuilder) String(k xopat.K, v string, dt xopbase.DataType) {
+	b.builder.String(k, b.text(v), dt)
+}
+
+func (b *scanBuilder) Any(k xopat.K, v xopbase.ModelArg) {
+	v, b.flagged = b.scanner.scanModel(v, b.flagged)
+	b.builder.Any(k, v)
+}
+
+func (b *scanBuilder) Bool(k xopat.K, v bool) {
+	b.builder.Bool(k, v)
+}
+
+func (b *scanBuilder) Duration(k xopat.K, v time.Duration) {
+	b.builder.Duration(k, v)
+}
+
+func (b *scanBuilder) Time(k xopat.K, v time.Time) {
+	b.builder.Time(k, v)
+}
+
+func (b *scanBuilder) Float64(k xopat.K, v float64, dt xopbase.DataType) {
+	b.builder.Float64(k, v, dt)
+}
+
+func (b *scanBuilder) Int64(k xopat.K, v int64, dt xopbase.DataType) {
+	b.builder.Int64(k, v, dt)
+}
+
+func (b *scanBuilder) Uint64(k xopat.K, v uint64, dt xopbase.DataType) {
+	b.builder.Uint64(k, v, dt)
+}
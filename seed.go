@@ -58,10 +58,12 @@ func (seed Seed) Copy(mods ...SeedModifier) Seed {
 // spanID is randomized and the Parent set to the now prior
 // Trace
 func (span *Span) SubSeed(mods ...SeedModifier) Seed {
+	span.bundleLock.Lock()
 	n := Seed{
 		spanSeed: span.seed.copy(false),
 		settings: span.logger.settings.Copy(),
 	}
+	span.bundleLock.Unlock()
 	n.traceBundle.Parent = n.traceBundle.Trace
 	if !span.seed.spanSet {
 		n.traceBundle.Trace.SpanID().SetRandom()
@@ -169,7 +171,10 @@ func (seed Seed) applyMods(mods []SeedModifier) Seed {
 // starting a cron job.
 func (seed Seed) Request(descriptionOrName string) *Logger {
 	now := time.Now()
-	seed = seed.react(true, descriptionOrName, now)
+	if !seed.traceSet {
+		seed.traceBundle.Trace.RebuildSetNonZero()
+	}
+	seed = seed.sample(descriptionOrName).react(true, descriptionOrName, now)
 	return seed.request(descriptionOrName, now)
 }
 
@@ -179,7 +184,7 @@ func (seed Seed) Request(descriptionOrName string) *Logger {
 // it is meant for handing off from spans created elsewhere.
 func (seed Seed) SubSpan(descriptionOrName string) *Logger {
 	now := time.Now()
-	seed = seed.react(false, descriptionOrName, now)
+	seed = seed.sample(descriptionOrName).react(false, descriptionOrName, now)
 	return seed.request(descriptionOrName, now)
 }
 
@@ -227,6 +232,35 @@ func WithBundle(bundle xoptrace.Bundle) SeedModifier {
 	}
 }
 
+// WithBaggage overrides the baggage in the seed.  Use Baggage.Set
+// and Baggage.Delete on a copy of Seed.Bundle().Baggage to build
+// the new value.
+func WithBaggage(baggage xoptrace.Baggage) SeedModifier {
+	return func(s *Seed) {
+		s.traceBundle.Baggage = baggage
+	}
+}
+
+// WithTraceState overrides the trace state in the seed.
+func WithTraceState(state xoptrace.State) SeedModifier {
+	return func(s *Seed) {
+		s.traceBundle.State = state
+	}
+}
+
+// WithTraceStateEntry adds or updates a vendor entry in the seed's trace
+// state. The entry is moved to the front as required by the W3C
+// trace-context specification. An invalid key or value is reported
+// to the Config.ErrorReporter and the trace state is left unchanged.
+func WithTraceStateEntry(key string, value string) SeedModifier {
+	return func(s *Seed) {
+		err := s.traceBundle.State.Set(key, value)
+		if err != nil && s.config.ErrorReporter != nil {
+			s.config.ErrorReporter(err)
+		}
+	}
+}
+
 // WithSpan overrides the span in the seed. When used inside a reactive
 // function, the override does not propagate.
 func WithSpan(spanID [8]byte) SeedModifier {
@@ -315,12 +349,10 @@ func (seed Seed) SourceInfo() xopbase.SourceInfo {
 // We always clear spanSet because if it had been true, the seed has skipped
 // being randomized and so the next time through we want it randomized so that we
 // don't get two spans with the same id.
+//
+// For Request() and SubSpan(), sampling has already been applied so
+// reactive functions see the sampling decision and can override it.
 func (seed Seed) react(isRequest bool, description string, now time.Time) Seed {
-	if isRequest {
-		if !seed.traceSet {
-			seed.traceBundle.Trace.RebuildSetNonZero()
-		}
-	}
 	seed.traceSet = false
 	seed.spanSet = false
 	if len(seed.reactive) == 0 {
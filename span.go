@@ -24,11 +24,40 @@ func (logger *Logger) Span() *Span {
 	return logger.capSpan
 }
 
-func (span *Span) TraceState() xoptrace.State     { return span.seed.traceBundle.State }
-func (span *Span) TraceBaggage() xoptrace.Baggage { return span.seed.traceBundle.Baggage }
-func (span *Span) ParentTrace() xoptrace.Trace    { return span.seed.traceBundle.Parent.Copy() }
-func (span *Span) Trace() xoptrace.Trace          { return span.seed.traceBundle.Trace.Copy() }
-func (span *Span) Bundle() xoptrace.Bundle        { return span.seed.traceBundle.Copy() }
+func (span *Span) TraceState() xoptrace.State  { return span.seed.traceBundle.State }
+func (span *Span) ParentTrace() xoptrace.Trace { return span.seed.traceBundle.Parent.Copy() }
+func (span *Span) Trace() xoptrace.Trace       { return span.seed.traceBundle.Trace.Copy() }
+
+func (span *Span) TraceBaggage() xoptrace.Baggage {
+	span.bundleLock.Lock()
+	defer span.bundleLock.Unlock()
+	return span.seed.traceBundle.Baggage
+}
+
+func (span *Span) Bundle() xoptrace.Bundle {
+	span.bundleLock.Lock()
+	defer span.bundleLock.Unlock()
+	return span.seed.traceBundle.Copy()
+}
+
+// SetBaggage adds or replaces a baggage member.  The change is
+// visible to spans and seeds created from this span after the call
+// (SubSeed, Fork, Step) and to anything that uses Bundle() or
+// TraceBaggage() to build outgoing headers.  Base loggers recorded
+// the baggage when the span started and are not informed of the change.
+func (span *Span) SetBaggage(key string, value string, properties ...xoptrace.BaggageProperty) error {
+	span.bundleLock.Lock()
+	defer span.bundleLock.Unlock()
+	return span.seed.traceBundle.Baggage.Set(key, value, properties...)
+}
+
+// DeleteBaggage removes a baggage member. See SetBaggage for
+// how changes propagate.
+func (span *Span) DeleteBaggage(key string) {
+	span.bundleLock.Lock()
+	defer span.bundleLock.Unlock()
+	span.seed.traceBundle.Baggage.Delete(key)
+}
 
 func (span *Span) eft() *Span {
 	span.logger.hasActivity(true)
@@ -38,6 +67,9 @@ func (span *Span) eft() *Span {
 // Int64 adds a int64 key/value attribute to the current Span.
 // The return value does not need to be used.
 func (span *Span) Int64(k *xopat.Int64Attribute, v int64) *Span {
+	if _, action := span.redaction(k); action != RedactionPass {
+		return span
+	}
 	span.base.MetadataInt64(k, v)
 	return span.eft()
 }
@@ -63,10 +95,7 @@ func (span *Span) EmbeddedEnum(kv xopat.EmbeddedEnum) *Span {
 // on the base logger being used.
 // The return value does not need to be used.
 func (span *Span) AnyImmutable(k *xopat.AnyAttribute, v interface{}) *Span {
-	span.base.MetadataAny(k, xopbase.ModelArg{
-		Model: v,
-	})
-	return span.eft()
+	return span.metadataAny(k, v)
 }
 
 // Any adds a key/value attribute to the current Span.  The provided
@@ -80,15 +109,46 @@ func (span *Span) Any(k *xopat.AnyAttribute, v interface{}) *Span {
 	if span.logger.span.referencesKept {
 		v = deepcopy.Copy(v)
 	}
+	return span.metadataAny(k, v)
+}
+
+func (span *Span) metadataAny(k *xopat.AnyAttribute, v interface{}) *Span {
+	switch policy, action := span.redaction(k); action {
+	case RedactionPass:
+		if policy != nil {
+			v = policy.redactModel(v)
+		}
+	case RedactionMask, RedactionHash:
+		v = policy.replacement(action, xopbase.ModelArg{Model: v})
+	default:
+		return span
+	}
 	span.base.MetadataAny(k, xopbase.ModelArg{
 		Model: v,
 	})
 	return span.eft()
 }
 
+// String adds a string key/value attribute to the current Span.
+// The return value does not need to be used.
+func (span *Span) String(k *xopat.StringAttribute, v string) *Span {
+	switch policy, action := span.redaction(k); action {
+	case RedactionPass:
+	case RedactionMask, RedactionHash:
+		v = policy.replacement(action, v)
+	default:
+		return span
+	}
+	span.base.MetadataString(k, v)
+	return span.eft()
+}
+
 // Bool adds a bool key/value attribute to the current Span.
 // The return value does not need to be used.
 func (span *Span) Bool(k *xopat.BoolAttribute, v bool) *Span {
+	if _, action := span.redaction(k); action != RedactionPass {
+		return span
+	}
 	span.base.MetadataBool(k, v)
 	return span.eft()
 }
@@ -96,6 +156,9 @@ func (span *Span) Bool(k *xopat.BoolAttribute, v bool) *Span {
 // Enum adds a xopat.Enum key/value attribute to the current Span.
 // The return value does not need to be used.
 func (span *Span) Enum(k *xopat.EnumAttribute, v xopat.Enum) *Span {
+	if _, action := span.redaction(k); action != RedactionPass {
+		return span
+	}
 	span.base.MetadataEnum(k, v)
 	return span.eft()
 }
@@ -103,6 +166,9 @@ func (span *Span) Enum(k *xopat.EnumAttribute, v xopat.Enum) *Span {
 // Float64 adds a float64 key/value attribute to the current Span.
 // The return value does not need to be used.
 func (span *Span) Float64(k *xopat.Float64Attribute, v float64) *Span {
+	if _, action := span.redaction(k); action != RedactionPass {
+		return span
+	}
 	span.base.MetadataFloat64(k, v)
 	return span.eft()
 }
@@ -110,20 +176,19 @@ func (span *Span) Float64(k *xopat.Float64Attribute, v float64) *Span {
 // Link adds a xoptrace.Trace key/value attribute to the current Span.
 // The return value does not need to be used.
 func (span *Span) Link(k *xopat.LinkAttribute, v xoptrace.Trace) *Span {
+	if _, action := span.redaction(k); action != RedactionPass {
+		return span
+	}
 	span.base.MetadataLink(k, v)
 	return span.eft()
 }
 
-// String adds a string key/value attribute to the current Span.
-// The return value does not need to be used.
-func (span *Span) String(k *xopat.StringAttribute, v string) *Span {
-	span.base.MetadataString(k, v)
-	return span.eft()
-}
-
 // Time adds a time.Time key/value attribute to the current Span.
 // The return value does not need to be used.
 func (span *Span) Time(k *xopat.TimeAttribute, v time.Time) *Span {
+	if _, action := span.redaction(k); action != RedactionPass {
+		return span
+	}
 	span.base.MetadataTime(k, v)
 	return span.eft()
 }
@@ -132,6 +197,9 @@ func (span *Span) Time(k *xopat.TimeAttribute, v time.Time) *Span {
 // Duration adds a time.Duration key/value attribute to the current Span.
 // The return value does not need to be used.
 func (span *Span) Duration(k *xopat.DurationAttribute, v time.Duration) *Span {
+	if _, action := span.redaction(k); action != RedactionPass {
+		return span
+	}
 	span.base.MetadataInt64(&k.Int64Attribute, int64(v))
 	return span.eft()
 }
@@ -139,6 +207,9 @@ func (span *Span) Duration(k *xopat.DurationAttribute, v time.Duration) *Span {
 // Int adds a int key/value attribute to the current Span.
 // The return value does not need to be used.
 func (span *Span) Int(k *xopat.IntAttribute, v int) *Span {
+	if _, action := span.redaction(k); action != RedactionPass {
+		return span
+	}
 	span.base.MetadataInt64(&k.Int64Attribute, int64(v))
 	return span.eft()
 }
@@ -146,6 +217,9 @@ func (span *Span) Int(k *xopat.IntAttribute, v int) *Span {
 // Int16 adds a int16 key/value attribute to the current Span.
 // The return value does not need to be used.
 func (span *Span) Int16(k *xopat.Int16Attribute, v int16) *Span {
+	if _, action := span.redaction(k); action != RedactionPass {
+		return span
+	}
 	span.base.MetadataInt64(&k.Int64Attribute, int64(v))
 	return span.eft()
 }
@@ -153,6 +227,9 @@ func (span *Span) Int16(k *xopat.Int16Attribute, v int16) *Span {
 // Int32 adds a int32 key/value attribute to the current Span.
 // The return value does not need to be used.
 func (span *Span) Int32(k *xopat.Int32Attribute, v int32) *Span {
+	if _, action := span.redaction(k); action != RedactionPass {
+		return span
+	}
 	span.base.MetadataInt64(&k.Int64Attribute, int64(v))
 	return span.eft()
 }
@@ -160,6 +237,9 @@ func (span *Span) Int32(k *xopat.Int32Attribute, v int32) *Span {
 // Int8 adds a int8 key/value attribute to the current Span.
 // The return value does not need to be used.
 func (span *Span) Int8(k *xopat.Int8Attribute, v int8) *Span {
+	if _, action := span.redaction(k); action != RedactionPass {
+		return span
+	}
 	span.base.MetadataInt64(&k.Int64Attribute, int64(v))
 	return span.eft()
 }
@@ -32,49 +32,6 @@ type Detaching struct {
 	sub *Sub
 }
 
-// RedactAnyFunc is used to redact models as they're being logged.
-// It is RedactAnyFunc's responsibility to call
-//
-//	baseLine.Any(k, xopbase.ModelArg{
-//		Model: v,
-//	})
-//
-// if it wants the value to be logged.  If it does make that call, it
-// must pass an immutable value.  Perhaps use "github.com/mohae/deepcopy"
-// to make a copy?
-//
-// The provided xopbase.Line may not be retained beyond the duration of
-// the function call.
-type RedactAnyFunc func(baseLine xopbase.Line, k xopat.K, v interface{}, alreadyImmutable bool)
-
-// RedactStringFunc is used to redact strings as they're being logged.
-// It is RedactStringFunc's responsiblity to call
-//
-//	baseLine.String(k, v, xopbase.StringDataType)
-//
-// if it wants the value to be logged.
-//
-// RedactStringFunc is applied only to String(), and Stringer() attributes.
-// It is not applied to Msg(), Msgf(), or Msgs().
-//
-// The provided xopbase.Line may not be retained beyond the duration of
-// the function call.
-type RedactStringFunc func(baseLine xopbase.Line, k xopat.K, v string)
-
-// RedactErrorFunc is used to redact or format errors as they're being
-// logged.  It is RedactErrorFunc's responsibility to call
-//
-//	baseLine.String(k, v.Error(), xopbase.ErrorDataType)
-//
-// if it wants the value to be logged.  Alternatively, it could log the
-// error as a model:
-//
-//	baseLine.Any(k, v)
-//
-// The provided xopbase.Line may not be retained beyond the duration of
-// the function call.
-type RedactErrorFunc func(baseLine xopbase.Line, k xopat.K, v error)
-
 type LogSettings struct {
 	prefillMsg               string
 	prefillData              []func(xopbase.Prefilling)
@@ -82,10 +39,16 @@ type LogSettings struct {
 	stackFramesWanted        [xopnum.AlertLevel + 1]int // indexed
 	tagLinesWithSpanSequence bool
 	synchronousFlushWhenDone bool
-	redactAny                RedactAnyFunc
-	redactString             RedactStringFunc
-	redactError              RedactErrorFunc
 	stackFilenameRewrite     func(string) string
+	retainBelow              xopnum.Level
+	retainMax                int
+	levelAdjuster            *levelAdjuster
+	rateLimit                int
+	rateLimitInterval        time.Duration
+	rateLimitBy              RateLimitKey
+	budgetLines              int
+	budgetBytes              int
+	budgetSample             int
 }
 
 // String is for debugging purposes. It is not complete or preformant.
@@ -103,6 +66,15 @@ func (settings LogSettings) String() string {
 	if settings.synchronousFlushWhenDone {
 		str += " flush-when-done"
 	}
+	if settings.rateLimit != 0 {
+		str += " rateLimit:" + strconv.Itoa(settings.rateLimit) + "/" + settings.rateLimitInterval.String()
+	}
+	if settings.budgetLines != 0 || settings.budgetBytes != 0 {
+		str += " budget:" + strconv.Itoa(settings.budgetLines) + "/" + strconv.Itoa(settings.budgetBytes)
+	}
+	if settings.retainBelow != 0 {
+		str += " retainBelow:" + settings.retainBelow.String()
+	}
 	return str
 }
 
@@ -138,9 +110,19 @@ func (logger *Logger) Settings() LogSettings {
 // sub.Step().
 //
 // Logs created from Sub() are done when their parent is done.
+//
+// If the logger came from a LevelAdjuster or ContextLevelAdjuster, the
+// minimum log level is refreshed from the adjuster's LevelRegistry
+// unless it has since been set with MinLevel.
 func (logger *Logger) Sub() *Sub {
+	settings := logger.settings.Copy()
+	if settings.levelAdjuster != nil {
+		if level := settings.levelAdjuster.level(logger); level != 0 {
+			settings.minimumLogLevel = level
+		}
+	}
 	return &Sub{
-		settings: logger.settings.Copy(),
+		settings: settings,
 		logger:   logger,
 	}
 }
@@ -250,14 +232,116 @@ func (sub *Sub) MinLevel(level xopnum.Level) *Sub {
 
 // MinLevel sets the minimum logging level below which logs will
 // be discarded. The default minimum level comes from DefaultSettings.
+// An explicit minimum level is not refreshed by a LevelAdjuster.
 func (settings *LogSettings) MinLevel(level xopnum.Level) {
 	settings.minimumLogLevel = level
+	settings.levelAdjuster = nil
 }
 
 func (settings LogSettings) GetMinLevel() xopnum.Level {
 	return settings.minimumLogLevel
 }
 
+// TailRetention holds lines below the given level in memory
+// instead of sending them to the base loggers. If the request
+// later logs at the Error or Alert level, or NotBoring() is
+// called, the held lines are sent with their original timestamps.
+// If the request is Done without that happening, the held lines are
+// discarded. This allows logging at a high verbosity without keeping
+// the output for boring requests.
+//
+// Lines below the minimum level (MinLevel) are discarded as usual.
+// If max is greater than zero, at most max lines are held for the
+// request, and older lines are dropped first.
+// A level of zero turns off tail retention.
+func (sub *Sub) TailRetention(level xopnum.Level, max int) *Sub {
+	sub.settings.TailRetention(level, max)
+	return sub
+}
+
+// TailRetention holds lines below the given level in memory
+// instead of sending them to the base loggers. If the request
+// later logs at the Error or Alert level, or NotBoring() is
+// called, the held lines are sent with their original timestamps.
+// If the request is Done without that happening, the held lines are
+// discarded. This allows logging at a high verbosity without keeping
+// the output for boring requests.
+//
+// Lines below the minimum level (MinLevel) are discarded as usual.
+// If max is greater than zero, at most max lines are held for the
+// request, and older lines are dropped first.
+// A level of zero turns off tail retention.
+func (settings *LogSettings) TailRetention(level xopnum.Level, max int) {
+	settings.retainBelow = level
+	settings.retainMax = max
+}
+
+// RateLimit limits how many lines can be logged by each call site
+// (RateLimitByCaller) or with each message or template
+// (RateLimitByMessage) per interval within a span.  Lines beyond the
+// limit are counted rather than logged. A summary line ("suppressed 4,211
+// similar lines") is logged when the next line with the same key arrives
+// after the interval ends, or when the span is Done.
+//
+// RateLimitByCaller is cheaper because lines are suppressed before any
+// data is added to them. With RateLimitByMessage, lines are buffered
+// until their message is known: suppressed lines do not reach the base
+// loggers, count against the Budget, or make the request not boring.
+// A limit of zero turns off rate limiting.
+func (sub *Sub) RateLimit(limit int, interval time.Duration, by RateLimitKey) *Sub {
+	sub.settings.RateLimit(limit, interval, by)
+	return sub
+}
+
+// RateLimit limits how many lines can be logged by each call site
+// (RateLimitByCaller) or with each message or template
+// (RateLimitByMessage) per interval within a span.  Lines beyond the
+// limit are counted rather than logged. A summary line ("suppressed 4,211
+// similar lines") is logged when the next line with the same key arrives
+// after the interval ends, or when the span is Done.
+//
+// RateLimitByCaller is cheaper because lines are suppressed before any
+// data is added to them. With RateLimitByMessage, lines are buffered
+// until their message is known: suppressed lines do not reach the base
+// loggers, count against the Budget, or make the request not boring.
+// A limit of zero turns off rate limiting.
+func (settings *LogSettings) RateLimit(limit int, interval time.Duration, by RateLimitKey) {
+	settings.rateLimit = limit
+	settings.rateLimitInterval = interval
+	settings.rateLimitBy = by
+}
+
+// Budget caps the number of lines and/or the (estimated) number of
+// bytes logged per request.  The counts are shared by all the spans of
+// the request.  Once the budget is exhausted, lines are dropped except that,
+// if sampleEvery is greater than zero, one out of every sampleEvery lines
+// is still logged.  When the request is Done, the number of lines dropped
+// at each level is recorded as the xopconst.LinesDropped attribute on the
+// request.
+//
+// Byte sizes are estimated before encoding by the base loggers.
+// Zero for lines or bytes means that there is no limit of that kind.
+func (sub *Sub) Budget(lines int, bytes int, sampleEvery int) *Sub {
+	sub.settings.Budget(lines, bytes, sampleEvery)
+	return sub
+}
+
+// Budget caps the number of lines and/or the (estimated) number of
+// bytes logged per request.  The counts are shared by all the spans of
+// the request.  Once the budget is exhausted, lines are dropped except that,
+// if sampleEvery is greater than zero, one out of every sampleEvery lines
+// is still logged.  When the request is Done, the number of lines dropped
+// at each level is recorded as the xopconst.LinesDropped attribute on the
+// request.
+//
+// Byte sizes are estimated before encoding by the base loggers.
+// Zero for lines or bytes means that there is no limit of that kind.
+func (settings *LogSettings) Budget(lines int, bytes int, sampleEvery int) {
+	settings.budgetLines = lines
+	settings.budgetBytes = bytes
+	settings.budgetSample = sampleEvery
+}
+
 // TagLinesWithSpanSequence controls if the span sequence
 // indicator (see Fork() and Step()) should be included in
 // the prefill data on each line.
@@ -301,7 +385,13 @@ func (logger *Logger) sendPrefill() {
 		logger.prefilled = logger.span.base.NoPrefill()
 		return
 	}
-	prefilling := logger.span.base.StartPrefill()
+	var prefilling xopbase.Prefilling = logger.span.base.StartPrefill()
+	if scanner := logger.span.seed.config.Scanner; scanner != nil {
+		prefilling = newScanPrefilling(prefilling, scanner)
+	}
+	if policy := logger.span.seed.config.Redaction; policy != nil {
+		prefilling = newRedactPrefilling(prefilling, policy)
+	}
 	for _, f := range logger.settings.prefillData {
 		f(prefilling)
 	}
@@ -635,42 +725,3 @@ func (settings *LogSettings) PrefillUintptr(k xopat.K, v uintptr) {
 		line.Uint64(k, uint64(v), xopbase.UintptrDataType)
 	})
 }
-
-// SetRedactAnyFunc sets a redaction function to be used
-// when Line.Any() is called.
-func (sub *Sub) SetRedactAnyFunc(f RedactAnyFunc) *Sub {
-	sub.settings.SetRedactAnyFunc(f)
-	return sub
-}
-
-// SetRedactErrorFunc sets a redaction function to be used
-// when Line.Error() is called.
-func (sub *Sub) SetRedactErrorFunc(f RedactErrorFunc) *Sub {
-	sub.settings.SetRedactErrorFunc(f)
-	return sub
-}
-
-// SetRedactStringFunc sets a redaction function to be used
-// when Line.String() is called.
-func (sub *Sub) SetRedactStringFunc(f RedactStringFunc) *Sub {
-	sub.settings.SetRedactStringFunc(f)
-	return sub
-}
-
-// SetRedactAnyFunc sets a redaction function to be used
-// when Line.Any() is called.
-func (settings *LogSettings) SetRedactAnyFunc(f RedactAnyFunc) {
-	settings.redactAny = f
-}
-
-// SetRedactErrorFunc sets a redaction function to be used
-// when Line.Error() is called.
-func (settings *LogSettings) SetRedactErrorFunc(f RedactErrorFunc) {
-	settings.redactError = f
-}
-
-// SetRedactStringFunc sets a redaction function to be used
-// when Line.String() is called.
-func (settings *LogSettings) SetRedactStringFunc(f RedactStringFunc) {
-	settings.redactString = f
-}
@@ -136,6 +136,7 @@ var macros = map[string]map[string]string{
 	"LineEndersWithData": {
 		"Link":  "xoptrace.Trace",
 		"Model": "xopbase.ModelArg",
+		"Table": "xopbase.SimpleTable",
 	},
 	"LineEndersWithoutData": {
 		"Msg":      "string",
@@ -0,0 +1,57 @@
+package xop
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/xoplog/xop-go/xopnum"
+)
+
+// DefaultVerbosityKey is the baggage key suggested for use with
+// VerbosityFromBaggage.
+const DefaultVerbosityKey = "xop-debug"
+
+// VerbosityFromBaggage returns a SeedReactiveCallback that lowers the
+// minimum log level of requests and spans whose trace baggage includes
+// key.  Since baggage is propagated, adding the key to an inbound
+// request turns on verbose logging for that request across every
+// service that it touches.
+//
+// The value of the baggage member can be a level name or number (for
+// example "xop-debug=trace") in which case that level is used. Otherwise
+// the level passed to VerbosityFromBaggage is used. Values of "0", "false",
+// and "off" are ignored.  The minimum log level is only ever lowered.
+//
+//	seed := xop.NewSeed(xop.WithReactive(xop.VerbosityFromBaggage(xop.DefaultVerbosityKey, xopnum.TraceLevel)))
+//
+// See xopmiddle.Inbound.WithVerbosityHeader for turning an HTTP header
+// into baggage.
+func VerbosityFromBaggage(key string, level xopnum.Level) SeedReactiveCallback {
+	return func(_ context.Context, seed Seed, _ string, _ bool, _ time.Time) []SeedModifier {
+		value, ok := seed.traceBundle.Baggage.Get(key)
+		if !ok {
+			return nil
+		}
+		requested, ok := verbosityLevel(value, level)
+		if !ok || requested >= seed.settings.minimumLogLevel {
+			return nil
+		}
+		return []SeedModifier{WithSettings(func(settings *LogSettings) {
+			settings.MinLevel(requested)
+		})}
+	}
+}
+
+func verbosityLevel(value string, level xopnum.Level) (xopnum.Level, bool) {
+	switch strings.ToLower(value) {
+	case "0", "false", "off":
+		return 0, false
+	case "", "1", "true", "on":
+		return level, true
+	}
+	if l, err := parseLevel(value); err == nil && l != 0 {
+		return l, true
+	}
+	return level, true
+}
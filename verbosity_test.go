@@ -0,0 +1,68 @@
+package xop_test
+
+import (
+	"testing"
+
+	"github.com/xoplog/xop-go"
+	"github.com/xoplog/xop-go/xopnum"
+	"github.com/xoplog/xop-go/xoprecorder"
+	"github.com/xoplog/xop-go/xoptest"
+	"github.com/xoplog/xop-go/xoptrace"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerbosityFromBaggage(t *testing.T) {
+	cases := []struct {
+		baggage string
+		want    xopnum.Level
+	}{
+		{baggage: "", want: xopnum.DebugLevel},
+		{baggage: "other=1", want: xopnum.DebugLevel},
+		{baggage: "xop-debug=1", want: xopnum.TraceLevel},
+		{baggage: "xop-debug=off", want: xopnum.DebugLevel},
+		{baggage: "xop-debug=trace", want: xopnum.TraceLevel},
+		{baggage: "xop-debug=warn", want: xopnum.DebugLevel},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.baggage, func(t *testing.T) {
+			tLog := xoptest.New(t)
+			bundle := xoptrace.NewBundle()
+			bundle.Baggage.SetString(tc.baggage)
+			log := xop.NewSeed(
+				xop.WithBase(tLog),
+				xop.WithBundle(bundle),
+				xop.WithReactive(xop.VerbosityFromBaggage(xop.DefaultVerbosityKey, xopnum.TraceLevel)),
+			).Request(t.Name())
+			assert.Equal(t, tc.want, log.Settings().GetMinLevel(), "request")
+			child := log.Sub().MinLevel(xopnum.InfoLevel).Fork("child")
+			if tc.want == xopnum.TraceLevel {
+				assert.Equal(t, xopnum.TraceLevel, child.Settings().GetMinLevel(), "child")
+			}
+			child.Trace().Msg("trace")
+			log.Done()
+			if tc.want == xopnum.TraceLevel {
+				assert.Equal(t, 1, tLog.Recorder().CountLines(xoprecorder.MessageEquals("trace")))
+			}
+		})
+	}
+}
+
+func TestVerbosityFromBaggageUnsampled(t *testing.T) {
+	tLog := xoptest.New(t)
+	bundle := xoptrace.NewBundle()
+	bundle.Baggage.SetString("xop-debug=1")
+	log := xop.NewSeed(
+		xop.WithBase(tLog),
+		xop.WithBundle(bundle),
+		xop.WithSampler(xop.ProbabilitySampler(0)),
+		xop.WithUnsampledMinLevel(xopnum.WarnLevel),
+		xop.WithReactive(xop.VerbosityFromBaggage(xop.DefaultVerbosityKey, xopnum.TraceLevel)),
+	).Request(t.Name())
+	assert.Equal(t, "00", log.Span().Trace().GetFlags().String(), "unsampled")
+	assert.Equal(t, xopnum.TraceLevel, log.Settings().GetMinLevel(), "baggage wins")
+	log.Trace().Msg("trace")
+	log.Done()
+	assert.Equal(t, 1, tLog.Recorder().CountLines(xoprecorder.MessageEquals("trace")))
+}
@@ -91,6 +91,9 @@ type Make struct {
 	Distinct    bool   `json:"distinct"` // when keeping all values, only keep distinct values (not supported for interface{})
 	Ranged      bool   `json:"ranged"`   // hint: comparisons between values are meaningful (eg: time, integers)
 	Locked      bool   `json:"locked"`   // only keep the first value
+
+	// Sensitive classifies the attribute's values for redaction. See Sensitivity.
+	Sensitive Sensitivity `json:"sensitive,omitempty"`
 }
 
 // Can't use MACRO for these since default values are needed
@@ -176,6 +179,9 @@ func (s Make) make(registry *Registry, exampleValue interface{}, subType Attribu
 	ra.jsonDef = jsonAttributeDefinition(&ra)
 	ra.jsonDefString = string(ra.jsonDef)
 	registry.registeredNames[s.Key] = &ra
+	if s.Sensitive != "" && registry == defaultRegistry {
+		sensitiveKeys.Store(ra.key, s.Sensitive)
+	}
 	registry.allAttributes = append(registry.allAttributes, &ra)
 	return ra, nil
 }
@@ -208,6 +214,7 @@ func (r Attribute) Ranged() bool                      { return r.properties.Rang
 func (r Attribute) Locked() bool                      { return r.properties.Locked }
 func (r Attribute) Distinct() bool                    { return r.properties.Distinct }
 func (r Attribute) Prominence() int                   { return r.properties.Prominence }
+func (r Attribute) Sensitivity() Sensitivity          { return r.properties.Sensitive }
 func (r Attribute) RegistrationNumber() int32         { return r.number }
 func (r Attribute) ExampleValue() interface{}         { return r.exampleValue }
 func (r Attribute) TypeName() string                  { return r.typeName }
@@ -235,6 +242,7 @@ type AttributeInterface interface {
 	Locked() bool
 	Distinct() bool
 	Prominence() int
+	Sensitivity() Sensitivity
 	RegistrationNumber() int32
 	ExampleValue() interface{}
 	TypeName() string
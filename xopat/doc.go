@@ -59,5 +59,18 @@ fmt.Stringer.  Since that isn't possible, enums must implement
 String() and Int64() methods and you cannot define methods on
 third-party types.
 
+Metrics
+
+Metric keys are registered the same way but they are not used as span
+metadata. Instead they record float64 values to a span:
+
+	Make{}.CounterAttribute()   // values are increments
+	Make{}.GaugeAttribute()     // values replace the prior value
+	Make{}.HistogramAttribute() // values are observations
+
+For example:
+
+	log.Span().Counter(RowsRead).Add(37)
+
 */
 package xopat
@@ -0,0 +1,80 @@
+package xopat
+
+import (
+	"github.com/xoplog/xop-go/xopproto"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	AttributeTypeCounter   = AttributeType(xopproto.AttributeType_Counter)
+	AttributeTypeGauge     = AttributeType(xopproto.AttributeType_Gauge)
+	AttributeTypeHistogram = AttributeType(xopproto.AttributeType_Histogram)
+)
+
+// IsMetric is true for the AttributeTypes that are used to record
+// metrics rather than span metadata.
+func (at AttributeType) IsMetric() bool {
+	switch at {
+	case AttributeTypeCounter, AttributeTypeGauge, AttributeTypeHistogram:
+		return true
+	default:
+		return false
+	}
+}
+
+// MetricAttribute is the common type for the keys that are used to
+// record metrics on spans. What kind of metric it is can be found
+// with SubType(): AttributeTypeCounter, AttributeTypeGauge, or
+// AttributeTypeHistogram.  All metric values are float64.
+//
+// Base loggers receive a MetricAttribute. Users of xop use the more
+// specific CounterAttribute, GaugeAttribute, and HistogramAttribute.
+type MetricAttribute struct{ Attribute }
+
+// CounterAttribute represents a metric that only goes up.
+// Values provided to a counter are increments.
+type CounterAttribute struct{ MetricAttribute }
+
+// GaugeAttribute represents a metric that can go up and down.
+// Values provided to a gauge replace the prior value.
+type GaugeAttribute struct{ MetricAttribute }
+
+// HistogramAttribute represents a metric where each value
+// is an observation to be aggregated into a distribution.
+type HistogramAttribute struct{ MetricAttribute }
+
+// Can't use MACRO for these since there is no zzz type
+
+func (s Make) CounterAttribute() *CounterAttribute {
+	return &CounterAttribute{MetricAttribute{Attribute: s.attribute(defaultRegistry, float64(0), nil, AttributeTypeCounter)}}
+}
+
+func (s Make) TryCounterAttribute() (_ *CounterAttribute, err error) {
+	return &CounterAttribute{MetricAttribute{Attribute: s.attribute(defaultRegistry, float64(0), &err, AttributeTypeCounter)}}, err
+}
+
+func (s Make) GaugeAttribute() *GaugeAttribute {
+	return &GaugeAttribute{MetricAttribute{Attribute: s.attribute(defaultRegistry, float64(0), nil, AttributeTypeGauge)}}
+}
+
+func (s Make) TryGaugeAttribute() (_ *GaugeAttribute, err error) {
+	return &GaugeAttribute{MetricAttribute{Attribute: s.attribute(defaultRegistry, float64(0), &err, AttributeTypeGauge)}}, err
+}
+
+func (s Make) HistogramAttribute() *HistogramAttribute {
+	return &HistogramAttribute{MetricAttribute{Attribute: s.attribute(defaultRegistry, float64(0), nil, AttributeTypeHistogram)}}
+}
+
+func (s Make) TryHistogramAttribute() (_ *HistogramAttribute, err error) {
+	return &HistogramAttribute{MetricAttribute{Attribute: s.attribute(defaultRegistry, float64(0), &err, AttributeTypeHistogram)}}, err
+}
+
+// ConstructMetricAttribute is intended for use during replay of logs.
+// The AttributeType must be one of the metric types.
+func (r *Registry) ConstructMetricAttribute(s Make, t AttributeType) (_ *MetricAttribute, err error) {
+	if !t.IsMetric() {
+		return nil, errors.Errorf("cannot override %s to be a metric", t)
+	}
+	return &MetricAttribute{Attribute: s.attribute(r, float64(0), &err, t)}, err
+}
@@ -0,0 +1,33 @@
+package xopat
+
+import (
+	"sync"
+)
+
+// Sensitivity classifies attribute values that may need to be redacted:
+// for example, personally identifiable information. The zero value means
+// that the values are not sensitive.  What is done with sensitive
+// values is decided by a redaction policy, see xop.RedactionPolicy.
+//
+// Sensitivity classes beyond the ones defined here can be used.
+type Sensitivity string
+
+const (
+	NotSensitive Sensitivity = ""
+	PII          Sensitivity = "pii"    // personally identifiable information: emails, names, addresses
+	Secret       Sensitivity = "secret" // credentials, tokens, keys
+)
+
+// sensitiveKeys maps K to Sensitivity for attributes in the default registry
+var sensitiveKeys sync.Map
+
+// SensitivityOf returns the Sensitivity of the attribute, in the default
+// registry, that has the given key.  Since line attributes are keyed by
+// K rather than by attribute, this allows line attributes to be classified
+// by registering an attribute with the same key.
+func SensitivityOf(k K) Sensitivity {
+	if s, ok := sensitiveKeys.Load(k); ok {
+		return s.(Sensitivity)
+	}
+	return NotSensitive
+}
@@ -145,20 +145,33 @@ type Span interface {
 	// metadata recording.
 	MetadataInt64(*xopat.Int64Attribute, int64)
 
-	// Boring true indicates that a span (or request) is boring.  The
-	// suggested meaning for this is that a boring request that is buffered
-	// can ignore Flush() and never get sent to output.  A boring span
-	// can be un-indexed. Boring requests that do get sent to output can
-	// be marked as boring so that they're dropped at the indexing stage.
+	// Boring true indicates that a request is boring.  A boring request
+	// that is buffered should ignore Flush() and never get sent to output
+	// unless it has lines at the Error or Alert level.  Boring requests
+	// that do get sent to output should be marked as boring (with the
+	// xopconst.Boring attribute) so that they can be dropped at the
+	// indexing stage.  Boring false undoes an earlier Boring true.
+	//
+	// Boring returns true if the base logger honored the call: the
+	// output will be dropped or marked.  Boring is only honored for
+	// requests. Calls to Boring on spans that are not requests should
+	// return false.
 	//
 	// Calls to Boring are single-threaded with respect to other calls to
-	// Boring.  NOT YET IMPLEMENTED.
-	Boring(bool)
+	// Boring.
+	Boring(bool) bool
 
 	// ID must return the same string as the Logger it came from
 	ID() string
 
-	// TODO: Gauge()
+	// Metric records a value for a metric. The kind of metric
+	// is indicated by k.SubType(): for AttributeTypeCounter the
+	// value is an increment; for AttributeTypeGauge it is the
+	// current value; for AttributeTypeHistogram it is an observation.
+	// Calls to Metric can be concurrent with other calls to Metric
+	// and with calls to set Metadata.
+	Metric(k *xopat.MetricAttribute, v float64, t time.Time)
+
 	// TODO: Event()
 
 	// NoPrefill must work in parallel with other calls to NoPrefill, Span,
@@ -212,6 +225,9 @@ type Prefilled interface {
 	Line(xopnum.Level, time.Time, []runtime.Frame) Line
 }
 
+// Line is a log line that is being built. A Line may be abandoned
+// without any LineDone method being called, for example when it is
+// suppressed by rate limiting. Base loggers must tolerate that.
 type Line interface {
 	Builder
 	LineDone
@@ -225,6 +241,9 @@ type LineDone interface {
 	// Object may change in the future to also take an un-redaction string,
 	Model(string, ModelArg)
 	Link(string, xoptrace.Trace)
+	// Table must not retain t after it returns: copy it with
+	// NewTableData if needed.
+	Table(string, SimpleTable)
 
 	// TODO: support special table handling inside Models
 	// TODO: ExternalReference(name string, itemID string, storageID string)
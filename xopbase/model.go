@@ -94,6 +94,31 @@ type SimpleTable interface {
 	Rows() [][]string
 }
 
+// TableData is a SimpleTable that is just data. Replayed tables
+// are TableData.
+type TableData struct {
+	Columns []string   `json:"header"`
+	Cells   [][]string `json:"rows"`
+}
+
+var _ SimpleTable = TableData{}
+
+func (t TableData) Header() []string { return t.Columns }
+func (t TableData) Rows() [][]string { return t.Cells }
+
+// NewTableData copies a SimpleTable
+func NewTableData(t SimpleTable) TableData {
+	rows := t.Rows()
+	td := TableData{
+		Columns: list.Copy(t.Header()),
+		Cells:   make([][]string, len(rows)),
+	}
+	for i, row := range rows {
+		td.Cells[i] = list.Copy(row)
+	}
+	return td
+}
+
 var _ json.Marshaler = ModelArg{}
 var _ json.Unmarshaler = &ModelArg{}
 
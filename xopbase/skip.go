@@ -24,6 +24,7 @@ func (_ skipLine) Msg(string)                  {}
 func (_ skipLine) Link(string, xoptrace.Trace) {}
 func (_ skipLine) Model(string, ModelArg)      {}
 func (_ skipLine) Template(string)             {}
+func (_ skipLine) Table(string, SimpleTable)   {}
 
 func (_ skipLine) Any(xopat.K, ModelArg)           {}
 func (_ skipLine) Bool(xopat.K, bool)              {}
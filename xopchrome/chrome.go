@@ -0,0 +1,484 @@
+package xopchrome
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/xoplog/xop-go/xopat"
+	"github.com/xoplog/xop-go/xopbase"
+	"github.com/xoplog/xop-go/xopbase/xopbaseutil"
+	"github.com/xoplog/xop-go/xopbytes"
+	"github.com/xoplog/xop-go/xopnum"
+	"github.com/xoplog/xop-go/xopproto"
+	"github.com/xoplog/xop-go/xoptrace"
+	"github.com/xoplog/xop-go/xoputil"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// New creates a Logger that writes Chrome trace events to a
+// BytesWriter in the JSON Array Format.  The opening "[" is written
+// before the first event and every event is followed by ",\n".  The
+// closing "]" is never written: chrome://tracing and Perfetto
+// accept traces without it.
+//
+// Use Convert to produce a complete trace document instead.
+func New(w xopbytes.BytesWriter) *Logger {
+	return &Logger{
+		id:     uuid.New(),
+		writer: w,
+	}
+}
+
+func (logger *Logger) ID() string           { return logger.id.String() }
+func (logger *Logger) Buffered() bool       { return true }
+func (logger *Logger) ReferencesKept() bool { return false }
+
+// Request starts a new process in the trace. Each request is
+// its own process.
+func (logger *Logger) Request(_ context.Context, ts time.Time, bundle xoptrace.Bundle, name string, _ xopbase.SourceInfo) xopbase.Request {
+	r := &request{
+		span: span{
+			logger:    logger,
+			bundle:    bundle,
+			name:      name,
+			startTime: ts,
+			isRequest: true,
+		},
+		pid:       int(atomic.AddInt32(&logger.pid, 1)),
+		errorFunc: func(error) {},
+		tracks:    make(map[string]int),
+	}
+	r.request = r
+	r.spans = []*span{&r.span}
+	r.pending = append(r.pending, traceEvent{
+		Name:  "process_name",
+		Phase: "M",
+		PID:   r.pid,
+		Args:  map[string]interface{}{"name": name},
+	})
+	r.span.tid = r.track(&r.span)
+	if logger.writer != nil {
+		r.bytes = logger.writer.Request(r)
+	} else {
+		logger.mu.Lock()
+		defer logger.mu.Unlock()
+		logger.requests = append(logger.requests, r)
+	}
+	return r
+}
+
+// track returns the thread id for a span. Steps continue the work
+// of their parent so they share its track. Forks run concurrently
+// so each gets a new track. The track is found by removing the
+// trailing Step (numeric) parts of the span sequence code.
+//
+// The request lock must be held, except when creating the request.
+func (r *request) track(s *span) int {
+	key := s.sequenceCode
+	for {
+		i := strings.LastIndexByte(key, '.')
+		if i == -1 || i == len(key)-1 || key[i+1] < '0' || key[i+1] > '9' {
+			break
+		}
+		key = key[:i]
+	}
+	if tid, ok := r.tracks[key]; ok {
+		return tid
+	}
+	tid := len(r.tracks) + 1
+	r.tracks[key] = tid
+	name := r.name
+	if key != "" {
+		name = key + " " + s.name
+	}
+	r.pending = append(r.pending,
+		traceEvent{
+			Name:  "thread_name",
+			Phase: "M",
+			PID:   r.pid,
+			TID:   tid,
+			Args:  map[string]interface{}{"name": name},
+		},
+		traceEvent{
+			Name:  "thread_sort_index",
+			Phase: "M",
+			PID:   r.pid,
+			TID:   tid,
+			Args:  map[string]interface{}{"sort_index": tid},
+		})
+	return tid
+}
+
+// Flush writes the events since the previous Flush. Spans are
+// written once they are finished.
+//
+// Flush is skipped for boring requests that have no lines at the
+// Error or Alert level.
+func (r *request) Flush() {
+	if atomic.LoadInt32(&r.boring) == 1 && r.GetErrorCount() == 0 && r.GetAlertCount() == 0 {
+		return
+	}
+	var events []traceEvent
+	func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		events = r.pending
+		r.pending = nil
+		for _, s := range r.spans {
+			if atomic.SwapInt32(&s.dirty, 0) == 1 {
+				events = append(events, s.complete())
+			}
+		}
+	}()
+	if len(events) == 0 {
+		return
+	}
+	encoded := make([]json.RawMessage, 0, len(events))
+	for _, event := range events {
+		enc, err := json.Marshal(event)
+		if err != nil {
+			r.errorFunc(errors.Wrapf(err, "encode trace event %s", event.Name))
+			continue
+		}
+		encoded = append(encoded, enc)
+	}
+	err := r.logger.write(r, encoded)
+	if err != nil {
+		r.errorFunc(err)
+	}
+}
+
+func (logger *Logger) write(r *request, events []json.RawMessage) error {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	if logger.writer == nil {
+		logger.events = append(logger.events, events...)
+		return nil
+	}
+	var b bytes.Buffer
+	if !logger.started {
+		b.WriteString("[\n")
+		logger.started = true
+	}
+	for _, enc := range events {
+		b.Write(enc)
+		b.WriteString(",\n")
+	}
+	err := r.bytes.Span(r, chunk(b.Bytes()))
+	if err != nil {
+		return err
+	}
+	return r.bytes.Flush()
+}
+
+func (r *request) Final() {
+	if r.bytes != nil {
+		r.bytes.ReclaimMemory()
+	}
+}
+
+// Boring is honored for requests. Events are kept until Flush, so
+// boring requests are simply not flushed.
+func (r *request) Boring(b bool) bool {
+	if b {
+		atomic.StoreInt32(&r.boring, 1)
+	} else {
+		atomic.StoreInt32(&r.boring, 0)
+	}
+	return true
+}
+
+func (r *request) SetErrorReporter(reporter func(error)) { r.errorFunc = reporter }
+func (r *request) GetErrorCount() int32                  { return atomic.LoadInt32(&r.errorCount) }
+func (r *request) GetAlertCount() int32                  { return atomic.LoadInt32(&r.alertCount) }
+
+func (s *span) Span(_ context.Context, ts time.Time, bundle xoptrace.Bundle, name string, spanSequenceCode string) xopbase.Span {
+	n := &span{
+		logger:       s.logger,
+		request:      s.request,
+		bundle:       bundle,
+		name:         name,
+		sequenceCode: spanSequenceCode,
+		startTime:    ts,
+	}
+	s.request.mu.Lock()
+	defer s.request.mu.Unlock()
+	n.tid = s.request.track(n)
+	s.request.spans = append(s.request.spans, n)
+	return n
+}
+
+// Done marks a span to be written by the next Flush. Spans are only
+// written once they are final but activity after that (a bug in the
+// application) will cause them to be written again. Convert writes
+// each span once, at the end.
+func (s *span) Done(t time.Time, final bool) {
+	xoputil.AtomicMaxInt64(&s.endTime, t.UnixNano())
+	if final {
+		atomic.StoreInt32(&s.done, 1)
+	}
+	if atomic.LoadInt32(&s.done) == 1 && s.logger.writer != nil {
+		atomic.StoreInt32(&s.dirty, 1)
+	}
+}
+
+// Metric values are counter events. Chrome shows counters per
+// process so they are named with the span sequence code.
+func (s *span) Metric(k *xopat.MetricAttribute, v float64, t time.Time) {
+	xoputil.AtomicMaxInt64(&s.endTime, t.UnixNano())
+	name := k.Key().String()
+	if s.sequenceCode != "" {
+		name = s.sequenceCode + " " + name
+	}
+	s.request.add(traceEvent{
+		Name:  name,
+		Cat:   "metric",
+		Phase: "C",
+		TS:    micros(t),
+		PID:   s.request.pid,
+		TID:   s.tid,
+		Args:  map[string]interface{}{k.Key().String(): floatValue(v)},
+	})
+}
+
+func (s *span) Boring(bool) bool           { return false }
+func (s *span) ID() string                 { return s.logger.id.String() }
+func (s *span) GetBundle() xoptrace.Bundle { return s.bundle }
+func (s *span) GetStartTime() time.Time    { return s.startTime }
+func (s *span) GetEndTimeNano() int64      { return atomic.LoadInt64(&s.endTime) }
+func (s *span) IsRequest() bool            { return s.isRequest }
+
+// complete builds the "X" event for a span. It must be called from
+// Flush.
+func (s *span) complete() traceEvent {
+	start := s.startTime.UnixNano()
+	end := atomic.LoadInt64(&s.endTime)
+	if end < start {
+		end = start
+	}
+	dur := end/1000 - start/1000
+	cat := "span"
+	if s.isRequest {
+		cat = "request"
+	}
+	args := map[string]interface{}{
+		"trace": s.bundle.Trace.String(),
+	}
+	if s.sequenceCode != "" {
+		args["seq"] = s.sequenceCode
+	}
+	s.SpanMetadata.Map.Range(func(k string, tracker *xopbaseutil.MetadataTracker) bool {
+		tracker.Mu.Lock()
+		defer tracker.Mu.Unlock()
+		args[k] = metadataValue(tracker.Value)
+		return true
+	})
+	return traceEvent{
+		Name:  s.name,
+		Cat:   cat,
+		Phase: "X",
+		TS:    start / 1000,
+		Dur:   &dur,
+		PID:   s.request.pid,
+		TID:   s.tid,
+		Args:  args,
+	}
+}
+
+func metadataValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case []interface{}:
+		values := make([]interface{}, len(v))
+		for i, e := range v {
+			values[i] = metadataValue(e)
+		}
+		return values
+	case float64:
+		return floatValue(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case xoptrace.Trace:
+		return v.String()
+	case xopat.Enum:
+		return v.String()
+	case xopbase.ModelArg:
+		return modelValue(v)
+	default:
+		return v
+	}
+}
+
+// floatValue returns the float as-is unless it cannot be
+// represented in JSON
+func floatValue(v float64) interface{} {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	return v
+}
+
+// modelValue embeds JSON models as JSON and other models as
+// strings
+func modelValue(v xopbase.ModelArg) interface{} {
+	v.Encode()
+	if v.Encoding == xopproto.Encoding_JSON && json.Valid(v.Encoded) {
+		return json.RawMessage(v.Encoded)
+	}
+	return string(v.Encoded)
+}
+
+func micros(t time.Time) int64 { return t.UnixNano() / 1000 }
+
+func (r *request) add(event traceEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pending = append(r.pending, event)
+}
+
+func (s *span) builder() *builder {
+	return &builder{
+		span: s,
+		args: make(map[string]interface{}),
+	}
+}
+
+func (s *span) NoPrefill() xopbase.Prefilled {
+	return &prefilled{
+		builder: s.builder(),
+	}
+}
+
+func (s *span) StartPrefill() xopbase.Prefilling {
+	return &prefilling{
+		builder: s.builder(),
+	}
+}
+
+func (p *prefilling) PrefillComplete(m string) xopbase.Prefilled {
+	return &prefilled{
+		builder:    p.builder,
+		prefillMsg: m,
+	}
+}
+
+func (p *prefilled) Line(level xopnum.Level, t time.Time, frames []runtime.Frame) xopbase.Line {
+	xoputil.AtomicMaxInt64(&p.span.endTime, t.UnixNano())
+	if level >= xopnum.ErrorLevel {
+		if level >= xopnum.AlertLevel {
+			_ = atomic.AddInt32(&p.span.request.alertCount, 1)
+		} else {
+			_ = atomic.AddInt32(&p.span.request.errorCount, 1)
+		}
+	}
+	l := &line{
+		builder: &builder{
+			span: p.span,
+			args: make(map[string]interface{}, len(p.args)+2),
+		},
+		prefillMsg: p.prefillMsg,
+		level:      level,
+		timestamp:  t,
+	}
+	for k, v := range p.args {
+		l.args[k] = v
+	}
+	if len(frames) > 0 {
+		l.stack = make([]string, len(frames))
+		for i, frame := range frames {
+			l.stack[i] = frame.File + ":" + strconv.Itoa(frame.Line)
+		}
+	}
+	return l
+}
+
+func (l *line) Msg(m string) {
+	l.send(l.prefillMsg + m)
+}
+
+var templateRE = regexp.MustCompile(`\{.+?\}`)
+
+// Template lines are named with the expanded template. The
+// template itself is in the "template" arg.
+func (l *line) Template(m string) {
+	tmpl := l.prefillMsg + m
+	msg := templateRE.ReplaceAllStringFunc(tmpl, func(k string) string {
+		if v, ok := l.args[k[1:len(k)-1]]; ok {
+			if raw, ok := v.(json.RawMessage); ok {
+				return string(raw)
+			}
+			return fmt.Sprint(v)
+		}
+		return "''"
+	})
+	l.args["template"] = tmpl
+	l.send(msg)
+}
+
+func (l *line) Model(m string, v xopbase.ModelArg) {
+	l.args["model"] = modelValue(v)
+	l.send(l.prefillMsg + m)
+}
+
+func (l *line) Link(m string, v xoptrace.Trace) {
+	l.args["link"] = v.String()
+	l.send(l.prefillMsg + m)
+}
+
+func (l *line) Table(m string, v xopbase.SimpleTable) {
+	l.args["table"] = map[string]interface{}{
+		"header": v.Header(),
+		"rows":   v.Rows(),
+	}
+	l.send(l.prefillMsg + m)
+}
+
+// send adds a thread-scoped instant event for the line
+func (l *line) send(msg string) {
+	l.args["level"] = l.level.String()
+	if len(l.stack) != 0 {
+		l.args["stack"] = l.stack
+	}
+	l.span.request.add(traceEvent{
+		Name:  msg,
+		Cat:   l.level.String(),
+		Phase: "i",
+		TS:    micros(l.timestamp),
+		PID:   l.span.request.pid,
+		TID:   l.span.tid,
+		Scope: "t",
+		Args:  l.args,
+	})
+}
+
+func (b *builder) Enum(k *xopat.EnumAttribute, v xopat.Enum) { b.args[k.Key().String()] = v.String() }
+func (b *builder) Any(k xopat.K, v xopbase.ModelArg)         { b.args[k.String()] = modelValue(v) }
+func (b *builder) Bool(k xopat.K, v bool)                    { b.args[k.String()] = v }
+func (b *builder) Time(k xopat.K, v time.Time)               { b.args[k.String()] = v.Format(time.RFC3339Nano) }
+func (b *builder) Duration(k xopat.K, v time.Duration)       { b.args[k.String()] = v.String() }
+
+func (b *builder) Int64(k xopat.K, v int64, _ xopbase.DataType) {
+	b.args[k.String()] = v
+}
+
+func (b *builder) Uint64(k xopat.K, v uint64, _ xopbase.DataType) {
+	b.args[k.String()] = v
+}
+
+func (b *builder) Float64(k xopat.K, v float64, _ xopbase.DataType) {
+	b.args[k.String()] = floatValue(v)
+}
+
+func (b *builder) String(k xopat.K, v string, _ xopbase.DataType) {
+	b.args[k.String()] = v
+}
@@ -0,0 +1,153 @@
+package xopchrome_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/xoplog/xop-go"
+	"github.com/xoplog/xop-go/xopbase"
+	"github.com/xoplog/xop-go/xopbytes"
+	"github.com/xoplog/xop-go/xopchrome"
+	"github.com/xoplog/xop-go/xoptest"
+	"github.com/xoplog/xop-go/xoptest/xoptestutil"
+	"github.com/xoplog/xop-go/xoputil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type traceEvent struct {
+	Name  string                 `json:"name"`
+	Cat   string                 `json:"cat"`
+	Phase string                 `json:"ph"`
+	TS    int64                  `json:"ts"`
+	Dur   *int64                 `json:"dur"`
+	PID   int                    `json:"pid"`
+	TID   int                    `json:"tid"`
+	Scope string                 `json:"s"`
+	Args  map[string]interface{} `json:"args"`
+}
+
+func find(events []traceEvent, phase string, name string) *traceEvent {
+	for i, e := range events {
+		if e.Phase == phase && e.Name == name {
+			return &events[i]
+		}
+	}
+	return nil
+}
+
+func TestChrome(t *testing.T) {
+	var buffer xoputil.Buffer
+	seed := xop.NewSeed(
+		xop.WithBase(xopchrome.New(xopbytes.WriteToIOWriter(&buffer))),
+		xop.WithSettings(func(settings *xop.LogSettings) {
+			settings.SynchronousFlush(true)
+		}),
+	)
+	log := seed.Request(t.Name())
+	log.Info().String(xop.Key("color"), "blue").Int(xop.Key("count"), 3).Msg("a line")
+	step := log.Sub().Step("a step")
+	step.Warn().String(xop.Key("who"), "world").Template("hello {who}")
+	step.Done()
+	fork := log.Sub().Fork("a fork")
+	forkStep := fork.Sub().Step("a fork step")
+	forkStep.Error().Msg("oops")
+	forkStep.Done()
+	fork.Done()
+	log.Done()
+
+	out := buffer.String()
+	require.True(t, strings.HasPrefix(out, "[\n"), "starts array")
+	require.True(t, strings.HasSuffix(out, ",\n"), "unterminated array")
+	var events []traceEvent
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimSuffix(out, ",\n")+"]"), &events))
+
+	request := find(events, "X", t.Name())
+	require.NotNil(t, request, "request")
+	assert.Equal(t, "request", request.Cat)
+	require.NotNil(t, request.Dur)
+	stepEvent := find(events, "X", "a step")
+	require.NotNil(t, stepEvent, "step")
+	forkEvent := find(events, "X", "a fork")
+	require.NotNil(t, forkEvent, "fork")
+	forkStepEvent := find(events, "X", "a fork step")
+	require.NotNil(t, forkStepEvent, "fork step")
+
+	assert.Equal(t, request.PID, forkEvent.PID)
+	assert.Equal(t, request.TID, stepEvent.TID, "steps share their parent's track")
+	assert.NotEqual(t, request.TID, forkEvent.TID, "forks get their own track")
+	assert.Equal(t, forkEvent.TID, forkStepEvent.TID)
+	assert.Equal(t, ".A", forkEvent.Args["seq"])
+
+	thread := find(events, "M", "thread_name")
+	require.NotNil(t, thread)
+	assert.Equal(t, t.Name(), thread.Args["name"])
+	process := find(events, "M", "process_name")
+	require.NotNil(t, process)
+	assert.Equal(t, t.Name(), process.Args["name"])
+
+	line := find(events, "i", "a line")
+	require.NotNil(t, line, "line")
+	assert.Equal(t, "t", line.Scope)
+	assert.Equal(t, request.TID, line.TID)
+	assert.Equal(t, "blue", line.Args["color"])
+	assert.Equal(t, float64(3), line.Args["count"])
+	assert.Equal(t, "info", line.Args["level"])
+
+	tmpl := find(events, "i", "hello world")
+	require.NotNil(t, tmpl, "template")
+	assert.Equal(t, "hello {who}", tmpl.Args["template"])
+	assert.Equal(t, "warn", tmpl.Cat)
+
+	oops := find(events, "i", "oops")
+	require.NotNil(t, oops, "error line")
+	assert.Equal(t, forkEvent.TID, oops.TID)
+}
+
+func TestConvert(t *testing.T) {
+	for _, mc := range xoptestutil.MessageCases {
+		mc := mc
+		t.Run(mc.Name, func(t *testing.T) {
+			tLog := xoptest.New(t)
+			seed := xop.NewSeed(
+				xop.WithBase(tLog),
+				xop.WithSettings(func(settings *xop.LogSettings) {
+					settings.SynchronousFlush(true)
+				}),
+			)
+			if len(mc.SeedMods) != 0 {
+				seed = seed.Copy(mc.SeedMods...)
+			}
+			log := seed.Request(t.Name())
+			mc.Do(t, log, tLog)
+
+			var buffer bytes.Buffer
+			err := xopchrome.Convert(context.Background(), &buffer, func(ctx context.Context, logger xopbase.Logger) error {
+				return tLog.Recorder().Replay(ctx, logger)
+			})
+			require.NoError(t, err)
+			t.Log("\n", buffer.String())
+
+			var doc struct {
+				TraceEvents []traceEvent `json:"traceEvents"`
+			}
+			require.NoError(t, json.Unmarshal(buffer.Bytes(), &doc))
+			var complete, instant int
+			for _, e := range doc.TraceEvents {
+				switch e.Phase {
+				case "X":
+					complete++
+				case "i":
+					instant++
+				}
+			}
+			recorder := tLog.Recorder()
+			assert.Equal(t, len(recorder.Requests)+len(recorder.Spans), complete, "spans")
+			assert.Equal(t, len(recorder.Lines), instant, "lines")
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package xopchrome
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync/atomic"
+
+	"github.com/xoplog/xop-go/xopbase"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// Convert writes a complete Chrome trace document to w. The
+// replay function should replay logs into the base logger it
+// is given, for example:
+//
+//	err := xopchrome.Convert(ctx, w, func(ctx context.Context, logger xopbase.Logger) error {
+//		return xopconsole.Replay(ctx, input, logger)
+//	})
+//
+// Spans that were never finished are ended at their last activity.
+func Convert(ctx context.Context, w io.Writer, replay func(context.Context, xopbase.Logger) error) error {
+	logger := &Logger{
+		id: uuid.New(),
+	}
+	err := replay(ctx, logger)
+	if err != nil {
+		return errors.Wrap(err, "replay")
+	}
+	for _, r := range logger.requests {
+		for _, s := range r.spans {
+			atomic.StoreInt32(&s.dirty, 1)
+		}
+		r.Flush()
+	}
+	var b bytes.Buffer
+	b.WriteString(`{"traceEvents":[`)
+	for i, enc := range logger.events {
+		if i != 0 {
+			b.WriteString(",\n")
+		}
+		b.Write(enc)
+	}
+	b.WriteString(`],"displayTimeUnit":"ms"}`)
+	b.WriteString("\n")
+	_, err = w.Write(b.Bytes())
+	return errors.Wrap(err, "write chrome trace")
+}
@@ -0,0 +1,29 @@
+/*
+Package xopchrome is a xop base logger (xopbase.Logger) that produces
+the Chrome Trace Event format so that requests can be viewed as
+timelines in Perfetto (https://ui.perfetto.dev) or chrome://tracing.
+
+Use New to write events as they happen or Convert to turn logs in
+any format that has a Replay function into a single trace document.
+
+# Mapping
+
+Each request is a process. Within a request, each Fork starts a new
+track (thread) and each Step stays on the track of its parent. Tracks
+are derived from the span sequence code so ".A.1.2" is on the ".A"
+track.
+
+Spans are complete ("X") events. Their args are the span metadata
+plus "trace" and "seq". Spans are written once they are finished.
+
+Lines are thread-scoped instant ("i") events named by their message
+with their attributes as args. The level is both the category and
+the "level" arg. Templates are expanded and the template is kept in
+the "template" arg. Models, links, and tables are in the "model",
+"link", and "table" args.
+
+Metrics are counter ("C") events.
+
+This is a lossy format: there is no Replay.
+*/
+package xopchrome
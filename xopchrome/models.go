@@ -0,0 +1,105 @@
+package xopchrome
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/xoplog/xop-go/xopbase"
+	"github.com/xoplog/xop-go/xopbase/xopbaseutil"
+	"github.com/xoplog/xop-go/xopbytes"
+	"github.com/xoplog/xop-go/xopnum"
+	"github.com/xoplog/xop-go/xoptrace"
+
+	"github.com/google/uuid"
+)
+
+var _ xopbase.Logger = &Logger{}
+var _ xopbase.Request = &request{}
+var _ xopbase.Span = &span{}
+var _ xopbase.Line = &line{}
+var _ xopbase.Prefilling = &prefilling{}
+var _ xopbase.Prefilled = &prefilled{}
+var _ xopbytes.Request = &request{}
+var _ xopbytes.Buffer = chunk{}
+
+type Logger struct {
+	id       uuid.UUID
+	writer   xopbytes.BytesWriter // nil when used by Convert
+	pid      int32
+	mu       sync.Mutex
+	started  bool
+	events   []json.RawMessage // only used by Convert
+	requests []*request        // only used by Convert
+}
+
+type request struct {
+	span
+	bytes      xopbytes.BytesRequest
+	pid        int
+	errorFunc  func(error)
+	errorCount int32
+	alertCount int32
+	boring     int32 // 1 = boring
+	mu         sync.Mutex
+	spans      []*span
+	tracks     map[string]int
+	pending    []traceEvent // events since the last Flush
+}
+
+type span struct {
+	xopbaseutil.SpanMetadata
+	logger       *Logger
+	request      *request
+	bundle       xoptrace.Bundle
+	name         string
+	sequenceCode string
+	tid          int
+	startTime    time.Time
+	isRequest    bool
+	endTime      int64
+	done         int32 // 1 once Done has been called with final
+	dirty        int32 // 1 if the span should be sent by the next Flush
+}
+
+type builder struct {
+	span *span
+	args map[string]interface{}
+}
+
+type prefilling struct {
+	*builder
+}
+
+type prefilled struct {
+	*builder
+	prefillMsg string
+}
+
+type line struct {
+	*builder
+	prefillMsg string
+	level      xopnum.Level
+	timestamp  time.Time
+	stack      []string
+}
+
+// traceEvent is an event in the Chrome Trace Event format. Times
+// are in microseconds.
+type traceEvent struct {
+	Name  string                 `json:"name"`
+	Cat   string                 `json:"cat,omitempty"`
+	Phase string                 `json:"ph"`
+	TS    int64                  `json:"ts"`
+	Dur   *int64                 `json:"dur,omitempty"`
+	PID   int                    `json:"pid"`
+	TID   int                    `json:"tid"`
+	Scope string                 `json:"s,omitempty"`
+	Args  map[string]interface{} `json:"args,omitempty"`
+}
+
+// chunk is encoded events, each followed by ",\n"
+type chunk []byte
+
+func (c chunk) AsBytes() []byte { return c }
+func (c chunk) ReclaimMemory()  {}
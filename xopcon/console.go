@@ -21,6 +21,7 @@ import (
 	"github.com/xoplog/xop-go/xopat"
 	"github.com/xoplog/xop-go/xopbase"
 	"github.com/xoplog/xop-go/xopbase/xopbaseutil"
+	"github.com/xoplog/xop-go/xopconst"
 	"github.com/xoplog/xop-go/xopnum"
 	"github.com/xoplog/xop-go/xoptrace"
 	"github.com/xoplog/xop-go/xoputil"
@@ -117,6 +118,7 @@ type Line struct {
 	Tmpl      string // un-evaluated template
 	AsLink    *xoptrace.Trace
 	AsModel   *xopbase.ModelArg
+	AsTable   *xopbase.TableData
 	Stack     []runtime.Frame
 }
 
@@ -175,12 +177,25 @@ func (span *Span) Flush() {}
 // Final is a required method for xopbase.Request
 func (span *Span) Final() {}
 
-// Boring is a required method for xopbase.Span
-func (span *Span) Boring(bool) {}
+// Boring is a required method for xopbase.Span. Boring requests
+// are tagged with xopconst.Boring.
+func (span *Span) Boring(b bool) bool {
+	if !span.IsRequest {
+		return false
+	}
+	span.MetadataBool(xopconst.Boring, b)
+	return true
+}
 
 // ID is a required method for xopbase.Span
 func (span *Span) ID() string { return span.logger.id }
 
+// Metric is a required method for xopbase.Span
+func (span *Span) Metric(k *xopat.MetricAttribute, v float64, t time.Time) {
+	xoputil.AtomicMaxInt64(&span.provisionalEndTime, t.UnixNano())
+	span.logger.output(fmt.Sprintf("%s %s: %s=%v", span.Short, strings.ToLower(k.SubType().String()), k.Key(), v))
+}
+
 // ID is a required method for xopbase.Request
 func (span *Span) SetErrorReporter(func(error)) {}
 
@@ -291,6 +306,21 @@ func (line *Line) Model(m string, v xopbase.ModelArg) {
 	line.send(text)
 }
 
+// Table is a required method for xopbase.Line
+func (line *Line) Table(m string, v xopbase.SimpleTable) {
+	td := xopbase.NewTableData(v)
+	line.AsTable = &td
+	line.Message += m
+	text := line.Span.Short + " TABLE:" + line.Message
+	if len(line.kvText) > 0 {
+		text += " " + strings.Join(line.kvText, " ")
+		line.kvText = nil
+	}
+	text += "\n" + strings.TrimSuffix(xoputil.AlignedTable(td.Columns, td.Cells), "\n")
+	line.Text = text
+	line.send(text)
+}
+
 // Msg is a required method for xopbase.Line
 func (line *Line) Msg(m string) {
 	line.Message += m
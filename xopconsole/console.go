@@ -13,10 +13,12 @@ import (
 	"os"
 	"runtime"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/xoplog/xop-go/xopat"
 	"github.com/xoplog/xop-go/xopbase"
+	"github.com/xoplog/xop-go/xopconst"
 	"github.com/xoplog/xop-go/xopnum"
 	"github.com/xoplog/xop-go/xoptrace"
 	"github.com/xoplog/xop-go/xoputil"
@@ -84,6 +86,7 @@ type Span struct {
 	SourceInfo         *xopbase.SourceInfo
 	VersionNumber      int32
 	Request            *Span
+	metricsDefined     sync.Map // only used on requests
 }
 
 type Prefilling struct {
@@ -215,12 +218,43 @@ func (span *Span) Flush() {}
 // Final is a required method for xopbase.Request
 func (span *Span) Final() {}
 
-// Boring is a required method for xopbase.Span
-func (span *Span) Boring(bool) {}
+// Boring is a required method for xopbase.Span. Boring requests
+// are tagged with xopconst.Boring.
+func (span *Span) Boring(b bool) bool {
+	if !span.IsRequest {
+		return false
+	}
+	span.MetadataBool(xopconst.Boring, b)
+	return true
+}
 
 // ID is a required method for xopbase.Span
 func (span *Span) ID() string { return span.logger.id }
 
+// Metric is a required method for xopbase.Span
+func (span *Span) Metric(k *xopat.MetricAttribute, v float64, ts time.Time) {
+	xoputil.AtomicMaxInt64(&span.provisionalEndTime, ts.UnixNano())
+	if _, loaded := span.AttributeBuilder.request.metricsDefined.LoadOrStore(k.Key(), struct{}{}); !loaded {
+		span.AttributeBuilder.defineKey(k)
+	}
+	var buf [200]byte
+	b := xoputil.JBuilder{
+		B: buf[:0],
+	}
+	b.AppendBytes([]byte("xop Metric "))
+	b.B = DefaultTimeFormatter(b.B, ts)
+	b.AppendByte(' ')
+	b.AppendBytes(span.Bundle.Trace.SpanID().HexBytes())
+	b.AppendByte(' ')
+	b.AppendBytes(k.ConsoleKey())
+	b.AddFloat64(v)
+	b.AppendByte('\n')
+	_, err := span.logger.out.Write(b.B)
+	if err != nil {
+		span.logger.errorReporter(err)
+	}
+}
+
 // ID is a required method for xopbase.Request
 func (span *Span) SetErrorReporter(func(error)) {}
 
@@ -314,7 +348,7 @@ func (p *Prefilled) Line(level xopnum.Level, t time.Time, frames []runtime.Frame
 
 // Link is a required method for xopbase.Line
 func (line *Line) Link(m string, v xoptrace.Trace) {
-	line.send([]byte("LINK:"), m, []byte(v.String()))
+	line.send([]byte("LINK:"), m, []byte(v.String()), "")
 }
 
 // Model is a required method for xopbase.Line
@@ -322,22 +356,47 @@ func (line *Line) Model(m string, v xopbase.ModelArg) {
 	var b Builder
 	b.Init()
 	b.AnyCommon(v)
-	line.send([]byte("MODEL:"), m, b.B)
+	line.send([]byte("MODEL:"), m, b.B, "")
 }
 
 // Msg is a required method for xopbase.Line
 func (line *Line) Msg(m string) {
-	line.send(nil, line.PrefillMsg+m, nil)
+	line.send(nil, line.PrefillMsg+m, nil, "")
 }
 
 // Template is a required method for xopbase.Line
 func (line *Line) Template(m string) {
-	line.send([]byte("TEMPLATE:"), line.PrefillMsg+m, nil)
+	line.send([]byte("TEMPLATE:"), line.PrefillMsg+m, nil, "")
+}
+
+// Table is a required method for xopbase.Line. The table follows
+// the line as an aligned table. Cells are quoted if needed.
+func (line *Line) Table(m string, v xopbase.SimpleTable) {
+	rows := v.Rows()
+	cells := make([][]string, len(rows))
+	for i, row := range rows {
+		cells[i] = consoleCells(row)
+	}
+	line.send([]byte("TABLE:"), line.PrefillMsg+m,
+		[]byte("("+strconv.Itoa(len(rows))+")"),
+		xoputil.AlignedTable(consoleCells(v.Header()), cells))
+}
+
+func consoleCells(row []string) []string {
+	cells := make([]string, len(row))
+	for i, cell := range row {
+		var b xoputil.JBuilder
+		b.AddConsoleString(cell)
+		cells[i] = string(b.B)
+	}
+	return cells
 }
 
-func (line Line) send(prefix []byte, text string, postfix []byte) {
+// send writes the line. If there is a trailer, it is written on the lines
+// that follow.
+func (line Line) send(prefix []byte, text string, postfix []byte, trailer string) {
 	b := xoputil.JBuilder{
-		B: make([]byte, 0, len(line.B)+len(text)+len(prefix)+len(postfix)+50),
+		B: make([]byte, 0, len(line.B)+len(text)+len(prefix)+len(postfix)+len(trailer)+50),
 	}
 	b.AppendBytes([]byte("xop "))
 	b.AppendString(line.Level.String())
@@ -367,6 +426,7 @@ func (line Line) send(prefix []byte, text string, postfix []byte) {
 		}
 	}
 	b.AppendByte('\n')
+	b.AppendString(trailer)
 	_, err := line.Span.logger.out.Write(b.B)
 	if err != nil {
 		line.Span.logger.errorReporter(err)
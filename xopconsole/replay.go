@@ -32,6 +32,17 @@ type replayData struct {
 	requests             map[xoptrace.HexBytes8]*replayRequest
 	dest                 xopbase.Logger
 	attributeDefinitions *replayutil.GlobalAttributeDefinitions
+	table                *replayTable
+}
+
+// replayTable collects the lines that follow a TABLE: line:
+// the header, a separator, and then the rows.
+type replayTable struct {
+	line    xopbase.Line
+	message string
+	want    int // lines, including the header and separator
+	read    int
+	data    xopbase.TableData
 }
 
 type replaySpan struct {
@@ -75,6 +86,7 @@ const (
 	lineTypeTemplate
 	lineTypeModel
 	lineTypeLink
+	lineTypeTable
 )
 
 func (x replayLine) replayLine(ctx context.Context, t string) error {
@@ -95,6 +107,7 @@ func (x replayLine) replayLine(ctx context.Context, t string) error {
 	var lineLink xoptrace.Trace
 	var lineModel xopbase.ModelArg
 	var lineType lineType
+	var rowCount int
 	message, t := oneString(t)
 	if t != "" {
 		switch t[0] {
@@ -119,6 +132,21 @@ func (x replayLine) replayLine(ctx context.Context, t string) error {
 				default:
 					return errors.Errorf("invalid data after model, sep is '%s'", string(sep))
 				}
+			case "TABLE":
+				lineType = lineTypeTable
+				message, t = oneStringAndSpace(t[1:])
+				if t == "" || t[0] != '(' /*)*/ {
+					return errors.Errorf("invalid table line, missing row count")
+				}
+				var countString string
+				countString, _, t = oneWord(t[1:] /*(*/, ")")
+				rowCount, err = strconv.Atoi(countString)
+				if err != nil {
+					return errors.Wrap(err, "invalid table row count")
+				}
+				if t != "" && t[0] == ' ' {
+					t = t[1:]
+				}
 			case "LINK":
 				lineType = lineTypeLink
 				message, t = oneStringAndSpace(t[1:])
@@ -341,12 +369,73 @@ func (x replayLine) replayLine(ctx context.Context, t string) error {
 		line.Model(message, lineModel)
 	case lineTypeLink:
 		line.Link(message, lineLink)
+	case lineTypeTable:
+		*x.table = replayTable{
+			line:    line,
+			message: message,
+			want:    rowCount + 2,
+		}
 	default:
 		return errors.Errorf("invalid line type %d", lineType)
 	}
 	return nil
 }
 
+// add consumes one line of a table. When the last line has been
+// read, the table is sent.
+func (x *replayTable) add(t string) error {
+	switch x.read {
+	case 0:
+		var err error
+		x.data.Columns, err = tableCells(t)
+		if err != nil {
+			return errors.Wrap(err, "table header")
+		}
+		x.data.Cells = make([][]string, 0, x.want-2)
+	case 1:
+		if strings.Trim(t, "-+") != "" {
+			return errors.Errorf("invalid table separator")
+		}
+	default:
+		row, err := tableCells(t)
+		if err != nil {
+			return errors.Wrap(err, "table row")
+		}
+		x.data.Cells = append(x.data.Cells, row)
+	}
+	x.read++
+	if x.read == x.want {
+		x.line.Table(x.message, x.data)
+		*x = replayTable{}
+	}
+	return nil
+}
+
+// tableCells parses a row of cells separated by "|". Cells are
+// quoted if needed. Missing cells are only at the end of rows.
+func tableCells(t string) ([]string, error) {
+	cells := make([]string, 0)
+	for {
+		t = strings.TrimLeft(t, " ")
+		if t == "" || t[0] == '|' {
+			return cells, nil
+		}
+		cell, rest := oneString(t)
+		if rest == t {
+			return nil, errors.Errorf("invalid cell (%s)", t)
+		}
+		cells = append(cells, cell)
+		t = strings.TrimLeft(rest, " ")
+		if t == "" {
+			return cells, nil
+		}
+		if t[0] != '|' {
+			return nil, errors.Errorf("invalid cell separator (%s)", t)
+		}
+		t = t[1:]
+	}
+}
+
 var shortCodeRE = regexp.MustCompile(`^T\d+\.\d+(\.\S+)?`)
 
 // Example:
@@ -433,6 +522,42 @@ func (x replayData) replaySpan(ctx context.Context, t string) error {
 	return nil
 }
 
+// Example:
+//
+//	xop Metric 2023-09-19T21:08:15.545908-07:00 ed738448be5cde53 requests=3
+func (x replayData) replayMetric(ctx context.Context, t string) error {
+	ts, t, err := oneTime(t)
+	if err != nil {
+		return err
+	}
+	spanIDString, _, t := oneWord(t, " ")
+	if spanIDString == "" {
+		return errors.Errorf("missing span id in metric")
+	}
+	spanData, ok := x.spans[xoptrace.NewHexBytes8FromString(spanIDString)]
+	if !ok {
+		return errors.Errorf("metric for span %s that doesn't exist", spanIDString)
+	}
+	key, sep, t := oneWordMaybeQuoted(t, "=")
+	if sep != '=' {
+		return errors.Errorf("invalid metric, missing '='")
+	}
+	aDef := spanData.request.requestAttributes.Lookup(key)
+	if aDef == nil {
+		return errors.Errorf("missing definition for '%s'", key)
+	}
+	ma, err := spanData.request.attributeRegistry.ConstructMetricAttribute(aDef.Make, xopat.AttributeType(aDef.AttributeType))
+	if err != nil {
+		return err
+	}
+	v, err := strconv.ParseFloat(t, 64)
+	if err != nil {
+		return errors.Wrap(err, "invalid metric value")
+	}
+	spanData.span.Metric(ma, v, ts)
+	return nil
+}
+
 // xop Def 2023-09-10T09:15:27.76661-07:00 {"type":"defineKey","key":"http.route","desc":"HTTP handler route used to handle the request. If there are path parameters in the route their generic names should be used, eg \u0027/invoice/{number}\u0027 or \u0027/invoice/:number\u0027 depending on the router used","ns":"xop 0.0.0","indexed":true,"prom":10,"vtype":"String"}
 func (x replayData) replayDef(ctx context.Context, t string) error {
 	// skip timestamp
@@ -1004,10 +1129,17 @@ func Replay(ctx context.Context, inputStream io.Reader, dest xopbase.Logger) err
 		spans:                make(map[xoptrace.HexBytes8]*replaySpan),
 		requests:             make(map[xoptrace.HexBytes8]*replayRequest),
 		attributeDefinitions: replayutil.NewGlobalAttributeDefinitions(),
+		table:                &replayTable{},
 	}
 	for scanner.Scan() {
 		x.lineCount++
 		t := scanner.Text()
+		if x.table.line != nil {
+			if err := x.table.add(t); err != nil {
+				x.errors = append(x.errors, errors.Wrapf(err, "line %d: %s", x.lineCount, t))
+			}
+			continue
+		}
 		if !strings.HasPrefix(t, "xop ") {
 			continue
 		}
@@ -1022,6 +1154,8 @@ func Replay(ctx context.Context, inputStream io.Reader, dest xopbase.Logger) err
 			err = x.replaySpan(ctx, t)
 		case "Def":
 			err = x.replayDef(ctx, t)
+		case "Metric":
+			err = x.replayMetric(ctx, t)
 		case "alert":
 			err = replayLine{
 				replayData: x,
@@ -50,3 +50,19 @@ var HTTPStatusCode = xopat.Make{Key: "http.status_code", Namespace: "OTEL", Inde
 
 var TraceResponse = xopat.Make{Key: "http.response.header.traceresponse", Namespace: "OTEL", Indexed: true, Prominence: 50,
 	Description: "Response 'traceresponse' heeader received"}.StringAttribute()
+
+var HTTPResponseContentLength = xopat.Make{Key: "http.response_content_length", Namespace: "OTEL", Indexed: false, Prominence: 40,
+	Description: "The size of the response payload body in bytes. This is the number of bytes transferred" +
+		" excluding headers and is often, but not always, present as the Content-Length header"}.Int64Attribute()
+
+var RPCSystem = xopat.Make{Key: "rpc.system", Namespace: "OTEL", Indexed: true, Prominence: 20,
+	Description: "A string identifying the remoting system, eg: grpc"}.StringAttribute()
+
+var RPCService = xopat.Make{Key: "rpc.service", Namespace: "OTEL", Indexed: true, Prominence: 12,
+	Description: "The full (logical) name of the service being called, including its package name, if applicable"}.StringAttribute()
+
+var RPCMethod = xopat.Make{Key: "rpc.method", Namespace: "OTEL", Indexed: true, Prominence: 10,
+	Description: "The name of the (logical) method being called, must be equal to the $method part in the span name"}.StringAttribute()
+
+var RPCGRPCStatusCode = xopat.Make{Key: "rpc.grpc.status_code", Namespace: "OTEL", Indexed: true, Prominence: 5,
+	Description: "The numeric status code of the gRPC request"}.IntAttribute()
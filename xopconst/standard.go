@@ -14,9 +14,13 @@ var EndpointRoute = xopat.Make{Key: "http.route", Namespace: "xop", Indexed: tru
 		" eg '/invoice/{number}' or '/invoice/:number' depending on the router used"}.StringAttribute()
 
 var Boring = xopat.Make{Key: "boring", Namespace: "xop", Indexed: false, Prominence: 200,
-	Description: "spans are boring if they're an internal span (created by log.Fork() or" +
-		" log.Step()) or they're a request and log.Boring() has been called, and if" +
-		" there have has been nothing logged at the Error or Alert level"}.BoolAttribute()
+	Description: "requests are boring if log.Boring() has been called, and if" +
+		" there has been nothing logged at the Error or Alert level." +
+		" Set by base loggers that do not drop boring requests"}.BoolAttribute()
+
+var LinesDropped = xopat.Make{Key: "lines.dropped", Namespace: "xop", Indexed: false, Prominence: 150,
+	Description: "the number of lines, by level, that were dropped because the request" +
+		" exceeded its log budget.  Set automatically when the request is done"}.AnyAttribute(map[string]int64{})
 
 var SpanSequenceCode = xopat.Make{Key: "span.seq", Namespace: "xop", Indexed: false, Prominence: 500,
 	Description: "sub-spans only: an indicator of how the sub-span relates to it's parent" +
@@ -31,8 +35,21 @@ var (
 	SpanTypeHTTPServerEndpoint = SpanType.Iota("endpoint")
 	SpanTypeHTTPClientRequest  = SpanType.Iota("REST")
 	SpanTypeCronJob            = SpanType.Iota("cron_job")
+	SpanTypeGRPCServerEndpoint = SpanType.Iota("grpc_endpoint")
+	SpanTypeGRPCClientRequest  = SpanType.Iota("grpc")
 )
 
 var RemoteTrace = xopat.Make{Key: "http.remote_trace", Namespace: "xop", Indexed: true, Prominence: 40,
 	Description: "The traceID and spanID for for the remote side of a outgoing HTTP request, if known"}.
 	LinkAttribute()
+
+var HTTPResponseTime = xopat.Make{Key: "http.response_time", Namespace: "xop", Indexed: false, Prominence: 40,
+	Description: "For outgoing HTTP requests, the time from sending the request until the response" +
+		" headers were received. For incoming HTTP requests, the time from the start of handling" +
+		" until the response headers were sent"}.DurationAttribute()
+
+var RPCMessagesSent = xopat.Make{Key: "rpc.messages_sent", Namespace: "xop", Indexed: false, Prominence: 45,
+	Description: "The number of messages sent during an RPC call. For unary calls this is zero or one"}.Int64Attribute()
+
+var RPCMessagesReceived = xopat.Make{Key: "rpc.messages_received", Namespace: "xop", Indexed: false, Prominence: 45,
+	Description: "The number of messages received during an RPC call. For unary calls this is zero or one"}.Int64Attribute()
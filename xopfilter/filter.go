@@ -0,0 +1,425 @@
+// This file is generated, DO NOT EDIT.  It comes from the corresponding .zzzgo file
+
+/*
+Package xopfilter provides a xopbase.Logger that wraps another
+xopbase.Logger and passes through only some of what it receives.
+
+The minimum log level in xop.LogSettings applies to all base loggers
+attached to a Seed. Wrapping base loggers with xopfilter allows them
+to have different thresholds. For example, Info and above to the
+console, but Debug and above to a JSON file:
+
+	seed := xop.NewSeed(
+		xop.WithBase(xopfilter.New(xopcon.New(), xopfilter.WithMinLevel(xopnum.InfoLevel))),
+		xop.WithBase(xopjson.New(xopbytes.WriteToIOWriter(f))),
+	)
+
+Lines that are below the minimum level or that are in requests or spans that
+have been filtered out cost very little: if no base logger wants a line, the
+line is skipped before any data is added to it.  Filtering with WithLineFilter
+is more expensive because lines must be buffered until they are complete.
+*/
+package xopfilter
+
+import (
+	"context"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/xoplog/xop-go/xopat"
+	"github.com/xoplog/xop-go/xopbase"
+	"github.com/xoplog/xop-go/xopnum"
+	"github.com/xoplog/xop-go/xoptrace"
+
+	"github.com/google/uuid"
+)
+
+var (
+	_ xopbase.Logger     = &Logger{}
+	_ xopbase.Request    = &request{}
+	_ xopbase.Span       = &span{}
+	_ xopbase.Prefilling = &prefilling{}
+	_ xopbase.Prefilled  = &prefilled{}
+	_ xopbase.Line       = &pendingLine{}
+	_ xopbase.Request    = discard{}
+	_ xopbase.Prefilling = discard{}
+	_ xopbase.Prefilled  = discard{}
+)
+
+type Opt func(*Logger)
+
+type Logger struct {
+	logger         xopbase.Logger
+	id             string
+	minLevel       xopnum.Level
+	requestFilter  func(string, xopbase.SourceInfo) bool
+	metadataFilter func(xopat.K, interface{}) bool
+	lineFilter     func(LineInfo) bool
+}
+
+// LineInfo is what is known about a line when it is complete. It
+// is provided to the function given to WithLineFilter.
+type LineInfo struct {
+	Level   xopnum.Level
+	Message string
+	// Data is keyed by the attribute key. Values are as they would
+	// be passed to the base logger: Any values are xopbase.ModelArg.
+	Data map[string]interface{}
+}
+
+// New wraps a base logger.  Without options, everything is passed through.
+func New(logger xopbase.Logger, opts ...Opt) *Logger {
+	log := &Logger{
+		logger: logger,
+		id:     "xopfilter-" + uuid.New().String(),
+	}
+	for _, opt := range opts {
+		opt(log)
+	}
+	return log
+}
+
+// WithMinLevel discards lines below level.
+func WithMinLevel(level xopnum.Level) Opt {
+	return func(log *Logger) {
+		log.minLevel = level
+	}
+}
+
+// WithRequestFilter decides, when a request starts, if it should be
+// passed through. If f returns false, nothing about the request, its
+// spans, or its lines, is passed through.
+func WithRequestFilter(f func(descriptionOrName string, source xopbase.SourceInfo) bool) Opt {
+	return func(log *Logger) {
+		log.requestFilter = f
+	}
+}
+
+// WithSpanMetadataFilter is called for each metadata attribute set on a
+// request or span.  If f returns false, subsequent lines logged in that span
+// (and in its sub-spans) are discarded.  The span itself and its metadata
+// are still passed through.
+func WithSpanMetadataFilter(f func(key xopat.K, value interface{}) bool) Opt {
+	return func(log *Logger) {
+		log.metadataFilter = f
+	}
+}
+
+// WithLineFilter is called for each line that is not discarded by other
+// filters. If f returns false, the line is discarded.
+func WithLineFilter(f func(LineInfo) bool) Opt {
+	return func(log *Logger) {
+		log.lineFilter = f
+	}
+}
+
+type span struct {
+	logger     *Logger
+	span       xopbase.Span
+	parent     *span
+	suppressed int32
+}
+
+type request struct {
+	span
+	request xopbase.Request
+}
+
+type prefilling struct {
+	span       *span
+	prefilling xopbase.Prefilling
+}
+
+type prefilled struct {
+	span      *span
+	prefilled xopbase.Prefilled
+}
+
+type pendingLine struct {
+	prefilled xopbase.Prefilled
+	filter    func(LineInfo) bool
+	ts        time.Time
+	frames    []runtime.Frame
+	info      LineInfo
+	ops       []func(xopbase.Line)
+}
+
+// discard is used for requests that are filtered out
+type discard struct {
+	id string
+}
+
+func (log *Logger) ID() string           { return log.id }
+func (log *Logger) Buffered() bool       { return log.logger.Buffered() }
+func (log *Logger) ReferencesKept() bool { return log.logger.ReferencesKept() }
+
+func (log *Logger) Request(ctx context.Context, ts time.Time, bundle xoptrace.Bundle, description string, sourceInfo xopbase.SourceInfo) xopbase.Request {
+	if log.requestFilter != nil && !log.requestFilter(description, sourceInfo) {
+		return discard{id: log.id}
+	}
+	baseRequest := log.logger.Request(ctx, ts, bundle, description, sourceInfo)
+	return &request{
+		span: span{
+			logger: log,
+			span:   baseRequest,
+		},
+		request: baseRequest,
+	}
+}
+
+func (r *request) Flush()                         { r.request.Flush() }
+func (r *request) Final()                         { r.request.Final() }
+func (r *request) SetErrorReporter(f func(error)) { r.request.SetErrorReporter(f) }
+
+func (s *span) Span(ctx context.Context, ts time.Time, bundle xoptrace.Bundle, descriptionOrName string, spanSequenceCode string) xopbase.Span {
+	return &span{
+		logger: s.logger,
+		span:   s.span.Span(ctx, ts, bundle, descriptionOrName, spanSequenceCode),
+		parent: s,
+	}
+}
+
+func (s *span) ID() string                                              { return s.logger.id }
+func (s *span) Boring(b bool) bool                                      { return s.span.Boring(b) }
+func (s *span) Done(endTime time.Time, final bool)                      { s.span.Done(endTime, final) }
+func (s *span) Metric(k *xopat.MetricAttribute, v float64, t time.Time) { s.span.Metric(k, v, t) }
+
+func (s *span) NoPrefill() xopbase.Prefilled {
+	return &prefilled{
+		span:      s,
+		prefilled: s.span.NoPrefill(),
+	}
+}
+
+func (s *span) StartPrefill() xopbase.Prefilling {
+	return &prefilling{
+		span:       s,
+		prefilling: s.span.StartPrefill(),
+	}
+}
+
+func (s *span) isSuppressed() bool {
+	for ; s != nil; s = s.parent {
+		if atomic.LoadInt32(&s.suppressed) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *span) checkMetadata(k xopat.K, v interface{}) {
+	if s.logger.metadataFilter != nil && !s.logger.metadataFilter(k, v) {
+		atomic.StoreInt32(&s.suppressed, 1)
+	}
+}
+
+func (s *span) MetadataAny(k *xopat.AnyAttribute, v xopbase.ModelArg) {
+	s.checkMetadata(k.Key(), v)
+	s.span.MetadataAny(k, v)
+}
+
+func (s *span) MetadataBool(k *xopat.BoolAttribute, v bool) {
+	s.checkMetadata(k.Key(), v)
+	s.span.MetadataBool(k, v)
+}
+
+func (s *span) MetadataEnum(k *xopat.EnumAttribute, v xopat.Enum) {
+	s.checkMetadata(k.Key(), v)
+	s.span.MetadataEnum(k, v)
+}
+
+func (s *span) MetadataFloat64(k *xopat.Float64Attribute, v float64) {
+	s.checkMetadata(k.Key(), v)
+	s.span.MetadataFloat64(k, v)
+}
+
+func (s *span) MetadataInt64(k *xopat.Int64Attribute, v int64) {
+	s.checkMetadata(k.Key(), v)
+	s.span.MetadataInt64(k, v)
+}
+
+func (s *span) MetadataLink(k *xopat.LinkAttribute, v xoptrace.Trace) {
+	s.checkMetadata(k.Key(), v)
+	s.span.MetadataLink(k, v)
+}
+
+func (s *span) MetadataString(k *xopat.StringAttribute, v string) {
+	s.checkMetadata(k.Key(), v)
+	s.span.MetadataString(k, v)
+}
+
+func (s *span) MetadataTime(k *xopat.TimeAttribute, v time.Time) {
+	s.checkMetadata(k.Key(), v)
+	s.span.MetadataTime(k, v)
+}
+
+func (p *prefilling) PrefillComplete(msg string) xopbase.Prefilled {
+	return &prefilled{
+		span:      p.span,
+		prefilled: p.prefilling.PrefillComplete(msg),
+	}
+}
+
+func (p *prefilling) Enum(k *xopat.EnumAttribute, v xopat.Enum) { p.prefilling.Enum(k, v) }
+
+func (p *prefilling) Any(k xopat.K, v xopbase.ModelArg)   { p.prefilling.Any(k, v) }
+func (p *prefilling) Bool(k xopat.K, v bool)              { p.prefilling.Bool(k, v) }
+func (p *prefilling) Duration(k xopat.K, v time.Duration) { p.prefilling.Duration(k, v) }
+func (p *prefilling) Time(k xopat.K, v time.Time)         { p.prefilling.Time(k, v) }
+
+func (p *prefilling) Float64(k xopat.K, v float64, dt xopbase.DataType) {
+	p.prefilling.Float64(k, v, dt)
+}
+func (p *prefilling) Int64(k xopat.K, v int64, dt xopbase.DataType)   { p.prefilling.Int64(k, v, dt) }
+func (p *prefilling) String(k xopat.K, v string, dt xopbase.DataType) { p.prefilling.String(k, v, dt) }
+func (p *prefilling) Uint64(k xopat.K, v uint64, dt xopbase.DataType) { p.prefilling.Uint64(k, v, dt) }
+
+// Line returns xopbase.SkipLine for lines that are filtered out by level
+// or by span so that the caller can know that no work is needed.
+func (p *prefilled) Line(level xopnum.Level, ts time.Time, frames []runtime.Frame) xopbase.Line {
+	if level < p.span.logger.minLevel || p.span.isSuppressed() {
+		return xopbase.SkipLine
+	}
+	if p.span.logger.lineFilter == nil {
+		return p.prefilled.Line(level, ts, frames)
+	}
+	var framesCopy []runtime.Frame
+	if len(frames) > 0 {
+		framesCopy = make([]runtime.Frame, len(frames))
+		copy(framesCopy, frames)
+	}
+	return &pendingLine{
+		prefilled: p.prefilled,
+		filter:    p.span.logger.lineFilter,
+		ts:        ts,
+		frames:    framesCopy,
+		info: LineInfo{
+			Level: level,
+			Data:  make(map[string]interface{}),
+		},
+	}
+}
+
+// line returns nil if the line is filtered out
+func (l *pendingLine) line(msg string) xopbase.Line {
+	l.info.Message = msg
+	if !l.filter(l.info) {
+		return nil
+	}
+	line := l.prefilled.Line(l.info.Level, l.ts, l.frames)
+	for _, op := range l.ops {
+		op(line)
+	}
+	return line
+}
+
+func (l *pendingLine) Msg(msg string) {
+	if line := l.line(msg); line != nil {
+		line.Msg(msg)
+	}
+}
+
+func (l *pendingLine) Template(template string) {
+	if line := l.line(template); line != nil {
+		line.Template(template)
+	}
+}
+
+func (l *pendingLine) Model(msg string, v xopbase.ModelArg) {
+	if line := l.line(msg); line != nil {
+		line.Model(msg, v)
+	}
+}
+
+func (l *pendingLine) Table(msg string, t xopbase.SimpleTable) {
+	if line := l.line(msg); line != nil {
+		line.Table(msg, t)
+	}
+}
+
+func (l *pendingLine) Link(msg string, v xoptrace.Trace) {
+	if line := l.line(msg); line != nil {
+		line.Link(msg, v)
+	}
+}
+
+func (l *pendingLine) Enum(k *xopat.EnumAttribute, v xopat.Enum) {
+	l.info.Data[k.Key().String()] = v
+	l.ops = append(l.ops, func(line xopbase.Line) { line.Enum(k, v) })
+}
+
+func (l *pendingLine) Any(k xopat.K, v xopbase.ModelArg) {
+	l.info.Data[k.String()] = v
+	l.ops = append(l.ops, func(line xopbase.Line) { line.Any(k, v) })
+}
+
+func (l *pendingLine) Bool(k xopat.K, v bool) {
+	l.info.Data[k.String()] = v
+	l.ops = append(l.ops, func(line xopbase.Line) { line.Bool(k, v) })
+}
+
+func (l *pendingLine) Duration(k xopat.K, v time.Duration) {
+	l.info.Data[k.String()] = v
+	l.ops = append(l.ops, func(line xopbase.Line) { line.Duration(k, v) })
+}
+
+func (l *pendingLine) Time(k xopat.K, v time.Time) {
+	l.info.Data[k.String()] = v
+	l.ops = append(l.ops, func(line xopbase.Line) { line.Time(k, v) })
+}
+
+func (l *pendingLine) Float64(k xopat.K, v float64, dt xopbase.DataType) {
+	l.info.Data[k.String()] = v
+	l.ops = append(l.ops, func(line xopbase.Line) { line.Float64(k, v, dt) })
+}
+
+func (l *pendingLine) Int64(k xopat.K, v int64, dt xopbase.DataType) {
+	l.info.Data[k.String()] = v
+	l.ops = append(l.ops, func(line xopbase.Line) { line.Int64(k, v, dt) })
+}
+
+func (l *pendingLine) String(k xopat.K, v string, dt xopbase.DataType) {
+	l.info.Data[k.String()] = v
+	l.ops = append(l.ops, func(line xopbase.Line) { line.String(k, v, dt) })
+}
+
+func (l *pendingLine) Uint64(k xopat.K, v uint64, dt xopbase.DataType) {
+	l.info.Data[k.String()] = v
+	l.ops = append(l.ops, func(line xopbase.Line) { line.Uint64(k, v, dt) })
+}
+
+func (d discard) ID() string                                                 { return d.id }
+func (d discard) Boring(bool) bool                                           { return false }
+func (d discard) Done(time.Time, bool)                                       {}
+func (d discard) Metric(*xopat.MetricAttribute, float64, time.Time)          {}
+func (d discard) Flush()                                                     {}
+func (d discard) Final()                                                     {}
+func (d discard) SetErrorReporter(func(error))                               {}
+func (d discard) NoPrefill() xopbase.Prefilled                               { return d }
+func (d discard) StartPrefill() xopbase.Prefilling                           { return d }
+func (d discard) PrefillComplete(string) xopbase.Prefilled                   { return d }
+func (d discard) Line(xopnum.Level, time.Time, []runtime.Frame) xopbase.Line { return xopbase.SkipLine }
+func (d discard) Enum(*xopat.EnumAttribute, xopat.Enum)                      {}
+func (d discard) Span(context.Context, time.Time, xoptrace.Bundle, string, string) xopbase.Span {
+	return d
+}
+
+func (d discard) MetadataAny(*xopat.AnyAttribute, xopbase.ModelArg) {}
+func (d discard) MetadataBool(*xopat.BoolAttribute, bool)           {}
+func (d discard) MetadataEnum(*xopat.EnumAttribute, xopat.Enum)     {}
+func (d discard) MetadataFloat64(*xopat.Float64Attribute, float64)  {}
+func (d discard) MetadataInt64(*xopat.Int64Attribute, int64)        {}
+func (d discard) MetadataLink(*xopat.LinkAttribute, xoptrace.Trace) {}
+func (d discard) MetadataString(*xopat.StringAttribute, string)     {}
+func (d discard) MetadataTime(*xopat.TimeAttribute, time.Time)      {}
+
+func (d discard) Any(xopat.K, xopbase.ModelArg)   {}
+func (d discard) Bool(xopat.K, bool)              {}
+func (d discard) Duration(xopat.K, time.Duration) {}
+func (d discard) Time(xopat.K, time.Time)         {}
+
+func (d discard) Float64(xopat.K, float64, xopbase.DataType) {}
+func (d discard) Int64(xopat.K, int64, xopbase.DataType)     {}
+func (d discard) String(xopat.K, string, xopbase.DataType)   {}
+func (d discard) Uint64(xopat.K, uint64, xopbase.DataType)   {}
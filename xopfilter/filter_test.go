@@ -0,0 +1,102 @@
+package xopfilter_test
+
+import (
+	"testing"
+
+	"github.com/xoplog/xop-go"
+	"github.com/xoplog/xop-go/xopat"
+	"github.com/xoplog/xop-go/xopbase"
+	"github.com/xoplog/xop-go/xopconst"
+	"github.com/xoplog/xop-go/xopfilter"
+	"github.com/xoplog/xop-go/xopnum"
+	"github.com/xoplog/xop-go/xoprecorder"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMinLevel(t *testing.T) {
+	all := xoprecorder.New()
+	info := xoprecorder.New()
+	log := xop.NewSeed(
+		xop.WithBase(all),
+		xop.WithBase(xopfilter.New(info, xopfilter.WithMinLevel(xopnum.InfoLevel))),
+	).Request(t.Name())
+	log.Debug().Int("i", 1).Msg("debug")
+	log.Info().Msg("info")
+	step := log.Sub().PrefillText("pre: ").Step("step")
+	step.Debug().Msg("step debug")
+	step.Warn().Msg("step warn")
+	step.Done()
+	log.Done()
+
+	assert.Equal(t, 1, all.CountLines(xoprecorder.MessageEquals("debug")))
+	assert.Equal(t, 1, all.CountLines(xoprecorder.MessageEquals("pre: step debug")))
+	assert.Equal(t, 0, info.CountLines(xoprecorder.MessageEquals("debug")))
+	assert.Equal(t, 0, info.CountLines(xoprecorder.MessageEquals("pre: step debug")))
+	assert.Equal(t, 1, info.CountLines(xoprecorder.MessageEquals("info")))
+	assert.Equal(t, 1, info.CountLines(xoprecorder.MessageEquals("pre: step warn")), "prefilled")
+	require.Len(t, info.Requests, 1)
+	require.Len(t, info.Spans, 1, "spans pass through")
+	assert.False(t, info.Spans[0].EndTime == 0, "done passed through")
+}
+
+func TestRequestFilter(t *testing.T) {
+	rLog := xoprecorder.New()
+	seed := xop.NewSeed(xop.WithBase(xopfilter.New(rLog,
+		xopfilter.WithRequestFilter(func(name string, _ xopbase.SourceInfo) bool {
+			return name != "health"
+		}))))
+	log := seed.Request("health")
+	log.Error().Msg("ignored")
+	log.Sub().Fork("child").Info().Msg("ignored")
+	log.Done()
+	log = seed.Request("work")
+	log.Info().Msg("kept")
+	log.Done()
+
+	require.Len(t, rLog.Requests, 1)
+	assert.Equal(t, "work", rLog.Requests[0].Name)
+	assert.Equal(t, 0, rLog.CountLines(xoprecorder.MessageEquals("ignored")))
+	assert.Equal(t, 1, rLog.CountLines(xoprecorder.MessageEquals("kept")))
+}
+
+func TestSpanMetadataFilter(t *testing.T) {
+	rLog := xoprecorder.New()
+	log := xop.NewSeed(xop.WithBase(xopfilter.New(rLog,
+		xopfilter.WithSpanMetadataFilter(func(k xopat.K, v interface{}) bool {
+			return !(k == xopconst.URL.Key() && v == "/health")
+		})))).Request(t.Name())
+	log.Info().Msg("before")
+	child := log.Sub().Fork("child")
+	log.Span().String(xopconst.URL, "/health")
+	log.Info().Msg("after")
+	child.Info().Msg("child after")
+	child.Done()
+	log.Done()
+
+	assert.Equal(t, 1, rLog.CountLines(xoprecorder.MessageEquals("before")))
+	assert.Equal(t, 0, rLog.CountLines(xoprecorder.MessageEquals("after")))
+	assert.Equal(t, 0, rLog.CountLines(xoprecorder.MessageEquals("child after")))
+	require.Len(t, rLog.Requests, 1)
+	assert.Equal(t, "/health", rLog.Requests[0].SpanMetadata.Get(xopconst.URL.Key().String()).Value, "metadata passed through")
+}
+
+func TestLineFilter(t *testing.T) {
+	rLog := xoprecorder.New()
+	log := xop.NewSeed(xop.WithBase(xopfilter.New(rLog,
+		xopfilter.WithLineFilter(func(line xopfilter.LineInfo) bool {
+			_, noisy := line.Data["noisy"]
+			return !noisy || line.Level >= xopnum.WarnLevel
+		})))).Request(t.Name())
+	log.Info().Bool("noisy", true).Msg("dropped")
+	log.Warn().Bool("noisy", true).Int("i", 3).Msg("kept warn")
+	log.Info().String("s", "x").Template("kept {s}")
+	log.Done()
+
+	assert.Equal(t, 0, rLog.CountLines(xoprecorder.MessageEquals("dropped")))
+	lines := rLog.FindLines(xoprecorder.MessageEquals("kept warn"))
+	require.Len(t, lines, 1)
+	assert.Equal(t, int64(3), lines[0].Data["i"], "data replayed")
+	assert.Equal(t, 1, rLog.CountLines(xoprecorder.MessageEquals("kept x")), "template")
+}
@@ -0,0 +1,44 @@
+package xopgrpc
+
+import (
+	"strings"
+
+	"github.com/xoplog/xop-go/xoptrace"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// MetadataCarrier adapts gRPC metadata to be an xoptrace.Carrier
+type MetadataCarrier metadata.MD
+
+var _ xoptrace.Carrier = MetadataCarrier{}
+
+func (m MetadataCarrier) Get(key string) string {
+	return strings.Join(metadata.MD(m).Get(key), ",")
+}
+func (m MetadataCarrier) Set(key string, value string) { metadata.MD(m).Set(key, value) }
+
+// Option modifies the behavior of the interceptors
+type Option func(*config)
+
+type config struct {
+	propagator xoptrace.Propagator
+}
+
+// WithPropagator overrides xoptrace.Default for reading and
+// writing the trace in gRPC metadata.
+func WithPropagator(propagator xoptrace.Propagator) Option {
+	return func(c *config) {
+		c.propagator = propagator
+	}
+}
+
+func makeConfig(opts []Option) config {
+	c := config{
+		propagator: xoptrace.Default,
+	}
+	for _, f := range opts {
+		f(&c)
+	}
+	return c
+}
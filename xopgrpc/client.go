@@ -0,0 +1,153 @@
+package xopgrpc
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/xoplog/xop-go"
+	"github.com/xoplog/xop-go/xopconst"
+	"github.com/xoplog/xop-go/xoptrace"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryClientInterceptor creates a child span for each outgoing unary
+// call. The parent logger is found in the context.  If there is no
+// logger in the context, the call is passed through unmodified.
+func UnaryClientInterceptor(opts ...Option) grpc.UnaryClientInterceptor {
+	c := makeConfig(opts)
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		parent, ok := xop.FromContext(ctx)
+		if !ok {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		log := startClientSpan(parent, method)
+		defer log.Done()
+		var header metadata.MD
+		opts = append(opts, grpc.Header(&header))
+		err := invoker(c.outgoingContext(ctx, log), method, req, reply, cc, opts...)
+		recordTraceResponse(log, header)
+		var received int64
+		if err == nil {
+			received = 1
+		}
+		finish(log, err, 1, received)
+		return err
+	}
+}
+
+// StreamClientInterceptor creates a child span for each outgoing
+// streaming call. The parent logger is found in the context. If there
+// is no logger in the context, the call is passed through unmodified.
+//
+// The span is Done when RecvMsg returns an error (including io.EOF),
+// when RecvMsg returns the response of a call that is not server
+// streaming, or when the stream's context is cancelled.
+func StreamClientInterceptor(opts ...Option) grpc.StreamClientInterceptor {
+	c := makeConfig(opts)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		parent, ok := xop.FromContext(ctx)
+		if !ok {
+			return streamer(ctx, desc, cc, method, opts...)
+		}
+		log := startClientSpan(parent, method)
+		ctx = c.outgoingContext(ctx, log)
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			finish(log, err, 0, 0)
+			log.Done()
+			return cs, err
+		}
+		wrapped := &clientStream{
+			ClientStream:  cs,
+			log:           log,
+			serverStreams: desc.ServerStreams,
+			finished:      make(chan struct{}),
+		}
+		go func() {
+			select {
+			case <-ctx.Done():
+				wrapped.done(status.FromContextError(ctx.Err()).Err())
+			case <-wrapped.finished:
+			}
+		}()
+		return wrapped, nil
+	}
+}
+
+func startClientSpan(parent *xop.Logger, method string) *xop.Logger {
+	log := parent.Sub().Fork(method)
+	log.Span().Enum(xopconst.SpanKind, xopconst.SpanKindClient)
+	log.Span().EmbeddedEnum(xopconst.SpanTypeGRPCClientRequest)
+	describe(log, method)
+	return log
+}
+
+func (c config) outgoingContext(ctx context.Context, log *xop.Logger) context.Context {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	for _, field := range c.propagator.Fields() {
+		delete(md, field)
+	}
+	c.propagator.Inject(MetadataCarrier(md), log.Span().Bundle())
+	return metadata.NewOutgoingContext(log.IntoContext(ctx), md)
+}
+
+func recordTraceResponse(log *xop.Logger, header metadata.MD) {
+	if tr := header.Get("traceresponse"); len(tr) != 0 {
+		if trace, ok := xoptrace.TraceFromString(tr[0]); ok {
+			log.Span().Link(xopconst.RemoteTrace, trace)
+		}
+	}
+}
+
+type clientStream struct {
+	grpc.ClientStream
+	log           *xop.Logger
+	serverStreams bool
+	sent          int64
+	received      int64
+	once          sync.Once
+	finished      chan struct{}
+}
+
+func (s *clientStream) SendMsg(m interface{}) error {
+	err := s.ClientStream.SendMsg(m)
+	if err == nil {
+		atomic.AddInt64(&s.sent, 1)
+	}
+	return err
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err == nil {
+		atomic.AddInt64(&s.received, 1)
+		if !s.serverStreams {
+			// there is only one response and it has been received
+			s.done(nil)
+		}
+		return nil
+	}
+	if err == io.EOF {
+		s.done(nil)
+	} else {
+		s.done(err)
+	}
+	return err
+}
+
+func (s *clientStream) done(err error) {
+	s.once.Do(func() {
+		if header, herr := s.ClientStream.Header(); herr == nil {
+			recordTraceResponse(s.log, header)
+		}
+		finish(s.log, err, atomic.LoadInt64(&s.sent), atomic.LoadInt64(&s.received))
+		s.log.Done()
+		close(s.finished)
+	})
+}
@@ -0,0 +1,245 @@
+package xopgrpc_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/xoplog/xop-go"
+	"github.com/xoplog/xop-go/xopgrpc"
+	"github.com/xoplog/xop-go/xopproto"
+	"github.com/xoplog/xop-go/xoprecorder"
+	"github.com/xoplog/xop-go/xoptest"
+	"github.com/xoplog/xop-go/xoptrace"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type ingestServer struct {
+	xopproto.UnimplementedIngestServer
+	t *testing.T
+}
+
+func (s ingestServer) Ping(ctx context.Context, _ *xopproto.Empty) (*xopproto.Empty, error) {
+	log, ok := xop.FromContext(ctx)
+	if assert.True(s.t, ok, "logger in server context") {
+		v, _ := log.Span().TraceBaggage().Get("tenant")
+		assert.Equal(s.t, "acme", v, "server baggage")
+		assert.Equal(s.t, "congo=t61rcWkgMzE", log.Span().TraceState().String(), "server trace state")
+	}
+	return &xopproto.Empty{}, nil
+}
+
+func (s ingestServer) UploadFragment(context.Context, *xopproto.IngestFragment) (*xopproto.ErrorResponse, error) {
+	return nil, status.Error(codes.Internal, "broken")
+}
+
+var echoDesc = grpc.ServiceDesc{
+	ServiceName: "test.Echo",
+	HandlerType: (*interface{})(nil),
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Echo",
+			ServerStreams: true,
+			ClientStreams: true,
+			Handler: func(_ interface{}, stream grpc.ServerStream) error {
+				for {
+					var m xopproto.Empty
+					err := stream.RecvMsg(&m)
+					if err == io.EOF {
+						return nil
+					}
+					if err != nil {
+						return err
+					}
+					if err := stream.SendMsg(&m); err != nil {
+						return err
+					}
+				}
+			},
+		},
+		{
+			StreamName:    "Count",
+			ClientStreams: true,
+			Handler: func(_ interface{}, stream grpc.ServerStream) error {
+				for {
+					err := stream.RecvMsg(&xopproto.Empty{})
+					if err == io.EOF {
+						return stream.SendMsg(&xopproto.Empty{})
+					}
+					if err != nil {
+						return err
+					}
+				}
+			},
+		},
+	},
+}
+
+func setup(t *testing.T, tLog *xoptest.Logger) *grpc.ClientConn {
+	lis := bufconn.Listen(1024 * 1024)
+	seed := xop.NewSeed(xop.WithBase(tLog))
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(xopgrpc.UnaryServerInterceptor(seed)),
+		grpc.StreamInterceptor(xopgrpc.StreamServerInterceptor(seed)),
+	)
+	xopproto.RegisterIngestServer(server, ingestServer{t: t})
+	server.RegisterService(&echoDesc, struct{}{})
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(xopgrpc.UnaryClientInterceptor()),
+		grpc.WithStreamInterceptor(xopgrpc.StreamClientInterceptor()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func findRequest(t *testing.T, tLog *xoptest.Logger, name string) *xoprecorder.Span {
+	for _, r := range tLog.Recorder().Requests {
+		if r.Name == name {
+			return r
+		}
+	}
+	t.Fatalf("no request named %s", name)
+	return nil
+}
+
+func findSpan(t *testing.T, tLog *xoptest.Logger, name string) *xoprecorder.Span {
+	for _, s := range tLog.Recorder().Spans {
+		if s.Name == name {
+			return s
+		}
+	}
+	t.Fatalf("no span named %s", name)
+	return nil
+}
+
+func assertMetadata(t *testing.T, span *xoprecorder.Span, want map[string]interface{}) {
+	for k, v := range want {
+		md := span.SpanMetadata.Get(k)
+		if assert.NotNilf(t, md, "has %s", k) {
+			assert.Equal(t, v, md.Value, k)
+		}
+	}
+}
+
+func TestUnary(t *testing.T) {
+	tLog := xoptest.New(t)
+	conn := setup(t, tLog)
+	var state xoptrace.State
+	state.SetString("congo=t61rcWkgMzE")
+	log := xop.NewSeed(xop.WithBase(tLog), xop.WithTraceState(state)).Request(t.Name())
+	require.NoError(t, log.Span().SetBaggage("tenant", "acme"))
+	client := xopproto.NewIngestClient(conn)
+
+	_, err := client.Ping(log.IntoContext(context.Background()), &xopproto.Empty{})
+	require.NoError(t, err)
+	_, err = client.UploadFragment(log.IntoContext(context.Background()), &xopproto.IngestFragment{})
+	require.Error(t, err)
+	log.Done()
+
+	clientSpan := findSpan(t, tLog, "/xop.Ingest/Ping")
+	serverRequest := findRequest(t, tLog, "/xop.Ingest/Ping")
+	assert.Equal(t, clientSpan.Bundle.Trace.String(), serverRequest.Bundle.Parent.String(), "server parent is client span")
+	assert.Equal(t, clientSpan.Bundle.Trace.TraceID().String(), serverRequest.Bundle.Trace.TraceID().String(), "same trace")
+	expect := map[string]interface{}{
+		"rpc.system":            "grpc",
+		"rpc.service":           "xop.Ingest",
+		"rpc.method":            "Ping",
+		"rpc.grpc.status_code":  int64(codes.OK),
+		"rpc.messages_sent":     int64(1),
+		"rpc.messages_received": int64(1),
+	}
+	assertMetadata(t, clientSpan, expect)
+	assertMetadata(t, serverRequest, expect)
+	if md := clientSpan.SpanMetadata.Get("http.remote_trace"); assert.NotNil(t, md, "remote trace") {
+		assert.Equal(t, serverRequest.Bundle.Trace.String(), md.Value.(xoptrace.Trace).String())
+	}
+
+	failedClient := findSpan(t, tLog, "/xop.Ingest/UploadFragment")
+	failedServer := findRequest(t, tLog, "/xop.Ingest/UploadFragment")
+	assertMetadata(t, failedClient, map[string]interface{}{
+		"rpc.grpc.status_code":  int64(codes.Internal),
+		"rpc.messages_sent":     int64(1),
+		"rpc.messages_received": int64(0),
+	})
+	assertMetadata(t, failedServer, map[string]interface{}{
+		"rpc.grpc.status_code":  int64(codes.Internal),
+		"rpc.messages_sent":     int64(0),
+		"rpc.messages_received": int64(1),
+	})
+	assert.Equal(t, 2, tLog.Recorder().CountLines(xoprecorder.MessageEquals("rpc failed")), "client and server errors")
+}
+
+func TestStream(t *testing.T) {
+	tLog := xoptest.New(t)
+	conn := setup(t, tLog)
+	log := xop.NewSeed(xop.WithBase(tLog)).Request(t.Name())
+
+	stream, err := conn.NewStream(log.IntoContext(context.Background()), &echoDesc.Streams[0], "/test.Echo/Echo")
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, stream.SendMsg(&xopproto.Empty{}))
+		require.NoError(t, stream.RecvMsg(&xopproto.Empty{}))
+	}
+	require.NoError(t, stream.CloseSend())
+	assert.Equal(t, io.EOF, stream.RecvMsg(&xopproto.Empty{}))
+	log.Done()
+
+	expect := map[string]interface{}{
+		"rpc.service":           "test.Echo",
+		"rpc.method":            "Echo",
+		"rpc.grpc.status_code":  int64(codes.OK),
+		"rpc.messages_sent":     int64(3),
+		"rpc.messages_received": int64(3),
+	}
+	assertMetadata(t, findSpan(t, tLog, "/test.Echo/Echo"), expect)
+	assert.Eventually(t, func() bool {
+		for _, r := range tLog.Recorder().Requests {
+			if r.Name == "/test.Echo/Echo" && r.SpanMetadata.Get("rpc.messages_sent") != nil {
+				return true
+			}
+		}
+		return false
+	}, time.Second, time.Millisecond, "server request finished")
+	assertMetadata(t, findRequest(t, tLog, "/test.Echo/Echo"), expect)
+}
+
+func TestClientStream(t *testing.T) {
+	tLog := xoptest.New(t)
+	conn := setup(t, tLog)
+	log := xop.NewSeed(xop.WithBase(tLog)).Request(t.Name())
+
+	stream, err := conn.NewStream(log.IntoContext(context.Background()), &echoDesc.Streams[1], "/test.Echo/Count")
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, stream.SendMsg(&xopproto.Empty{}))
+	}
+	// CloseAndRecv
+	require.NoError(t, stream.CloseSend())
+	require.NoError(t, stream.RecvMsg(&xopproto.Empty{}))
+
+	span := findSpan(t, tLog, "/test.Echo/Count")
+	assertMetadata(t, span, map[string]interface{}{
+		"rpc.service":           "test.Echo",
+		"rpc.method":            "Count",
+		"rpc.grpc.status_code":  int64(codes.OK),
+		"rpc.messages_sent":     int64(3),
+		"rpc.messages_received": int64(1),
+	})
+	log.Done()
+}
@@ -0,0 +1,137 @@
+/*
+Package xopgrpc provides gRPC interceptors that create xop spans.
+
+The server interceptors start a new Request for each incoming call,
+picking up the trace from "traceparent", "tracestate", and "baggage"
+metadata. The client interceptors create a child span (with Fork) of
+the logger found in the context and send that same metadata. Other
+formats can be used with WithPropagator.
+*/
+package xopgrpc
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+
+	"github.com/xoplog/xop-go"
+	"github.com/xoplog/xop-go/xopconst"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor starts a Request for each incoming unary
+// call. The logger is available to the handler with
+// xop.FromContext.
+func UnaryServerInterceptor(seed xop.Seed, opts ...Option) grpc.UnaryServerInterceptor {
+	c := makeConfig(opts)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		log := c.startRequest(ctx, seed, info.FullMethod)
+		defer log.Done()
+		_ = grpc.SetHeader(ctx, metadata.Pairs("traceresponse", log.Span().Trace().String()))
+		resp, err := handler(log.IntoContext(ctx), req)
+		var sent int64
+		if err == nil {
+			sent = 1
+		}
+		finish(log, err, sent, 1)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor starts a Request for each incoming
+// streaming call. The logger is available to the handler with
+// xop.FromContext(stream.Context()).
+func StreamServerInterceptor(seed xop.Seed, opts ...Option) grpc.StreamServerInterceptor {
+	c := makeConfig(opts)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		log := c.startRequest(ss.Context(), seed, info.FullMethod)
+		defer log.Done()
+		_ = ss.SetHeader(metadata.Pairs("traceresponse", log.Span().Trace().String()))
+		wrapped := &serverStream{
+			ServerStream: ss,
+			ctx:          log.IntoContext(ss.Context()),
+		}
+		err := handler(srv, wrapped)
+		finish(log, err, atomic.LoadInt64(&wrapped.sent), atomic.LoadInt64(&wrapped.received))
+		return err
+	}
+}
+
+func (c config) startRequest(ctx context.Context, seed xop.Seed, fullMethod string) *xop.Logger {
+	bundle := seed.Bundle()
+	md, _ := metadata.FromIncomingContext(ctx)
+	c.propagator.Extract(MetadataCarrier(md), &bundle)
+	if bundle.Trace.TraceID().IsZero() {
+		bundle.Trace.TraceID().SetRandom()
+	}
+	if bundle.Trace.SpanID().IsZero() {
+		bundle.Trace.SpanID().SetRandom()
+	}
+	log := seed.Copy(
+		xop.WithContext(ctx),
+		xop.WithBundle(bundle),
+	).Request(fullMethod)
+	log.Span().Enum(xopconst.SpanKind, xopconst.SpanKindServer)
+	log.Span().EmbeddedEnum(xopconst.SpanTypeGRPCServerEndpoint)
+	describe(log, fullMethod)
+	return log
+}
+
+func describe(log *xop.Logger, fullMethod string) {
+	log.Span().String(xopconst.RPCSystem, "grpc")
+	service, method := splitMethod(fullMethod)
+	log.Span().String(xopconst.RPCService, service)
+	log.Span().String(xopconst.RPCMethod, method)
+}
+
+// splitMethod splits "/package.Service/Method"
+func splitMethod(fullMethod string) (string, string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndexByte(fullMethod, '/'); i != -1 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+	return "", fullMethod
+}
+
+// finish records the status code and message counts.  Codes
+// that indicate a server-side problem are logged at the Error
+// level which also marks the request as not boring.
+func finish(log *xop.Logger, err error, sent int64, received int64) {
+	code := status.Code(err)
+	log.Span().Int(xopconst.RPCGRPCStatusCode, int(code))
+	log.Span().Int64(xopconst.RPCMessagesSent, sent)
+	log.Span().Int64(xopconst.RPCMessagesReceived, received)
+	switch code {
+	case codes.Unknown, codes.Internal, codes.DataLoss, codes.Unavailable:
+		log.Error().String("code", code.String()).Error("error", err).Msg("rpc failed")
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	sent     int64
+	received int64
+}
+
+func (s *serverStream) Context() context.Context { return s.ctx }
+
+func (s *serverStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		atomic.AddInt64(&s.sent, 1)
+	}
+	return err
+}
+
+func (s *serverStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		atomic.AddInt64(&s.received, 1)
+	}
+	return err
+}
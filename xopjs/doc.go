@@ -0,0 +1,59 @@
+/*
+Package xopjs is a xop base logger (xopbase.Logger) that encodes in
+the most natural JSON it can. Unlike xopjson, it does not preserve
+type information so it cannot be replayed into other base loggers.
+
+The output is a stream of newline-delimited objects. Lines look like
+(actual encoding w/o whitespace):
+
+	{
+		"ts": "2023-03-30T21:27:36.901822-07:00",
+		"trace.id": "045fbbb27fab63e80bdef127c35e9abe",
+		"span.id": "e006cc70e2453480",
+		"lvl": "info",
+		"msg": "a test line",
+		"foo": "bar",
+		"blast": 99
+	}
+
+Attributes are plain JSON values: strings, numbers, and booleans.
+Enums are their names, durations are nanoseconds, times use the
+TimeFormatter, links are traceparent strings, and JSON models are
+embedded. Tables are arrays of objects keyed by the column headers.
+Templates are expanded in the message.
+
+Attributes are part of the main object unless WithAttributesObject
+is used, in which case they are in an "attributes" object. When they
+are part of the main object, attributes can collide with the fields
+named by the Profile.
+
+Metrics have "type":"metric" with "metric" and "value" fields.
+
+# Spans
+
+Spans and requests are written each time Done is called with their
+metadata as a flat object:
+
+	{
+		"type": "span",
+		"ts": "2023-03-30T21:27:36.902446-07:00",
+		"trace.id": "045fbbb27fab63e80bdef127c35e9abe",
+		"span.id": "193586833ecbd336",
+		"span.name": "a fork one span",
+		"dur": 216000,
+		"span.parent_span": "70adac21637a869d",
+		"span.seq": ".A",
+		"http.route": "/some/thing"
+	}
+
+Metadata attributes with Multiple set are arrays. The same span can
+be written more than once: the last copy is the most complete.
+
+# Profiles
+
+The names of the time, level, message, trace id, span id, and
+duration fields come from a Profile. DefaultProfile matches xopjson.
+ECSProfile, GCPProfile, and DatadogProfile match what Elastic, Google
+Cloud Logging, and Datadog expect.
+*/
+package xopjs
@@ -0,0 +1,552 @@
+package xopjs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/xoplog/xop-go/xopat"
+	"github.com/xoplog/xop-go/xopbase"
+	"github.com/xoplog/xop-go/xopbase/xopbaseutil"
+	"github.com/xoplog/xop-go/xopbytes"
+	"github.com/xoplog/xop-go/xopconst"
+	"github.com/xoplog/xop-go/xopnum"
+	"github.com/xoplog/xop-go/xopproto"
+	"github.com/xoplog/xop-go/xoptrace"
+	"github.com/xoplog/xop-go/xoputil"
+
+	"github.com/google/uuid"
+)
+
+// New creates a Logger that writes newline-delimited JSON objects
+// to a BytesWriter.
+func New(w xopbytes.BytesWriter, opts ...Option) *Logger {
+	log := &Logger{
+		id:            uuid.New(),
+		writer:        w,
+		timeFormatter: DefaultTimeFormatter,
+		profile:       DefaultProfile,
+	}
+	for _, f := range opts {
+		f(log)
+	}
+	return log
+}
+
+// DefaultTimeFormatter formats times as time.RFC3339Nano strings
+func DefaultTimeFormatter(b []byte, t time.Time) []byte {
+	b = append(b, '"')
+	b = t.AppendFormat(b, time.RFC3339Nano)
+	return append(b, '"')
+}
+
+// WithTimeFormatter specifies how time.Time should be
+// serialized to JSON.  The default is time.RFC3339Nano.
+//
+// Note: if serializing as a number, integers beyond 2^50
+// may lose precision because they're actually read as
+// float64s.
+func WithTimeFormatter(formatter TimeFormatter) Option {
+	return func(l *Logger) {
+		l.timeFormatter = formatter
+	}
+}
+
+// WithAttributesObject specifies if the user-defined
+// attributes on lines, spans, and requests should be
+// inside an "attributes" sub-object or part of the main
+// object. The default is part of the main object.
+func WithAttributesObject(b bool) Option {
+	return func(l *Logger) {
+		l.attributesObject = b
+	}
+}
+
+// WithProfile sets the names of the fields that are not
+// attributes. The default is DefaultProfile.
+func WithProfile(profile Profile) Option {
+	return func(l *Logger) {
+		l.profile = profile
+	}
+}
+
+func (logger *Logger) ID() string           { return logger.id.String() }
+func (logger *Logger) Buffered() bool       { return logger.writer.Buffered() }
+func (logger *Logger) ReferencesKept() bool { return false }
+
+func (logger *Logger) Request(_ context.Context, ts time.Time, bundle xoptrace.Bundle, name string, sourceInfo xopbase.SourceInfo) xopbase.Request {
+	r := &request{
+		span: span{
+			logger:    logger,
+			bundle:    bundle,
+			name:      name,
+			startTime: ts,
+			endTime:   ts.UnixNano(),
+			isRequest: true,
+		},
+		sourceInfo: sourceInfo,
+		errorFunc:  func(error) {},
+	}
+	r.request = r
+	r.span.setIDs()
+	r.writer = logger.writer.Request(r)
+	return r
+}
+
+// Flush is skipped for boring requests that have no lines at the
+// Error or Alert level.
+func (r *request) Flush() {
+	if atomic.LoadInt32(&r.boring) == 1 && r.GetErrorCount() == 0 && r.GetAlertCount() == 0 {
+		return
+	}
+	err := r.writer.Flush()
+	if err != nil {
+		r.errorFunc(err)
+	}
+}
+
+// Boring is honored for requests. If the writer is buffered, boring
+// requests are not flushed. Otherwise, they're tagged with
+// xopconst.Boring.
+func (r *request) Boring(b bool) bool {
+	if r.logger.writer.Buffered() {
+		if b {
+			atomic.StoreInt32(&r.boring, 1)
+		} else {
+			atomic.StoreInt32(&r.boring, 0)
+		}
+		return true
+	}
+	r.MetadataBool(xopconst.Boring, b)
+	return true
+}
+
+func (r *request) Final() {
+	r.writer.ReclaimMemory()
+}
+
+func (r *request) SetErrorReporter(reporter func(error)) { r.errorFunc = reporter }
+func (r *request) GetErrorCount() int32                  { return atomic.LoadInt32(&r.errorCount) }
+func (r *request) GetAlertCount() int32                  { return atomic.LoadInt32(&r.alertCount) }
+
+func (s *span) Span(_ context.Context, ts time.Time, bundle xoptrace.Bundle, name string, spanSequenceCode string) xopbase.Span {
+	n := &span{
+		logger:       s.logger,
+		request:      s.request,
+		bundle:       bundle,
+		name:         name,
+		sequenceCode: spanSequenceCode,
+		startTime:    ts,
+		endTime:      ts.UnixNano(),
+	}
+	n.setIDs()
+	return n
+}
+
+// setIDs pre-encodes the trace and span ids that are included in
+// every line
+func (s *span) setIDs() {
+	var b xoputil.JBuilder
+	p := s.logger.profile
+	if p.TraceID != "" {
+		b.AddKey(p.TraceID)
+		if p.FormatTraceID != nil {
+			b.AddString(p.FormatTraceID(s.bundle.Trace.GetTraceID()))
+		} else {
+			b.AddSafeString(s.bundle.Trace.GetTraceID().String())
+		}
+	}
+	if p.SpanID != "" {
+		b.AddKey(p.SpanID)
+		if p.FormatSpanID != nil {
+			b.AddString(p.FormatSpanID(s.bundle.Trace.GetSpanID()))
+		} else {
+			b.AddSafeString(s.bundle.Trace.GetSpanID().String())
+		}
+	}
+	s.ids = b.B
+}
+
+// Done writes the span with all of its metadata. The same span
+// can be written more than once. The last copy is the most complete.
+func (s *span) Done(t time.Time, _ bool) {
+	xoputil.AtomicMaxInt64(&s.endTime, t.UnixNano())
+	p := s.logger.profile
+	b := xoputil.JBuilder{
+		B: make([]byte, 0, 512),
+	}
+	b.AppendByte('{') // }
+	if s.isRequest {
+		b.AddSafeKey("type")
+		b.AddSafeString("request")
+	} else {
+		b.AddSafeKey("type")
+		b.AddSafeString("span")
+	}
+	if p.Time != "" {
+		b.AddKey(p.Time)
+		b.B = s.logger.timeFormatter(b.B, s.startTime)
+	}
+	b.Comma()
+	b.AppendBytes(s.ids)
+	b.AddSafeKey("span.name")
+	b.AddString(s.name)
+	if p.Duration != "" {
+		b.AddKey(p.Duration)
+		b.AddInt64(atomic.LoadInt64(&s.endTime) - s.startTime.UnixNano())
+	}
+	if s.isRequest {
+		if !s.bundle.Parent.IsZero() {
+			b.AddSafeKey("trace.parent")
+			b.AddSafeString(s.bundle.Parent.String())
+		}
+		if !s.bundle.State.IsZero() {
+			b.AddSafeKey("trace.state")
+			b.AddString(s.bundle.State.String())
+		}
+		if !s.bundle.Baggage.IsZero() {
+			b.AddSafeKey("trace.baggage")
+			b.AddString(s.bundle.Baggage.String())
+		}
+		b.AddSafeKey("source")
+		b.AddString(s.request.sourceInfo.Source + " " + s.request.sourceInfo.SourceVersion.String())
+		b.AddSafeKey("ns")
+		b.AddString(s.request.sourceInfo.Namespace + " " + s.request.sourceInfo.NamespaceVersion.String())
+	} else {
+		b.AddSafeKey("span.parent_span")
+		b.AddSafeString(s.bundle.Parent.GetSpanID().String())
+		b.AddSafeKey("span.seq")
+		b.AddString(s.sequenceCode)
+	}
+	if s.logger.attributesObject {
+		b.AddSafeKey("attributes")
+		b.AppendByte('{') // }
+	}
+	s.SpanMetadata.Map.Range(func(k string, tracker *xopbaseutil.MetadataTracker) bool {
+		tracker.Mu.Lock()
+		defer tracker.Mu.Unlock()
+		b.AddKey(k)
+		s.metadataValue(&b, tracker.Value)
+		return true
+	})
+	if s.logger.attributesObject {
+		// {
+		b.AppendByte('}')
+	}
+	// {
+	b.AppendBytes([]byte{'}', '\n'})
+	err := s.request.writer.Span(s, record(b.B))
+	if err != nil {
+		s.request.errorFunc(err)
+	}
+}
+
+// metadataValue encodes span metadata. Attributes with Multiple
+// set are arrays.
+func (s *span) metadataValue(b *xoputil.JBuilder, v interface{}) {
+	switch v := v.(type) {
+	case []interface{}:
+		b.AppendByte('[')
+		for _, e := range v {
+			b.Comma()
+			s.metadataValue(b, e)
+		}
+		b.AppendByte(']')
+	case string:
+		b.AddString(v)
+	case bool:
+		b.AddBool(v)
+	case int64:
+		b.AddInt64(v)
+	case float64:
+		addFloat64(b, v)
+	case time.Time:
+		b.B = s.logger.timeFormatter(b.B, v)
+	case xoptrace.Trace:
+		b.AddSafeString(v.String())
+	case xopat.Enum:
+		b.AddString(v.String())
+	case xopbase.ModelArg:
+		addModel(b, v)
+	default:
+		b.AddString(fmt.Sprint(v))
+	}
+}
+
+// addFloat64 encodes floats that JSON cannot represent as strings
+func addFloat64(b *xoputil.JBuilder, f float64) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		b.AddSafeString(strconv.FormatFloat(f, 'g', -1, 64))
+		return
+	}
+	b.AddFloat64(f)
+}
+
+// addModel embeds JSON models and encodes other models as strings
+func addModel(b *xoputil.JBuilder, v xopbase.ModelArg) {
+	v.Encode()
+	if v.Encoding == xopproto.Encoding_JSON && json.Valid(v.Encoded) {
+		b.AppendBytes(v.Encoded)
+		return
+	}
+	b.AddString(string(v.Encoded))
+}
+
+func (s *span) Metric(k *xopat.MetricAttribute, v float64, t time.Time) {
+	xoputil.AtomicMaxInt64(&s.endTime, t.UnixNano())
+	l := &line{
+		builder:   s.builder(),
+		timestamp: t,
+	}
+	l.start()
+	l.enc.AddSafeKey("type")
+	l.enc.AddSafeString("metric")
+	l.enc.AddSafeKey("metric")
+	l.enc.AddString(k.Key().String())
+	l.enc.AddSafeKey("value")
+	addFloat64(&l.enc, v)
+	l.done()
+}
+
+func (s *span) Boring(bool) bool           { return false }
+func (s *span) ID() string                 { return s.logger.id.String() }
+func (s *span) GetBundle() xoptrace.Bundle { return s.bundle }
+func (s *span) GetStartTime() time.Time    { return s.startTime }
+func (s *span) GetEndTimeNano() int64      { return atomic.LoadInt64(&s.endTime) }
+func (s *span) IsRequest() bool            { return s.isRequest }
+
+func (s *span) builder() *builder {
+	return &builder{
+		span: s,
+	}
+}
+
+func (s *span) NoPrefill() xopbase.Prefilled {
+	return &prefilled{
+		builder: s.builder(),
+	}
+}
+
+func (s *span) StartPrefill() xopbase.Prefilling {
+	return &prefilling{
+		builder: s.builder(),
+	}
+}
+
+func (p *prefilling) PrefillComplete(m string) xopbase.Prefilled {
+	return &prefilled{
+		builder:    p.builder,
+		prefillMsg: m,
+	}
+}
+
+func (p *prefilled) Line(level xopnum.Level, t time.Time, frames []runtime.Frame) xopbase.Line {
+	xoputil.AtomicMaxInt64(&p.span.endTime, t.UnixNano())
+	if level >= xopnum.ErrorLevel {
+		if level >= xopnum.AlertLevel {
+			_ = atomic.AddInt32(&p.span.request.alertCount, 1)
+		} else {
+			_ = atomic.AddInt32(&p.span.request.errorCount, 1)
+		}
+	}
+	l := &line{
+		builder: &builder{
+			span:       p.span,
+			attributes: make([]attribute, len(p.attributes), len(p.attributes)+5),
+		},
+		prefillMsg: p.prefillMsg,
+		level:      level,
+		timestamp:  t,
+	}
+	copy(l.attributes, p.attributes)
+	if len(frames) > 0 {
+		l.stack = make([]string, len(frames))
+		for i, frame := range frames {
+			l.stack[i] = frame.File + ":" + strconv.Itoa(frame.Line)
+		}
+	}
+	return l
+}
+
+// start begins the line with the time and the ids
+func (l *line) start() {
+	l.enc.B = make([]byte, 0, 512)
+	l.enc.AppendByte('{') // }
+	p := l.span.logger.profile
+	if p.Time != "" {
+		l.enc.AddKey(p.Time)
+		l.enc.B = l.span.logger.timeFormatter(l.enc.B, l.timestamp)
+	}
+	l.enc.Comma()
+	l.enc.AppendBytes(l.span.ids)
+}
+
+func (l *line) done() {
+	// {
+	l.enc.AppendBytes([]byte{'}', '\n'})
+	err := l.span.request.writer.Line(l)
+	if err != nil {
+		l.span.request.errorFunc(err)
+	}
+}
+
+func (l *line) send(msg string) {
+	l.start()
+	p := l.span.logger.profile
+	if p.Level != "" {
+		l.enc.AddKey(p.Level)
+		if p.FormatLevel != nil {
+			l.enc.AddString(p.FormatLevel(l.level))
+		} else {
+			l.enc.AddSafeString(l.level.String())
+		}
+	}
+	if p.Message != "" {
+		l.enc.AddKey(p.Message)
+		l.enc.AddString(msg)
+	}
+	if len(l.stack) != 0 {
+		l.enc.AddSafeKey("stack")
+		l.enc.AppendByte('[')
+		for _, frame := range l.stack {
+			l.enc.Comma()
+			l.enc.AddString(frame)
+		}
+		l.enc.AppendByte(']')
+	}
+	if len(l.attributes) != 0 {
+		if l.span.logger.attributesObject {
+			l.enc.AddSafeKey("attributes")
+			l.enc.AppendByte('{') // }
+		}
+		for _, a := range l.attributes {
+			l.enc.AddKey(a.key)
+			l.enc.AppendBytes(a.json)
+		}
+		if l.span.logger.attributesObject {
+			// {
+			l.enc.AppendByte('}')
+		}
+	}
+	l.done()
+}
+
+func (l *line) Msg(m string) {
+	l.send(l.prefillMsg + m)
+}
+
+var templateRE = regexp.MustCompile(`\{.+?\}`)
+
+// Template lines have the template expanded in the message
+func (l *line) Template(m string) {
+	msg := templateRE.ReplaceAllStringFunc(l.prefillMsg+m, func(k string) string {
+		k = k[1 : len(k)-1]
+		for _, a := range l.attributes {
+			if a.key == k {
+				return a.text
+			}
+		}
+		return "''"
+	})
+	l.send(msg)
+}
+
+func (l *line) Model(m string, v xopbase.ModelArg) {
+	var b xoputil.JBuilder
+	addModel(&b, v)
+	l.add("model", b.B, "")
+	l.send(l.prefillMsg + m)
+}
+
+func (l *line) Link(m string, v xoptrace.Trace) {
+	l.addString("link", v.String())
+	l.send(l.prefillMsg + m)
+}
+
+// Table lines have a "table" attribute that is an array of objects
+// keyed by the column headers.
+func (l *line) Table(m string, v xopbase.SimpleTable) {
+	header := v.Header()
+	var b xoputil.JBuilder
+	b.AppendByte('[')
+	for _, row := range v.Rows() {
+		b.Comma()
+		b.AppendByte('{') // }
+		for i, cell := range row {
+			if i < len(header) {
+				b.AddKey(header[i])
+			} else {
+				b.AddKey(strconv.Itoa(i))
+			}
+			b.AddString(cell)
+		}
+		// {
+		b.AppendByte('}')
+	}
+	b.AppendByte(']')
+	l.add("table", b.B, "")
+	l.send(l.prefillMsg + m)
+}
+
+func (l *line) GetSpanID() xoptrace.HexBytes8 { return l.span.bundle.Trace.GetSpanID() }
+func (l *line) GetLevel() xopnum.Level        { return l.level }
+func (l *line) GetTime() time.Time            { return l.timestamp }
+func (l *line) AsBytes() []byte               { return l.enc.B }
+func (l *line) ReclaimMemory()                {}
+
+func (b *builder) add(k string, enc []byte, text string) {
+	b.attributes = append(b.attributes, attribute{key: k, json: enc, text: text})
+}
+
+func (b *builder) addString(k string, v string) {
+	var enc xoputil.JBuilder
+	enc.AddString(v)
+	b.add(k, enc.B, v)
+}
+
+func (b *builder) Enum(k *xopat.EnumAttribute, v xopat.Enum) {
+	b.addString(k.Key().String(), v.String())
+}
+
+func (b *builder) Any(k xopat.K, v xopbase.ModelArg) {
+	var enc xoputil.JBuilder
+	addModel(&enc, v)
+	b.add(k.String(), enc.B, string(v.Encoded))
+}
+
+func (b *builder) Bool(k xopat.K, v bool) {
+	b.add(k.String(), strconv.AppendBool(nil, v), strconv.FormatBool(v))
+}
+
+func (b *builder) Time(k xopat.K, v time.Time) {
+	b.add(k.String(), b.span.logger.timeFormatter(nil, v), v.Format(time.RFC3339Nano))
+}
+
+// Duration is encoded as nanoseconds
+func (b *builder) Duration(k xopat.K, v time.Duration) {
+	b.add(k.String(), strconv.AppendInt(nil, int64(v), 10), v.String())
+}
+
+func (b *builder) Int64(k xopat.K, v int64, _ xopbase.DataType) {
+	b.add(k.String(), strconv.AppendInt(nil, v, 10), strconv.FormatInt(v, 10))
+}
+
+func (b *builder) Uint64(k xopat.K, v uint64, _ xopbase.DataType) {
+	b.add(k.String(), strconv.AppendUint(nil, v, 10), strconv.FormatUint(v, 10))
+}
+
+func (b *builder) Float64(k xopat.K, v float64, _ xopbase.DataType) {
+	var enc xoputil.JBuilder
+	addFloat64(&enc, v)
+	b.add(k.String(), enc.B, strconv.FormatFloat(v, 'g', -1, 64))
+}
+
+func (b *builder) String(k xopat.K, v string, _ xopbase.DataType) {
+	b.addString(k.String(), v)
+}
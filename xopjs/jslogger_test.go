@@ -0,0 +1,251 @@
+package xopjs_test
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xoplog/xop-go"
+	"github.com/xoplog/xop-go/xopbase"
+	"github.com/xoplog/xop-go/xopbytes"
+	"github.com/xoplog/xop-go/xopconst"
+	"github.com/xoplog/xop-go/xopjs"
+	"github.com/xoplog/xop-go/xoptest"
+	"github.com/xoplog/xop-go/xoptest/xoptestutil"
+	"github.com/xoplog/xop-go/xoputil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decode(t *testing.T, out string) []map[string]interface{} {
+	var records []map[string]interface{}
+	for _, text := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(text), &record), text)
+		records = append(records, record)
+	}
+	return records
+}
+
+func findMsg(records []map[string]interface{}, key string, msg string) map[string]interface{} {
+	for _, record := range records {
+		if record[key] == msg {
+			return record
+		}
+	}
+	return nil
+}
+
+func findType(records []map[string]interface{}, typ string) map[string]interface{} {
+	var found map[string]interface{}
+	for _, record := range records {
+		if record["type"] == typ {
+			found = record // the last copy is the most complete
+		}
+	}
+	return found
+}
+
+type model struct {
+	Color string `json:"color"`
+}
+
+func TestNatural(t *testing.T) {
+	var buffer xoputil.Buffer
+	seed := xop.NewSeed(
+		xop.WithBase(xopjs.New(xopbytes.WriteToIOWriter(&buffer))),
+		xop.WithSettings(func(settings *xop.LogSettings) {
+			settings.SynchronousFlush(true)
+		}),
+	)
+	log := seed.Request(t.Name())
+	log.Span().String(xopconst.URL, "/hello")
+	when := time.Date(2023, 1, 2, 3, 4, 5, 6, time.UTC)
+	log.Info().
+		String(xop.Key("s"), "str").
+		Int(xop.Key("i"), 3).
+		Float64(xop.Key("f"), 1.5).
+		Bool(xop.Key("b"), true).
+		Time(xop.Key("t"), when).
+		Duration(xop.Key("d"), time.Second).
+		Any(xop.Key("m"), model{Color: "blue"}).
+		Msg("a line")
+	log.Warn().String(xop.Key("who"), "world").Template("hello {who}")
+	log.Info().Table(xopbase.TableData{
+		Columns: []string{"name", "age"},
+		Cells:   [][]string{{"alice", "30"}, {"bob", "40", "extra"}},
+	}, "people")
+	child := log.Sub().Fork("a span")
+	child.Span().Int(xopconst.HTTPStatusCode, 200)
+	child.Done()
+	log.Done()
+	t.Log("\n" + buffer.String())
+
+	records := decode(t, buffer.String())
+	line := findMsg(records, "msg", "a line")
+	require.NotNil(t, line)
+	assert.Equal(t, "info", line["lvl"])
+	assert.Equal(t, "str", line["s"])
+	assert.Equal(t, float64(3), line["i"])
+	assert.Equal(t, 1.5, line["f"])
+	assert.Equal(t, true, line["b"])
+	assert.Equal(t, "2023-01-02T03:04:05.000000006Z", line["t"])
+	assert.Equal(t, float64(time.Second), line["d"])
+	assert.Equal(t, map[string]interface{}{"color": "blue"}, line["m"])
+	assert.Len(t, line["trace.id"], 32)
+	assert.Len(t, line["span.id"], 16)
+
+	tmpl := findMsg(records, "msg", "hello world")
+	require.NotNil(t, tmpl)
+	assert.Equal(t, "warn", tmpl["lvl"])
+	assert.Equal(t, "world", tmpl["who"])
+
+	table := findMsg(records, "msg", "people")
+	require.NotNil(t, table)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "alice", "age": "30"},
+		map[string]interface{}{"name": "bob", "age": "40", "2": "extra"},
+	}, table["table"])
+
+	span := findType(records, "span")
+	require.NotNil(t, span)
+	assert.Equal(t, "a span", span["span.name"])
+	assert.Equal(t, ".A", span["span.seq"])
+	assert.Equal(t, float64(200), span["http.status_code"])
+	assert.Equal(t, line["span.id"], span["span.parent_span"])
+
+	request := findType(records, "request")
+	require.NotNil(t, request)
+	assert.Equal(t, t.Name(), request["span.name"])
+	assert.Equal(t, "/hello", request["http.url"])
+	assert.Contains(t, request, "dur")
+}
+
+func TestAttributesObject(t *testing.T) {
+	var buffer xoputil.Buffer
+	seed := xop.NewSeed(
+		xop.WithBase(xopjs.New(xopbytes.WriteToIOWriter(&buffer), xopjs.WithAttributesObject(true))),
+		xop.WithSettings(func(settings *xop.LogSettings) {
+			settings.SynchronousFlush(true)
+		}),
+	)
+	log := seed.Request(t.Name())
+	log.Span().String(xopconst.URL, "/hello")
+	log.Info().String(xop.Key("msg"), "not the message").Msg("a line")
+	log.Done()
+
+	records := decode(t, buffer.String())
+	line := findMsg(records, "msg", "a line")
+	require.NotNil(t, line)
+	assert.Equal(t, map[string]interface{}{"msg": "not the message"}, line["attributes"])
+	request := findType(records, "request")
+	require.NotNil(t, request)
+	assert.Equal(t, map[string]interface{}{"http.url": "/hello"}, request["attributes"])
+}
+
+func TestProfiles(t *testing.T) {
+	cases := []struct {
+		name    string
+		profile xopjs.Profile
+		check   func(t *testing.T, line map[string]interface{}, log *xop.Logger)
+	}{
+		{
+			name:    "ecs",
+			profile: xopjs.ECSProfile,
+			check: func(t *testing.T, line map[string]interface{}, log *xop.Logger) {
+				assert.IsType(t, float64(0), line["@timestamp"], "time as number")
+				assert.Equal(t, "warn", line["log.level"])
+				assert.Equal(t, log.Span().Trace().GetTraceID().String(), line["trace.id"])
+				assert.Equal(t, log.Span().Trace().GetSpanID().String(), line["span.id"])
+			},
+		},
+		{
+			name:    "gcp",
+			profile: xopjs.GCPProfile("my-project"),
+			check: func(t *testing.T, line map[string]interface{}, log *xop.Logger) {
+				assert.IsType(t, float64(0), line["time"], "time as number")
+				assert.Equal(t, "WARNING", line["severity"])
+				assert.Equal(t, "projects/my-project/traces/"+log.Span().Trace().GetTraceID().String(), line["logging.googleapis.com/trace"])
+				assert.Equal(t, log.Span().Trace().GetSpanID().String(), line["logging.googleapis.com/spanId"])
+			},
+		},
+		{
+			name:    "datadog",
+			profile: xopjs.DatadogProfile,
+			check: func(t *testing.T, line map[string]interface{}, log *xop.Logger) {
+				assert.IsType(t, float64(0), line["timestamp"], "time as number")
+				assert.Equal(t, "warn", line["status"])
+				traceID := binary.BigEndian.Uint64(log.Span().Trace().GetTraceID().Bytes()[8:])
+				spanID := binary.BigEndian.Uint64(log.Span().Trace().GetSpanID().Bytes())
+				assert.Equal(t, strconv.FormatUint(traceID, 10), line["dd.trace_id"])
+				assert.Equal(t, strconv.FormatUint(spanID, 10), line["dd.span_id"])
+			},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var buffer xoputil.Buffer
+			seed := xop.NewSeed(
+				xop.WithBase(xopjs.New(xopbytes.WriteToIOWriter(&buffer),
+					xopjs.WithProfile(tc.profile),
+					xopjs.WithTimeFormatter(func(b []byte, t time.Time) []byte {
+						return strconv.AppendInt(b, t.UnixMilli(), 10)
+					}),
+				)),
+				xop.WithSettings(func(settings *xop.LogSettings) {
+					settings.SynchronousFlush(true)
+				}),
+			)
+			log := seed.Request(t.Name())
+			log.Warn().Msg("a line")
+			log.Done()
+			t.Log("\n" + buffer.String())
+
+			line := findMsg(decode(t, buffer.String()), "message", "a line")
+			require.NotNil(t, line)
+			tc.check(t, line, log)
+		})
+	}
+}
+
+func TestMessageCases(t *testing.T) {
+	for _, mc := range xoptestutil.MessageCases {
+		mc := mc
+		t.Run(mc.Name, func(t *testing.T) {
+			var buffer xoputil.Buffer
+			tLog := xoptest.New(t)
+			seed := xop.NewSeed(
+				xop.WithBase(xopjs.New(xopbytes.WriteToIOWriter(&buffer))),
+				xop.WithBase(tLog),
+				xop.WithSettings(func(settings *xop.LogSettings) {
+					settings.SynchronousFlush(true)
+				}),
+			)
+			if len(mc.SeedMods) != 0 {
+				seed = seed.Copy(mc.SeedMods...)
+			}
+			log := seed.Request(t.Name())
+			mc.Do(t, log, tLog)
+			t.Log("\n" + buffer.String())
+
+			var lines int
+			spans := make(map[interface{}]struct{})
+			for _, record := range decode(t, buffer.String()) {
+				switch record["type"] {
+				case nil:
+					lines++
+				case "span", "request":
+					spans[record["span.id"]] = struct{}{}
+				}
+			}
+			recorder := tLog.Recorder()
+			assert.Equal(t, len(recorder.Lines), lines, "lines")
+			assert.Equal(t, len(recorder.Requests)+len(recorder.Spans), len(spans), "spans")
+		})
+	}
+}
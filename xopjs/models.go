@@ -0,0 +1,110 @@
+package xopjs
+
+import (
+	"time"
+
+	"github.com/xoplog/xop-go/xopbase"
+	"github.com/xoplog/xop-go/xopbase/xopbaseutil"
+	"github.com/xoplog/xop-go/xopbytes"
+	"github.com/xoplog/xop-go/xopnum"
+	"github.com/xoplog/xop-go/xoptrace"
+	"github.com/xoplog/xop-go/xoputil"
+
+	"github.com/google/uuid"
+)
+
+var _ xopbase.Logger = &Logger{}
+var _ xopbase.Request = &request{}
+var _ xopbase.Span = &span{}
+var _ xopbase.Line = &line{}
+var _ xopbase.Prefilling = &prefilling{}
+var _ xopbase.Prefilled = &prefilled{}
+var _ xopbytes.Line = &line{}
+var _ xopbytes.Span = &span{}
+var _ xopbytes.Request = &request{}
+var _ xopbytes.Buffer = record{}
+
+type Option func(*Logger)
+
+// TimeFormatter is the function signature for custom time formatters
+// if anything other than time.RFC3339Nano is desired.  The value must
+// be valid JSON and must be appended to the byte slice (which must be
+// returned).
+//
+// For example:
+//
+//	func timeFormatter(b []byte, t time.Time) []byte {
+//		return strconv.AppendInt(b, t.UnixMilli(), 10)
+//	}
+//
+// The slice may not be safely accessed outside of the duration of the
+// call.  The only acceptable operation on the slice is to append.
+type TimeFormatter func(b []byte, t time.Time) []byte
+
+type Logger struct {
+	id               uuid.UUID
+	writer           xopbytes.BytesWriter
+	timeFormatter    TimeFormatter
+	attributesObject bool
+	profile          Profile
+}
+
+type request struct {
+	span
+	sourceInfo xopbase.SourceInfo
+	writer     xopbytes.BytesRequest
+	errorFunc  func(error)
+	errorCount int32
+	alertCount int32
+	boring     int32 // 1 = boring
+}
+
+type span struct {
+	xopbaseutil.SpanMetadata
+	logger       *Logger
+	request      *request
+	bundle       xoptrace.Bundle
+	name         string
+	sequenceCode string
+	startTime    time.Time
+	isRequest    bool
+	endTime      int64
+	ids          []byte // pre-encoded trace and span ids
+}
+
+type builder struct {
+	span       *span
+	attributes []attribute
+}
+
+// attribute is a line attribute: the JSON encoding of the value
+// and, for template expansion, a text version
+type attribute struct {
+	key  string
+	json []byte
+	text string
+}
+
+type prefilling struct {
+	*builder
+}
+
+type prefilled struct {
+	*builder
+	prefillMsg string
+}
+
+type line struct {
+	*builder
+	enc        xoputil.JBuilder
+	prefillMsg string
+	level      xopnum.Level
+	timestamp  time.Time
+	stack      []string
+}
+
+// record is an encoded span or request
+type record []byte
+
+func (r record) AsBytes() []byte { return r }
+func (r record) ReclaimMemory()  {}
@@ -0,0 +1,102 @@
+package xopjs
+
+import (
+	"encoding/binary"
+	"strconv"
+
+	"github.com/xoplog/xop-go/xopnum"
+	"github.com/xoplog/xop-go/xoptrace"
+)
+
+// Profile names the fields that xopjs uses for information that
+// is not an attribute so that output can match what a log ingestion
+// service expects. Empty names omit the field.
+type Profile struct {
+	Time     string
+	Level    string
+	Message  string
+	TraceID  string
+	SpanID   string
+	Duration string // nanoseconds, on spans and requests
+
+	// FormatLevel converts xop levels to the values expected by
+	// the ingestion service. If nil, xopnum.Level.String() is used.
+	FormatLevel func(xopnum.Level) string
+	// FormatTraceID and FormatSpanID convert ids. If nil, ids
+	// are hex.
+	FormatTraceID func(xoptrace.HexBytes16) string
+	FormatSpanID  func(xoptrace.HexBytes8) string
+}
+
+// DefaultProfile uses the same names as xopjson
+var DefaultProfile = Profile{
+	Time:     "ts",
+	Level:    "lvl",
+	Message:  "msg",
+	TraceID:  "trace.id",
+	SpanID:   "span.id",
+	Duration: "dur",
+}
+
+// ECSProfile uses Elastic Common Schema field names
+var ECSProfile = Profile{
+	Time:     "@timestamp",
+	Level:    "log.level",
+	Message:  "message",
+	TraceID:  "trace.id",
+	SpanID:   "span.id",
+	Duration: "event.duration",
+}
+
+// GCPProfile uses the special fields recognized by Google Cloud
+// Logging for structured logs written to stdout. The trace field
+// must include the project that holds the trace.
+func GCPProfile(projectID string) Profile {
+	return Profile{
+		Time:        "time",
+		Level:       "severity",
+		Message:     "message",
+		TraceID:     "logging.googleapis.com/trace",
+		SpanID:      "logging.googleapis.com/spanId",
+		Duration:    "dur",
+		FormatLevel: GCPSeverity,
+		FormatTraceID: func(id xoptrace.HexBytes16) string {
+			return "projects/" + projectID + "/traces/" + id.String()
+		},
+	}
+}
+
+// GCPSeverity converts xop levels to Google Cloud Logging severities
+func GCPSeverity(level xopnum.Level) string {
+	switch {
+	case level >= xopnum.AlertLevel:
+		return "ALERT"
+	case level >= xopnum.ErrorLevel:
+		return "ERROR"
+	case level >= xopnum.WarnLevel:
+		return "WARNING"
+	case level >= xopnum.LogLevel:
+		return "INFO"
+	default:
+		return "DEBUG"
+	}
+}
+
+// DatadogProfile uses the field names that Datadog uses to connect
+// logs to traces. Datadog ids are the decimal value of the
+// low 64 bits of the id.
+var DatadogProfile = Profile{
+	Time:     "timestamp",
+	Level:    "status",
+	Message:  "message",
+	TraceID:  "dd.trace_id",
+	SpanID:   "dd.span_id",
+	Duration: "duration",
+	FormatTraceID: func(id xoptrace.HexBytes16) string {
+		b := id.Bytes()
+		return strconv.FormatUint(binary.BigEndian.Uint64(b[8:]), 10)
+	},
+	FormatSpanID: func(id xoptrace.HexBytes8) string {
+		return strconv.FormatUint(binary.BigEndian.Uint64(id.Bytes()), 10)
+	},
+}
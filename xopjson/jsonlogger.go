@@ -11,6 +11,7 @@ import (
 	"github.com/xoplog/xop-go/xopat"
 	"github.com/xoplog/xop-go/xopbase"
 	"github.com/xoplog/xop-go/xopbytes"
+	"github.com/xoplog/xop-go/xopconst"
 	"github.com/xoplog/xop-go/xopjson/xopjsonutil"
 	"github.com/xoplog/xop-go/xopnum"
 	"github.com/xoplog/xop-go/xopproto"
@@ -134,10 +135,31 @@ func (s *span) addRequestStartData(rq *builder) {
 	rq.AppendByte('"')
 }
 
+// Flush is skipped for boring requests that have no lines at the
+// Error or Alert level.
 func (r *request) Flush() {
+	if atomic.LoadInt32(&r.boring) == 1 && r.GetErrorCount() == 0 && r.GetAlertCount() == 0 {
+		return
+	}
 	r.writer.Flush()
 }
 
+// Boring is honored for requests. If the writer is buffered, boring
+// requests are not flushed. Otherwise, they're tagged with
+// xopconst.Boring.
+func (r *request) Boring(b bool) bool {
+	if r.logger.writer.Buffered() {
+		if b {
+			atomic.StoreInt32(&r.boring, 1)
+		} else {
+			atomic.StoreInt32(&r.boring, 0)
+		}
+		return true
+	}
+	r.MetadataBool(xopconst.Boring, b)
+	return true
+}
+
 func (r *request) Final() {
 	r.writer.ReclaimMemory()
 }
@@ -248,7 +270,32 @@ func (s *span) Done(t time.Time, _ bool) {
 	s.flushAttributes()
 }
 
-func (s *span) Boring(bool)                {}
+func (s *span) Metric(k *xopat.MetricAttribute, v float64, t time.Time) {
+	atomic.StoreInt64(&s.endTime, t.UnixNano())
+	s.defineAttribute(&k.Attribute)
+	l := &line{
+		builder:   s.builder(),
+		timestamp: t,
+	}
+	l.AppendBytes([]byte(`{"type":"metric","ts":`)) // }
+	l.AttributeTime(t)
+	if len(s.spanIDPrebuilt.B) != 0 {
+		l.Comma()
+		l.AppendBytes(s.spanIDPrebuilt.B)
+	}
+	l.AppendBytes([]byte(`,"metric":`))
+	l.AppendBytes(k.Key().JSON())
+	l.AppendBytes([]byte(`,"value":`))
+	l.AddFloat64(v)
+	// {
+	l.AppendBytes([]byte{'}', '\n'})
+	err := s.writer.Line(l)
+	if err != nil {
+		s.request.errorFunc(err)
+	}
+}
+
+func (s *span) Boring(bool) bool           { return false }
 func (s *span) ID() string                 { return s.logger.id.String() }
 func (s *span) GetBundle() xoptrace.Bundle { return s.bundle }
 func (s *span) GetStartTime() time.Time    { return s.startTime }
@@ -421,6 +468,43 @@ func (l *line) Model(k string, v xopbase.ModelArg) {
 	l.done()
 }
 
+func (l *line) Table(k string, v xopbase.SimpleTable) {
+	if l.attributesStarted {
+		l.AppendByte( /*{*/ '}')
+	}
+	l.AppendBytes([]byte(`,"type":"table","header":`))
+	l.addStrings(v.Header())
+	l.AppendBytes([]byte(`,"rows":[`))
+	for i, row := range v.Rows() {
+		if i != 0 {
+			l.AppendByte(',')
+		}
+		l.addStrings(row)
+	}
+	l.AppendBytes([]byte(`],"msg":"`))
+	if len(l.prefillMsgPreEncoded) != 0 {
+		l.AppendBytes(l.prefillMsgPreEncoded)
+	}
+	l.AddStringBody(k)
+	l.AppendBytes([]byte{
+		'"', // {
+		'}',
+		'\n',
+	})
+	l.done()
+}
+
+func (l *line) addStrings(a []string) {
+	l.AppendByte('[')
+	for i, s := range a {
+		if i != 0 {
+			l.AppendByte(',')
+		}
+		l.AddString(s)
+	}
+	l.AppendByte(']')
+}
+
 func (l *line) Link(k string, v xoptrace.Trace) {
 	if l.attributesStarted {
 		l.AppendByte( /*{*/ '}')
@@ -599,164 +683,63 @@ func (b *builder) addKey(k xopat.K) {
 	b.AppendByte(':')
 }
 
-func (s *span) MetadataAny(k *xopat.AnyAttribute, v xopbase.ModelArg) {
+func (s *span) defineAttribute(k *xopat.Attribute) {
 	var err error
 	switch s.logger.attributeOption {
 	case AttributesDefinedAlways:
-		err = s.logger.writer.DefineAttribute(&k.Attribute, nil)
+		err = s.logger.writer.DefineAttribute(k, nil)
 	case AttributesDefinedOnce:
 		if _, ok := s.request.attributesDefined.LoadOrStore(k.Key(), struct{}{}); !ok {
-			err = s.logger.writer.DefineAttribute(&k.Attribute, nil)
+			err = s.logger.writer.DefineAttribute(k, nil)
 		}
 	case AttributesDefinedEachRequest:
 		if _, ok := s.request.attributesDefined.LoadOrStore(k.Key(), struct{}{}); !ok {
-			err = s.logger.writer.DefineAttribute(&k.Attribute, &s.request.bundle.Trace)
+			err = s.logger.writer.DefineAttribute(k, &s.request.bundle.Trace)
 		}
 	}
 	if err != nil {
 		s.request.errorFunc(err)
 	}
+}
+
+func (s *span) MetadataAny(k *xopat.AnyAttribute, v xopbase.ModelArg) {
+	s.defineAttribute(&k.Attribute)
 	s.attributes.MetadataAny(k, v)
 }
 
 func (s *span) MetadataBool(k *xopat.BoolAttribute, v bool) {
-	var err error
-	switch s.logger.attributeOption {
-	case AttributesDefinedAlways:
-		err = s.logger.writer.DefineAttribute(&k.Attribute, nil)
-	case AttributesDefinedOnce:
-		if _, ok := s.request.attributesDefined.LoadOrStore(k.Key(), struct{}{}); !ok {
-			err = s.logger.writer.DefineAttribute(&k.Attribute, nil)
-		}
-	case AttributesDefinedEachRequest:
-		if _, ok := s.request.attributesDefined.LoadOrStore(k.Key(), struct{}{}); !ok {
-			err = s.logger.writer.DefineAttribute(&k.Attribute, &s.request.bundle.Trace)
-		}
-	}
-	if err != nil {
-		s.request.errorFunc(err)
-	}
+	s.defineAttribute(&k.Attribute)
 	s.attributes.MetadataBool(k, v)
 }
 
 func (s *span) MetadataEnum(k *xopat.EnumAttribute, v xopat.Enum) {
-	var err error
-	switch s.logger.attributeOption {
-	case AttributesDefinedAlways:
-		err = s.logger.writer.DefineAttribute(&k.Attribute, nil)
-	case AttributesDefinedOnce:
-		if _, ok := s.request.attributesDefined.LoadOrStore(k.Key(), struct{}{}); !ok {
-			err = s.logger.writer.DefineAttribute(&k.Attribute, nil)
-		}
-	case AttributesDefinedEachRequest:
-		if _, ok := s.request.attributesDefined.LoadOrStore(k.Key(), struct{}{}); !ok {
-			err = s.logger.writer.DefineAttribute(&k.Attribute, &s.request.bundle.Trace)
-		}
-	}
-	if err != nil {
-		s.request.errorFunc(err)
-	}
+	s.defineAttribute(&k.Attribute)
 	s.attributes.MetadataEnum(k, v)
 	s.logger.writer.DefineEnum(k, v)
 }
 
 func (s *span) MetadataFloat64(k *xopat.Float64Attribute, v float64) {
-	var err error
-	switch s.logger.attributeOption {
-	case AttributesDefinedAlways:
-		err = s.logger.writer.DefineAttribute(&k.Attribute, nil)
-	case AttributesDefinedOnce:
-		if _, ok := s.request.attributesDefined.LoadOrStore(k.Key(), struct{}{}); !ok {
-			err = s.logger.writer.DefineAttribute(&k.Attribute, nil)
-		}
-	case AttributesDefinedEachRequest:
-		if _, ok := s.request.attributesDefined.LoadOrStore(k.Key(), struct{}{}); !ok {
-			err = s.logger.writer.DefineAttribute(&k.Attribute, &s.request.bundle.Trace)
-		}
-	}
-	if err != nil {
-		s.request.errorFunc(err)
-	}
+	s.defineAttribute(&k.Attribute)
 	s.attributes.MetadataFloat64(k, v)
 }
 
 func (s *span) MetadataInt64(k *xopat.Int64Attribute, v int64) {
-	var err error
-	switch s.logger.attributeOption {
-	case AttributesDefinedAlways:
-		err = s.logger.writer.DefineAttribute(&k.Attribute, nil)
-	case AttributesDefinedOnce:
-		if _, ok := s.request.attributesDefined.LoadOrStore(k.Key(), struct{}{}); !ok {
-			err = s.logger.writer.DefineAttribute(&k.Attribute, nil)
-		}
-	case AttributesDefinedEachRequest:
-		if _, ok := s.request.attributesDefined.LoadOrStore(k.Key(), struct{}{}); !ok {
-			err = s.logger.writer.DefineAttribute(&k.Attribute, &s.request.bundle.Trace)
-		}
-	}
-	if err != nil {
-		s.request.errorFunc(err)
-	}
+	s.defineAttribute(&k.Attribute)
 	s.attributes.MetadataInt64(k, v)
 }
 
 func (s *span) MetadataLink(k *xopat.LinkAttribute, v xoptrace.Trace) {
-	var err error
-	switch s.logger.attributeOption {
-	case AttributesDefinedAlways:
-		err = s.logger.writer.DefineAttribute(&k.Attribute, nil)
-	case AttributesDefinedOnce:
-		if _, ok := s.request.attributesDefined.LoadOrStore(k.Key(), struct{}{}); !ok {
-			err = s.logger.writer.DefineAttribute(&k.Attribute, nil)
-		}
-	case AttributesDefinedEachRequest:
-		if _, ok := s.request.attributesDefined.LoadOrStore(k.Key(), struct{}{}); !ok {
-			err = s.logger.writer.DefineAttribute(&k.Attribute, &s.request.bundle.Trace)
-		}
-	}
-	if err != nil {
-		s.request.errorFunc(err)
-	}
+	s.defineAttribute(&k.Attribute)
 	s.attributes.MetadataLink(k, v)
 }
 
 func (s *span) MetadataString(k *xopat.StringAttribute, v string) {
-	var err error
-	switch s.logger.attributeOption {
-	case AttributesDefinedAlways:
-		err = s.logger.writer.DefineAttribute(&k.Attribute, nil)
-	case AttributesDefinedOnce:
-		if _, ok := s.request.attributesDefined.LoadOrStore(k.Key(), struct{}{}); !ok {
-			err = s.logger.writer.DefineAttribute(&k.Attribute, nil)
-		}
-	case AttributesDefinedEachRequest:
-		if _, ok := s.request.attributesDefined.LoadOrStore(k.Key(), struct{}{}); !ok {
-			err = s.logger.writer.DefineAttribute(&k.Attribute, &s.request.bundle.Trace)
-		}
-	}
-	if err != nil {
-		s.request.errorFunc(err)
-	}
+	s.defineAttribute(&k.Attribute)
 	s.attributes.MetadataString(k, v)
 }
 
 func (s *span) MetadataTime(k *xopat.TimeAttribute, v time.Time) {
-	var err error
-	switch s.logger.attributeOption {
-	case AttributesDefinedAlways:
-		err = s.logger.writer.DefineAttribute(&k.Attribute, nil)
-	case AttributesDefinedOnce:
-		if _, ok := s.request.attributesDefined.LoadOrStore(k.Key(), struct{}{}); !ok {
-			err = s.logger.writer.DefineAttribute(&k.Attribute, nil)
-		}
-	case AttributesDefinedEachRequest:
-		if _, ok := s.request.attributesDefined.LoadOrStore(k.Key(), struct{}{}); !ok {
-			err = s.logger.writer.DefineAttribute(&k.Attribute, &s.request.bundle.Trace)
-		}
-	}
-	if err != nil {
-		s.request.errorFunc(err)
-	}
+	s.defineAttribute(&k.Attribute)
 	s.attributes.MetadataTime(k, v)
 }
 
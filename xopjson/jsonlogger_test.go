@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/xoplog/xop-go"
+	"github.com/xoplog/xop-go/xopat"
 	"github.com/xoplog/xop-go/xopbytes"
 	"github.com/xoplog/xop-go/xopjson"
 	"github.com/xoplog/xop-go/xoprecorder"
@@ -172,3 +173,67 @@ func TestReplayJSON(t *testing.T) {
 		})
 	}
 }
+
+// bufferedWriter holds output for each request until Flush
+type bufferedWriter struct {
+	*xopbytes.IOWriter
+}
+
+type bufferedRequest struct {
+	w       *bufferedWriter
+	pending []byte
+}
+
+func (bw *bufferedWriter) Buffered() bool { return true }
+func (bw *bufferedWriter) Request(xopbytes.Request) xopbytes.BytesRequest {
+	return &bufferedRequest{w: bw}
+}
+func (br *bufferedRequest) Flush() error {
+	_, err := br.w.Write(br.pending)
+	br.pending = br.pending[:0]
+	return err
+}
+func (br *bufferedRequest) ReclaimMemory()                             {}
+func (br *bufferedRequest) AttributeReferenced(*xopat.Attribute) error { return nil }
+func (br *bufferedRequest) Line(line xopbytes.Line) error {
+	br.pending = append(br.pending, line.AsBytes()...)
+	return nil
+}
+func (br *bufferedRequest) Span(_ xopbytes.Span, buffer xopbytes.Buffer) error {
+	br.pending = append(br.pending, buffer.AsBytes()...)
+	return nil
+}
+
+func TestBoringJSON(t *testing.T) {
+	t.Run("buffered", func(t *testing.T) {
+		var buffer xoputil.Buffer
+		writer := &bufferedWriter{IOWriter: xopbytes.WriteToIOWriter(&buffer).(*xopbytes.IOWriter)}
+		seed := xop.NewSeed(xop.WithBase(xopjson.New(writer)))
+
+		log := seed.Request("boring")
+		log.Boring()
+		assert.True(t, log.IsBoring(), "honored")
+		log.Info().Msg("nothing to see")
+		log.Done()
+		assert.Empty(t, buffer.String(), "boring request not written")
+
+		log = seed.Request("interesting")
+		log.Boring()
+		log.Info().Msg("before error")
+		log.Error().Msg("oops")
+		log.Done()
+		assert.Contains(t, buffer.String(), "before error")
+		assert.NotContains(t, buffer.String(), `"boring"`)
+	})
+	t.Run("unbuffered", func(t *testing.T) {
+		var buffer xoputil.Buffer
+		seed := xop.NewSeed(xop.WithBase(xopjson.New(xopbytes.WriteToIOWriter(&buffer))))
+		log := seed.Request("boring")
+		log.Boring()
+		assert.True(t, log.IsBoring(), "honored")
+		log.Info().Msg("still written")
+		log.Done()
+		assert.Contains(t, buffer.String(), "still written")
+		assert.Contains(t, buffer.String(), `"attributes":{"boring":true}`) // }
+	})
+}
@@ -67,6 +67,7 @@ type Logger struct {
 type request struct {
 	idNum      int64
 	errorCount int32
+	boring     int32 // 1 = boring, only used when the writer is buffered
 	span
 	errorFunc                 func(error)
 	alertCount                int32
@@ -45,6 +45,8 @@ var knownKeys = []string{
 	"span.name",
 	"span.ver",
 	"span.parent_span",
+	"metric",
+	"value",
 }
 
 type decodeAll struct {
@@ -53,6 +55,7 @@ type decodeAll struct {
 	decodeSpanShared
 	decodeSpanExclusive
 	decodeRequestExclusive
+	decodeMetricExclusive
 }
 
 type decodeCommon struct {
@@ -80,6 +83,18 @@ type decodeLineExclusive struct {
 	Encoding  string       `json:"encoding"`  // model only
 	Encoded   interface{}  `json:"encoded"`   // model only
 	Link      string       `json:"link"`      // link only
+	Header    []string     `json:"header"`    // table only
+	Rows      [][]string   `json:"rows"`      // table only
+}
+
+type decodedMetric struct {
+	*decodeCommon
+	*decodeMetricExclusive
+}
+
+type decodeMetricExclusive struct {
+	Metric string  `json:"metric"`
+	Value  float64 `json:"value"`
 }
 
 type decodedSpanShared struct {
@@ -130,6 +145,8 @@ type baseReplay struct {
 	logger               xopbase.Logger
 	spans                map[string]xopbase.Span
 	lines                []decodedLine
+	metrics              []decodedMetric
+	spanRequestIDs       map[string]string
 	request              xopbase.Request
 	requestID            string
 	spanMap              map[string]*decodedSpan
@@ -146,6 +163,7 @@ func ReplayFromStrings(ctx context.Context, data string, logger xopbase.Logger)
 		logger:               logger,
 		spans:                make(map[string]xopbase.Span),
 		spanMap:              make(map[string]*decodedSpan),
+		spanRequestIDs:       make(map[string]string),
 		attributeRegistry:    xopat.NewRegistry(false),
 		attributeDefinitions: replayutil.NewGlobalAttributeDefinitions(),
 	}
@@ -164,11 +182,16 @@ func ReplayFromStrings(ctx context.Context, data string, logger xopbase.Logger)
 		super.unparsed = inputText
 
 		switch super.Type {
-		case "", "line", "model", "link":
+		case "", "line", "model", "link", "table":
 			x.lines = append(x.lines, decodedLine{
 				decodeCommon:        &super.decodeCommon,
 				decodeLineExclusive: &super.decodeLineExclusive,
 			})
+		case "metric":
+			x.metrics = append(x.metrics, decodedMetric{
+				decodeCommon:          &super.decodeCommon,
+				decodeMetricExclusive: &super.decodeMetricExclusive,
+			})
 		case "request":
 			requests = append(requests, &decodedRequest{
 				decodedSpanShared: decodedSpanShared{
@@ -277,6 +300,7 @@ func ReplayFromStrings(ctx context.Context, data string, logger xopbase.Logger)
 			sourceInfo)
 		requestInput.request = x.request
 		x.spans[requestInput.SpanID] = x.request
+		x.spanRequestIDs[requestInput.SpanID] = requestInput.SpanID
 		err = spanReplayData{
 			baseReplay:  x,
 			span:        x.request,
@@ -292,6 +316,10 @@ func ReplayFromStrings(ctx context.Context, data string, logger xopbase.Logger)
 	if err != nil {
 		return err
 	}
+	err = x.ReplayMetrics()
+	if err != nil {
+		return err
+	}
 	for i := len(requests) - 1; i >= 0; i-- {
 		requestInput := requests[i]
 		if requestInput.request != nil {
@@ -349,6 +377,7 @@ func (x spanReplayData) Replay(ctx context.Context) (err error) {
 			spanInput.SequenceCode,
 		)
 		x.spans[subSpanID] = span
+		x.spanRequestIDs[subSpanID] = x.requestID
 		err := spanReplayData{
 			baseReplay:  x.baseReplay,
 			span:        span,
@@ -739,6 +768,33 @@ func (x baseReplay) ReplayLines() error {
 	return nil
 }
 
+func (x baseReplay) ReplayMetrics() error {
+	for _, metricInput := range x.metrics {
+		err := x.ReplayMetric(metricInput)
+		if err != nil {
+			return errors.Wrapf(err, "in metric (%s)", metricInput.unparsed)
+		}
+	}
+	return nil
+}
+
+func (x baseReplay) ReplayMetric(metricInput decodedMetric) error {
+	span, ok := x.spans[metricInput.SpanID]
+	if !ok {
+		return errors.Errorf("unknown span (%s)", metricInput.SpanID)
+	}
+	aDef := x.attributeDefinitions.Lookup(x.spanRequestIDs[metricInput.SpanID], metricInput.Metric)
+	if aDef == nil {
+		return errors.Errorf("no attribute definition for metric (%s)", metricInput.Metric)
+	}
+	registeredAttribute, err := x.attributeRegistry.ConstructMetricAttribute(aDef.Make, xopat.AttributeType(aDef.AttributeType))
+	if err != nil {
+		return err
+	}
+	span.Metric(registeredAttribute, metricInput.Value, metricInput.Timestamp.Time)
+	return nil
+}
+
 var lineRE = regexp.MustCompile(`^(.+):(\d+)$`)
 
 func (x baseReplay) ReplayLine(lineInput decodedLine) (err error) {
@@ -814,6 +870,11 @@ func (x baseReplay) ReplayLine(lineInput decodedLine) (err error) {
 		}
 		ma.ModelType = lineInput.ModelType
 		line.Model(lineInput.Msg, ma)
+	case "table":
+		line.Table(lineInput.Msg, xopbase.TableData{
+			Columns: lineInput.Header,
+			Cells:   lineInput.Rows,
+		})
 	default:
 		return errors.Errorf("unexpected type for line: %s", lineInput.Type)
 	}
@@ -0,0 +1,30 @@
+/*
+Package xoplogfmt is a xop base logger (xopbase.Logger) that writes
+logfmt: one line of key=value pairs per log line. It is meant for log
+pipelines that parse logfmt, like Loki and Heroku-style drains. It
+is lossy and does not support replay.
+
+Every line starts with the time and the trace and span ids:
+
+	time=2023-01-02T15:04:05.999Z trace.id=<32 hex> span.id=<16 hex> ...
+
+Log lines follow with the level, the message, and the attributes.
+Templates are expanded in the message and the attributes are included
+too. Links, models, and tables are in the "link", "model", and "table"
+keys. Stack frames are in "stack".
+
+	... level=info msg="hello world" who=world
+
+Requests and spans have start and done lines identified by "type":
+request.start, request.done, span.start, and span.done. Start lines
+have the name and, for spans, the span sequence code ("span.seq")
+and the parent span id ("parent.id").  Done lines have the duration
+and the span metadata. Attributes with Multiple set are JSON arrays.
+
+Metrics have type=metric with "metric" and "value" keys.
+
+Values are quoted when they are empty or contain spaces, quotes,
+backslashes, "=", or control characters. Within quotes, backslash,
+double quote, and control characters are escaped as in JSON.
+*/
+package xoplogfmt
@@ -0,0 +1,99 @@
+package xoplogfmt
+
+import (
+	"time"
+	"unicode/utf8"
+)
+
+// TimeFormatter is the function signature for custom time formatters
+// if anything other than time.RFC3339Nano is desired.  The value must
+// be appended to the byte slice (which must be returned).  Unlike
+// xopjson.TimeFormatter, the value must not be quoted: it will be
+// quoted if needed.
+//
+// For example:
+//
+//	func timeFormatter(b []byte, t time.Time) []byte {
+//		return strconv.AppendInt(b, t.UnixMilli(), 10)
+//	}
+//
+// The slice may not be safely accessed outside of the duration of the
+// call.  The only acceptable operation on the slice is to append.
+type TimeFormatter func(b []byte, t time.Time) []byte
+
+// DefaultTimeFormatter formats times as time.RFC3339Nano
+func DefaultTimeFormatter(b []byte, t time.Time) []byte {
+	return t.AppendFormat(b, time.RFC3339Nano)
+}
+
+// TimeFormat returns a TimeFormatter that uses a time.Time
+// layout, for example time.RFC3339 or time.Kitchen.
+func TimeFormat(layout string) TimeFormatter {
+	return func(b []byte, t time.Time) []byte {
+		return t.AppendFormat(b, layout)
+	}
+}
+
+// appendKey adds " key=". Characters that are not allowed in
+// logfmt keys are replaced with underscores.
+func appendKey(b []byte, k string) []byte {
+	b = append(b, ' ')
+	if k == "" {
+		b = append(b, '_')
+	}
+	for _, r := range k {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError {
+			b = append(b, '_')
+		} else {
+			b = utf8.AppendRune(b, r)
+		}
+	}
+	return append(b, '=')
+}
+
+// needsQuotes is true for values that would not survive a
+// round trip through a logfmt parser without quotes
+func needsQuotes(v string) bool {
+	if v == "" {
+		return true
+	}
+	for i := 0; i < len(v); {
+		r, size := utf8.DecodeRuneInString(v[i:])
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || r == utf8.RuneError {
+			return true
+		}
+		i += size
+	}
+	return false
+}
+
+// appendValue adds a value, quoting and escaping it if needed
+func appendValue(b []byte, v string) []byte {
+	if !needsQuotes(v) {
+		return append(b, v...)
+	}
+	b = append(b, '"')
+	for i := 0; i < len(v); {
+		r, size := utf8.DecodeRuneInString(v[i:])
+		switch {
+		case r == '"' || r == '\\':
+			b = append(b, '\\', byte(r))
+		case r == '\n':
+			b = append(b, '\\', 'n')
+		case r == '\r':
+			b = append(b, '\\', 'r')
+		case r == '\t':
+			b = append(b, '\\', 't')
+		case r < ' ' || r == 0x7f:
+			b = append(b, '\\', 'u', '0', '0', hex[r>>4], hex[r&0xf])
+		case r == utf8.RuneError && size == 1:
+			b = append(b, '\\', 'u', 'f', 'f', 'f', 'd')
+		default:
+			b = append(b, v[i:i+size]...)
+		}
+		i += size
+	}
+	return append(b, '"')
+}
+
+const hex = "0123456789abcdef"
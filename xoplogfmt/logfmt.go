@@ -0,0 +1,371 @@
+package xoplogfmt
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xoplog/xop-go/xopat"
+	"github.com/xoplog/xop-go/xopbase"
+	"github.com/xoplog/xop-go/xopbase/xopbaseutil"
+	"github.com/xoplog/xop-go/xopconst"
+	"github.com/xoplog/xop-go/xopnum"
+	"github.com/xoplog/xop-go/xoptrace"
+	"github.com/xoplog/xop-go/xoputil"
+
+	"github.com/google/uuid"
+	"github.com/muir/list"
+)
+
+// New creates a Logger that writes to os.Stdout
+func New(opts ...Opt) *Logger {
+	log := &Logger{
+		id:            uuid.New(),
+		out:           os.Stdout,
+		timeFormatter: DefaultTimeFormatter,
+	}
+	for _, opt := range opts {
+		opt(log)
+	}
+	return log
+}
+
+// WithWriter overrides os.Stdout. Each line is written with a
+// single call to Write.
+func WithWriter(w io.Writer) Opt {
+	return func(log *Logger) {
+		log.out = w
+	}
+}
+
+// WithTimeFormatter specifies how time.Time should be formatted,
+// both for the time of each line and for time attributes. The
+// default is time.RFC3339Nano.
+func WithTimeFormatter(formatter TimeFormatter) Opt {
+	return func(log *Logger) {
+		log.timeFormatter = formatter
+	}
+}
+
+func (log *Logger) ID() string           { return log.id.String() }
+func (log *Logger) Buffered() bool       { return false }
+func (log *Logger) ReferencesKept() bool { return false }
+
+func (log *Logger) Request(_ context.Context, ts time.Time, bundle xoptrace.Bundle, name string, sourceInfo xopbase.SourceInfo) xopbase.Request {
+	r := &request{
+		span: span{
+			logger:    log,
+			bundle:    bundle,
+			name:      name,
+			startTime: ts,
+			isRequest: true,
+		},
+		errorFunc: func(error) {},
+	}
+	r.request = r
+	b := r.start(ts, "request.start")
+	if !bundle.Parent.IsZero() {
+		b = appendKey(b, "parent")
+		b = appendValue(b, bundle.Parent.String())
+	}
+	if !bundle.Baggage.IsZero() {
+		b = appendKey(b, "baggage")
+		b = appendValue(b, bundle.Baggage.String())
+	}
+	b = appendKey(b, "source")
+	b = appendValue(b, sourceInfo.Source+" "+sourceInfo.SourceVersion.String())
+	b = appendKey(b, "namespace")
+	b = appendValue(b, sourceInfo.Namespace+" "+sourceInfo.NamespaceVersion.String())
+	log.write(r, b)
+	return r
+}
+
+func (r *request) Flush()                                {}
+func (r *request) Final()                                {}
+func (r *request) SetErrorReporter(reporter func(error)) { r.errorFunc = reporter }
+
+func (log *Logger) write(r *request, b []byte) {
+	b = append(b, '\n')
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	_, err := log.out.Write(b)
+	if err != nil {
+		r.errorFunc(err)
+	}
+}
+
+// start begins the start line of a request or span
+func (s *span) start(ts time.Time, kind string) []byte {
+	b := s.prefix(make([]byte, 0, 256), ts)
+	b = appendKey(b, "type")
+	b = append(b, kind...)
+	b = appendKey(b, "name")
+	b = appendValue(b, s.name)
+	if s.sequenceCode != "" {
+		b = appendKey(b, "span.seq")
+		b = appendValue(b, s.sequenceCode)
+	}
+	return b
+}
+
+// prefix starts every line: the time followed by the trace and
+// span ids
+func (s *span) prefix(b []byte, ts time.Time) []byte {
+	b = append(b, "time="...)
+	b = appendValue(b, string(s.logger.timeFormatter(nil, ts)))
+	b = appendKey(b, "trace.id")
+	b = append(b, s.bundle.Trace.GetTraceID().String()...)
+	b = appendKey(b, "span.id")
+	b = append(b, s.bundle.Trace.GetSpanID().String()...)
+	return b
+}
+
+func (s *span) Span(_ context.Context, ts time.Time, bundle xoptrace.Bundle, name string, spanSequenceCode string) xopbase.Span {
+	n := &span{
+		logger:       s.logger,
+		request:      s.request,
+		bundle:       bundle,
+		name:         name,
+		sequenceCode: spanSequenceCode,
+		startTime:    ts,
+	}
+	b := n.start(ts, "span.start")
+	b = appendKey(b, "parent.id")
+	b = append(b, s.bundle.Trace.GetSpanID().String()...)
+	s.logger.write(s.request, b)
+	return n
+}
+
+// Done writes a done line with the span's metadata when final is
+// true. Done is called with final false before flushes: that is
+// ignored.
+func (s *span) Done(t time.Time, final bool) {
+	xoputil.AtomicMaxInt64(&s.endTime, t.UnixNano())
+	if !final {
+		return
+	}
+	kind := "span.done"
+	if s.isRequest {
+		kind = "request.done"
+	}
+	b := s.prefix(make([]byte, 0, 256), t)
+	b = appendKey(b, "type")
+	b = append(b, kind...)
+	b = appendKey(b, "name")
+	b = appendValue(b, s.name)
+	b = appendKey(b, "duration")
+	b = append(b, time.Duration(t.UnixNano()-s.startTime.UnixNano()).String()...)
+	var metadata []keyValue
+	s.SpanMetadata.Map.Range(func(k string, tracker *xopbaseutil.MetadataTracker) bool {
+		tracker.Mu.Lock()
+		defer tracker.Mu.Unlock()
+		metadata = append(metadata, keyValue{key: k, value: s.metadataValue(tracker.Value)})
+		return true
+	})
+	sort.Slice(metadata, func(i, j int) bool { return metadata[i].key < metadata[j].key })
+	for _, kv := range metadata {
+		b = appendKey(b, kv.key)
+		b = appendValue(b, kv.value)
+	}
+	s.logger.write(s.request, b)
+}
+
+// metadataValue converts span metadata to a string. Attributes
+// with Multiple set become JSON arrays of strings.
+func (s *span) metadataValue(v interface{}) string {
+	switch v := v.(type) {
+	case []interface{}:
+		values := make([]string, len(v))
+		for i, e := range v {
+			values[i] = s.metadataValue(e)
+		}
+		enc, _ := json.Marshal(values)
+		return string(enc)
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case time.Time:
+		return string(s.logger.timeFormatter(nil, v))
+	case xoptrace.Trace:
+		return v.String()
+	case xopat.Enum:
+		return v.String()
+	case xopbase.ModelArg:
+		return modelValue(v)
+	default:
+		enc, _ := json.Marshal(v)
+		return string(enc)
+	}
+}
+
+func modelValue(v xopbase.ModelArg) string {
+	v.Encode()
+	return string(v.Encoded)
+}
+
+// Boring requests are marked with xopconst.Boring on their
+// done line.
+func (s *span) Boring(b bool) bool {
+	if !s.isRequest {
+		return false
+	}
+	s.MetadataBool(xopconst.Boring, b)
+	return true
+}
+
+func (s *span) ID() string { return s.logger.id.String() }
+
+func (s *span) Metric(k *xopat.MetricAttribute, v float64, t time.Time) {
+	xoputil.AtomicMaxInt64(&s.endTime, t.UnixNano())
+	b := s.prefix(make([]byte, 0, 128), t)
+	b = appendKey(b, "type")
+	b = append(b, "metric"...)
+	b = appendKey(b, "metric")
+	b = appendValue(b, k.Key().String())
+	b = appendKey(b, "value")
+	b = strconv.AppendFloat(b, v, 'g', -1, 64)
+	s.logger.write(s.request, b)
+}
+
+func (s *span) builder() *builder {
+	return &builder{
+		span: s,
+	}
+}
+
+func (s *span) NoPrefill() xopbase.Prefilled {
+	return &prefilled{
+		builder: s.builder(),
+	}
+}
+
+func (s *span) StartPrefill() xopbase.Prefilling {
+	return &prefilling{
+		builder: s.builder(),
+	}
+}
+
+func (p *prefilling) PrefillComplete(m string) xopbase.Prefilled {
+	return &prefilled{
+		builder:    p.builder,
+		prefillMsg: m,
+	}
+}
+
+func (p *prefilled) Line(level xopnum.Level, t time.Time, frames []runtime.Frame) xopbase.Line {
+	xoputil.AtomicMaxInt64(&p.span.endTime, t.UnixNano())
+	l := &line{
+		builder: &builder{
+			span: p.span,
+			kv:   list.Copy(p.kv),
+		},
+		prefillMsg: p.prefillMsg,
+		level:      level,
+		timestamp:  t,
+	}
+	if len(frames) > 0 {
+		l.stack = make([]string, len(frames))
+		for i, frame := range frames {
+			l.stack[i] = frame.File + ":" + strconv.Itoa(frame.Line)
+		}
+	}
+	return l
+}
+
+func (l *line) Msg(m string) {
+	l.send(l.prefillMsg + m)
+}
+
+var templateRE = regexp.MustCompile(`\{.+?\}`)
+
+// Template lines have the template expanded in msg. All the
+// attributes are included, even the ones used by the template.
+func (l *line) Template(m string) {
+	msg := templateRE.ReplaceAllStringFunc(l.prefillMsg+m, func(k string) string {
+		k = k[1 : len(k)-1]
+		for _, kv := range l.kv {
+			if kv.key == k {
+				return kv.value
+			}
+		}
+		return "''"
+	})
+	l.send(msg)
+}
+
+func (l *line) Model(m string, v xopbase.ModelArg) {
+	l.add("model", modelValue(v))
+	l.send(l.prefillMsg + m)
+}
+
+func (l *line) Link(m string, v xoptrace.Trace) {
+	l.add("link", v.String())
+	l.send(l.prefillMsg + m)
+}
+
+func (l *line) Table(m string, v xopbase.SimpleTable) {
+	enc, _ := json.Marshal(map[string]interface{}{
+		"header": v.Header(),
+		"rows":   v.Rows(),
+	})
+	l.add("table", string(enc))
+	l.send(l.prefillMsg + m)
+}
+
+func (l *line) send(msg string) {
+	b := l.span.prefix(make([]byte, 0, 256), l.timestamp)
+	b = appendKey(b, "level")
+	b = append(b, l.level.String()...)
+	b = appendKey(b, "msg")
+	b = appendValue(b, msg)
+	for _, kv := range l.kv {
+		b = appendKey(b, kv.key)
+		b = appendValue(b, kv.value)
+	}
+	if len(l.stack) != 0 {
+		b = appendKey(b, "stack")
+		b = appendValue(b, strings.Join(l.stack, " "))
+	}
+	l.span.logger.write(l.span.request, b)
+}
+
+func (b *builder) add(k string, v string) {
+	b.kv = append(b.kv, keyValue{key: k, value: v})
+}
+
+func (b *builder) Enum(k *xopat.EnumAttribute, v xopat.Enum) { b.add(k.Key().String(), v.String()) }
+func (b *builder) Any(k xopat.K, v xopbase.ModelArg)         { b.add(k.String(), modelValue(v)) }
+func (b *builder) Bool(k xopat.K, v bool)                    { b.add(k.String(), strconv.FormatBool(v)) }
+func (b *builder) Duration(k xopat.K, v time.Duration)       { b.add(k.String(), v.String()) }
+
+func (b *builder) Time(k xopat.K, v time.Time) {
+	b.add(k.String(), string(b.span.logger.timeFormatter(nil, v)))
+}
+
+func (b *builder) Int64(k xopat.K, v int64, _ xopbase.DataType) {
+	b.add(k.String(), strconv.FormatInt(v, 10))
+}
+
+func (b *builder) Uint64(k xopat.K, v uint64, _ xopbase.DataType) {
+	b.add(k.String(), strconv.FormatUint(v, 10))
+}
+
+func (b *builder) Float64(k xopat.K, v float64, _ xopbase.DataType) {
+	b.add(k.String(), strconv.FormatFloat(v, 'g', -1, 64))
+}
+
+func (b *builder) String(k xopat.K, v string, _ xopbase.DataType) {
+	b.add(k.String(), v)
+}
@@ -0,0 +1,173 @@
+package xoplogfmt_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xoplog/xop-go"
+	"github.com/xoplog/xop-go/xopconst"
+	"github.com/xoplog/xop-go/xoplogfmt"
+	"github.com/xoplog/xop-go/xoptest"
+	"github.com/xoplog/xop-go/xoptest/xoptestutil"
+	"github.com/xoplog/xop-go/xoputil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pair struct {
+	key   string
+	value string
+}
+
+// parse is a strict logfmt parser: quoted values must be valid
+// Go string literals
+func parse(t *testing.T, s string) []pair {
+	var pairs []pair
+	for s != "" {
+		eq := strings.IndexByte(s, '=')
+		require.NotEqual(t, -1, eq, "missing = in %q", s)
+		key := s[:eq]
+		require.NotContains(t, key, " ", "key %q", key)
+		s = s[eq+1:]
+		var value string
+		if strings.HasPrefix(s, `"`) {
+			end := 1
+			for ; end < len(s); end++ {
+				if s[end] == '\\' {
+					end++
+					continue
+				}
+				if s[end] == '"' {
+					break
+				}
+			}
+			require.Less(t, end, len(s), "unterminated quote in %q", s)
+			var err error
+			value, err = strconv.Unquote(s[:end+1])
+			require.NoError(t, err, "unquote %s", s[:end+1])
+			s = s[end+1:]
+		} else {
+			end := strings.IndexByte(s, ' ')
+			if end == -1 {
+				end = len(s)
+			}
+			value = s[:end]
+			s = s[end:]
+		}
+		pairs = append(pairs, pair{key: key, value: value})
+		s = strings.TrimPrefix(s, " ")
+	}
+	return pairs
+}
+
+func parseLines(t *testing.T, out string) []map[string]string {
+	var lines []map[string]string
+	for _, text := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		m := make(map[string]string)
+		for _, p := range parse(t, text) {
+			m[p.key] = p.value
+		}
+		lines = append(lines, m)
+	}
+	return lines
+}
+
+func TestLogfmt(t *testing.T) {
+	var buffer xoputil.Buffer
+	seed := xop.NewSeed(
+		xop.WithBase(xoplogfmt.New(
+			xoplogfmt.WithWriter(&buffer),
+			xoplogfmt.WithTimeFormatter(xoplogfmt.TimeFormat(time.RFC1123Z)),
+		)),
+		xop.WithSettings(func(settings *xop.LogSettings) {
+			settings.SynchronousFlush(true)
+		}),
+	)
+	log := seed.Request(t.Name())
+	log.Span().String(xopconst.URL, "/some path")
+	log.Info().String(xop.Key("quote"), `say "hi"`).Int(xop.Key("count"), 3).Msg("a line\nwith = odd\tthings \\")
+	log.Warn().String(xop.Key("who"), "world").Template("hello {who}")
+	child := log.Sub().Fork("a span")
+	child.Span().Int(xopconst.HTTPStatusCode, 200)
+	child.Error().Link(log.Span().Trace(), "see")
+	child.Done()
+	log.Done()
+	t.Log("\n" + buffer.String())
+
+	lines := parseLines(t, buffer.String())
+	require.Len(t, lines, 7)
+	for _, line := range lines {
+		assert.Len(t, line["trace.id"], 32)
+		assert.Len(t, line["span.id"], 16)
+		_, err := time.Parse(time.RFC1123Z, line["time"])
+		assert.NoError(t, err, "time")
+	}
+	requestID := lines[0]["span.id"]
+
+	assert.Equal(t, "request.start", lines[0]["type"])
+	assert.Equal(t, t.Name(), lines[0]["name"])
+
+	assert.Equal(t, "info", lines[1]["level"])
+	assert.Equal(t, "a line\nwith = odd\tthings \\", lines[1]["msg"])
+	assert.Equal(t, `say "hi"`, lines[1]["quote"])
+	assert.Equal(t, "3", lines[1]["count"])
+	assert.Equal(t, requestID, lines[1]["span.id"])
+
+	assert.Equal(t, "warn", lines[2]["level"])
+	assert.Equal(t, "hello world", lines[2]["msg"])
+
+	assert.Equal(t, "span.start", lines[3]["type"])
+	assert.Equal(t, ".A", lines[3]["span.seq"])
+	assert.Equal(t, requestID, lines[3]["parent.id"])
+	childID := lines[3]["span.id"]
+	assert.NotEqual(t, requestID, childID)
+
+	assert.Equal(t, "error", lines[4]["level"])
+	assert.Equal(t, log.Span().Trace().String(), lines[4]["link"])
+	assert.Equal(t, childID, lines[4]["span.id"])
+
+	assert.Equal(t, "span.done", lines[5]["type"])
+	assert.Equal(t, "200", lines[5]["http.status_code"])
+	assert.Equal(t, childID, lines[5]["span.id"])
+
+	assert.Equal(t, "request.done", lines[6]["type"])
+	assert.Equal(t, "/some path", lines[6]["http.url"])
+	assert.NotEmpty(t, lines[6]["duration"])
+}
+
+func TestLogfmtMessageCases(t *testing.T) {
+	for _, mc := range xoptestutil.MessageCases {
+		mc := mc
+		t.Run(mc.Name, func(t *testing.T) {
+			var buffer xoputil.Buffer
+			tLog := xoptest.New(t)
+			seed := xop.NewSeed(
+				xop.WithBase(xoplogfmt.New(xoplogfmt.WithWriter(&buffer))),
+				xop.WithBase(tLog),
+				xop.WithSettings(func(settings *xop.LogSettings) {
+					settings.SynchronousFlush(true)
+				}),
+			)
+			if len(mc.SeedMods) != 0 {
+				seed = seed.Copy(mc.SeedMods...)
+			}
+			log := seed.Request(t.Name())
+			mc.Do(t, log, tLog)
+			t.Log("\n" + buffer.String())
+
+			var lines int
+			for _, line := range parseLines(t, buffer.String()) {
+				assert.NotEmpty(t, line["time"])
+				assert.NotEmpty(t, line["trace.id"])
+				assert.NotEmpty(t, line["span.id"])
+				if line["type"] == "" {
+					lines++
+				}
+			}
+			assert.Equal(t, len(tLog.Recorder().Lines), lines, "lines")
+		})
+	}
+}
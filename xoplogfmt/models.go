@@ -0,0 +1,74 @@
+package xoplogfmt
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/xoplog/xop-go/xopbase"
+	"github.com/xoplog/xop-go/xopbase/xopbaseutil"
+	"github.com/xoplog/xop-go/xopnum"
+	"github.com/xoplog/xop-go/xoptrace"
+
+	"github.com/google/uuid"
+)
+
+var _ xopbase.Logger = &Logger{}
+var _ xopbase.Request = &request{}
+var _ xopbase.Span = &span{}
+var _ xopbase.Line = &line{}
+var _ xopbase.Prefilling = &prefilling{}
+var _ xopbase.Prefilled = &prefilled{}
+
+type Opt func(*Logger)
+
+type Logger struct {
+	id            uuid.UUID
+	out           io.Writer
+	mu            sync.Mutex
+	timeFormatter TimeFormatter
+}
+
+type request struct {
+	span
+	errorFunc func(error)
+}
+
+type span struct {
+	xopbaseutil.SpanMetadata
+	logger       *Logger
+	request      *request
+	bundle       xoptrace.Bundle
+	name         string
+	sequenceCode string
+	startTime    time.Time
+	isRequest    bool
+	endTime      int64
+}
+
+type builder struct {
+	span *span
+	kv   []keyValue
+}
+
+type keyValue struct {
+	key   string
+	value string
+}
+
+type prefilling struct {
+	*builder
+}
+
+type prefilled struct {
+	*builder
+	prefillMsg string
+}
+
+type line struct {
+	*builder
+	prefillMsg string
+	level      xopnum.Level
+	timestamp  time.Time
+	stack      []string
+}
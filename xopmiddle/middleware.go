@@ -6,60 +6,130 @@ import (
 
 	"github.com/xoplog/xop-go"
 	"github.com/xoplog/xop-go/xopconst"
+	"github.com/xoplog/xop-go/xoptrace"
 )
 
 type Inbound struct {
 	requestToName func(*http.Request) string
+	routeFinder   func(*http.Request) string
+	propagator    xoptrace.Propagator
 	seed          xop.Seed
+	verbosity     []verbosityHeader
 }
 
+type verbosityHeader struct {
+	header     string
+	baggageKey string
+}
+
+// DefaultInboundPropagator reads W3C ("traceparent", "tracestate",
+// and "baggage") and B3 headers. If both are present, the combined
+// "b3" header is preferred over "traceparent" which is preferred over
+// the multiple "X-B3-*" headers.
+var DefaultInboundPropagator = xoptrace.Composite(
+	xoptrace.B3Multi,
+	xoptrace.W3C,
+	xoptrace.B3Single,
+	xoptrace.W3CBaggage,
+)
+
 func New(seed xop.Seed, requestToName func(*http.Request) string) Inbound {
 	return Inbound{
 		seed:          seed,
 		requestToName: requestToName,
+		propagator:    DefaultInboundPropagator,
 	}
 }
 
+// WithPropagator overrides DefaultInboundPropagator for
+// reading the trace from request headers.
+func (i Inbound) WithPropagator(propagator xoptrace.Propagator) Inbound {
+	i.propagator = propagator
+	return i
+}
+
+// WithRouteFinder provides a function to extract the matched
+// route (eg "/invoice/{number}") from a request. It is called after
+// the handler returns and the result, if not empty, is recorded as
+// xopconst.EndpointRoute.  Routers generally expose the matched route
+// through the request context. For example, with chi:
+//
+//	inbound.WithRouteFinder(func(r *http.Request) string {
+//		return chi.RouteContext(r.Context()).RoutePattern()
+//	})
+func (i Inbound) WithRouteFinder(routeFinder func(*http.Request) string) Inbound {
+	i.routeFinder = routeFinder
+	return i
+}
+
+// WithVerbosityHeader copies the value of an inbound HTTP header (for
+// example "Xop-Debug") into the trace baggage as baggageKey so that
+// xop.VerbosityFromBaggage, when included in the seed with xop.WithReactive,
+// will turn up the logging for the request.  Since baggage is propagated,
+// downstream services will see it too.  Baggage that already has baggageKey
+// is not modified.
+func (i Inbound) WithVerbosityHeader(header string, baggageKey string) Inbound {
+	i.verbosity = append(i.verbosity[:len(i.verbosity):len(i.verbosity)], verbosityHeader{
+		header:     header,
+		baggageKey: baggageKey,
+	})
+	return i
+}
+
+// HandlerFuncMiddleware wraps the http.ResponseWriter to record the
+// status code, response size and response time.
 func (i Inbound) HandlerFuncMiddleware() func(http.HandlerFunc) http.HandlerFunc {
 	return func(next http.HandlerFunc) http.HandlerFunc {
 		return func(w http.ResponseWriter, r *http.Request) {
 			log, ctx := i.makeChildSpan(w, r)
 			defer log.Done()
+			rw := newResponseWriter(w, log)
+			defer rw.done()
 			r = r.WithContext(log.IntoContext(ctx))
-			next(w, r)
+			defer i.recordRoute(log, r)
+			next(rw.wrapped(), r)
 		}
 	}
 }
 
+// HandlerMiddleware wraps the http.ResponseWriter to record the
+// status code, response size and response time.
 func (i Inbound) HandlerMiddleware() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			log, ctx := i.makeChildSpan(w, r)
 			defer log.Done()
+			rw := newResponseWriter(w, log)
+			defer rw.done()
 			r = r.WithContext(log.IntoContext(ctx))
-			next.ServeHTTP(w, r)
+			defer i.recordRoute(log, r)
+			next.ServeHTTP(rw.wrapped(), r)
 		})
 	}
 }
 
 // InjectorWithContext is compatible with https://github.com/muir/nject/nvelope and
 // provides a *xop.Logger to the injection chain.  It also puts the log in
-// the request context.
+// the request context. Since the http.ResponseWriter is not passed through,
+// the response status and size are not recorded.
 func (i Inbound) InjectorWithContext() func(inner func(*xop.Logger, *http.Request), w http.ResponseWriter, r *http.Request) {
 	return func(inner func(*xop.Logger, *http.Request), w http.ResponseWriter, r *http.Request) {
 		log, ctx := i.makeChildSpan(w, r)
 		defer log.Done()
 		r = r.WithContext(log.IntoContext(ctx))
+		defer i.recordRoute(log, r)
 		inner(log, r)
 	}
 }
 
-// InjectorWithContext is compatible with https://github.com/muir/nject/nvelope and
-// provides a *xop.Logger to the injection chain.
+// Injector is compatible with https://github.com/muir/nject/nvelope and
+// provides a *xop.Logger to the injection chain. Since the http.ResponseWriter
+// is not passed through, the response status and size are not recorded.
 func (i Inbound) Injector() func(inner func(*xop.Logger), w http.ResponseWriter, r *http.Request) {
 	return func(inner func(*xop.Logger), w http.ResponseWriter, r *http.Request) {
 		log, _ := i.makeChildSpan(w, r)
 		defer log.Done()
+		defer i.recordRoute(log, r)
 		inner(log)
 	}
 }
@@ -72,34 +142,20 @@ func (i Inbound) makeChildSpan(w http.ResponseWriter, r *http.Request) (*xop.Log
 
 	bundle := i.seed.Bundle()
 
-	if b3 := r.Header.Get("b3"); b3 != "" {
-		SetByB3Header(&bundle, b3)
-	} else if tp := r.Header.Get("traceparent"); tp != "" {
-		SetByParentTraceHeader(&bundle, tp)
-	} else if b3TraceID := r.Header.Get("X-B3-TraceId"); b3TraceID != "" {
-		bundle.Trace.TraceID().SetString(b3TraceID)
-		if b3Sampling := r.Header.Get("X-B3-Sampled"); b3Sampling != "" {
-			SetByB3Sampled(&bundle.Trace, b3Sampling)
-		}
-		bundle.Parent = bundle.Trace
-		if b3ParentSpanID := r.Header.Get("X-B3-ParentSpanId"); b3ParentSpanID != "" {
-			bundle.Parent.SpanID().SetString(b3ParentSpanID)
-		} else {
-			// Uh oh, no parent span id
-			bundle.Parent.SpanID().SetZero()
-		}
-		if b3SpanID := r.Header.Get("X-B3-SpanId"); b3SpanID != "" {
-			bundle.Trace.SpanID().SetString(b3SpanID)
-		} else {
-			bundle.Trace.SpanID().SetRandom()
-		}
-	}
+	i.propagator.Extract(xoptrace.HeaderCarrier(r.Header), &bundle)
 	if bundle.Trace.TraceID().IsZero() {
 		bundle.Trace.TraceID().SetRandom()
 	}
 	if bundle.Trace.SpanID().IsZero() {
 		bundle.Trace.SpanID().SetRandom()
 	}
+	for _, v := range i.verbosity {
+		if value := r.Header.Get(v.header); value != "" {
+			if _, ok := bundle.Baggage.Get(v.baggageKey); !ok {
+				_ = bundle.Baggage.Set(v.baggageKey, value)
+			}
+		}
+	}
 
 	ctx := r.Context()
 	log := i.seed.Copy(
@@ -113,3 +169,12 @@ func (i Inbound) makeChildSpan(w http.ResponseWriter, r *http.Request) (*xop.Log
 	log.Span().String(xopconst.URL, r.URL.String())
 	return log, ctx
 }
+
+func (i Inbound) recordRoute(log *xop.Logger, r *http.Request) {
+	if i.routeFinder == nil {
+		return
+	}
+	if route := i.routeFinder(r); route != "" {
+		log.Span().String(xopconst.EndpointRoute, route)
+	}
+}
@@ -7,6 +7,8 @@ import (
 
 	"github.com/xoplog/xop-go"
 	"github.com/xoplog/xop-go/xopmiddle"
+	"github.com/xoplog/xop-go/xopnum"
+	"github.com/xoplog/xop-go/xoprecorder"
 	"github.com/xoplog/xop-go/xoptest"
 
 	"github.com/stretchr/testify/assert"
@@ -22,6 +24,8 @@ var headerCases = []struct {
 	expectTrace       string // defaults to expectParentTrace
 	expectSpan        string // defaults to random
 	expectFlags       string
+	expectState       string
+	expectBaggage     string
 }{
 	{
 		name:              "traceparent set",
@@ -31,6 +35,33 @@ var headerCases = []struct {
 		expectParentFlags: "01",
 		expectFlags:       "01",
 	},
+	{
+		name: "traceparent with tracestate and baggage",
+		headers: []string{
+			"traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+			"tracestate", "rojo=00f067aa0ba902b7, congo=t61rcWkgMzE",
+			"baggage", "userId=alice,serverNode=DF%2028,isProduction=false",
+		},
+		expectParentTrace: "0af7651916cd43dd8448eb211c80319c",
+		expectParentSpan:  "b7ad6b7169203331",
+		expectParentFlags: "01",
+		expectFlags:       "01",
+		expectState:       "rojo=00f067aa0ba902b7,congo=t61rcWkgMzE",
+		expectBaggage:     "userId=alice,serverNode=DF%2028,isProduction=false",
+	},
+	{
+		name: "tracestate ignored without traceparent",
+		headers: []string{
+			"tracestate", "rojo=00f067aa0ba902b7",
+			"baggage", "tenant=acme",
+		},
+		expectParentTrace: "00000000000000000000000000000000",
+		expectParentSpan:  "0000000000000000",
+		expectParentFlags: "01",
+		expectTrace:       "random",
+		expectFlags:       "01",
+		expectBaggage:     "tenant=acme",
+	},
 	{
 		name:              "no header",
 		headers:           nil,
@@ -180,7 +211,7 @@ func TestHandlerFuncMiddleware(t *testing.T) {
 					require.NoError(t, err, "new request")
 					w := httptest.NewRecorder()
 					for i := 0; i < len(hc.headers); i += 2 {
-						r.Header.Set(hc.headers[i], hc.headers[i+1])
+						r.Header.Add(hc.headers[i], hc.headers[i+1])
 					}
 
 					im.f(t, inbound, w, r)
@@ -211,8 +242,57 @@ func TestHandlerFuncMiddleware(t *testing.T) {
 					assert.Equal(t, hc.expectFlags, request.Bundle.Trace.Flags().String(), "trace flags")
 
 					assert.Equal(t, request.Bundle.Trace.String(), w.Header().Get("traceresponse"), "trace response header")
+					assert.Equal(t, hc.expectState, request.Bundle.State.String(), "trace state")
+					assert.Equal(t, hc.expectBaggage, request.Bundle.Baggage.String(), "baggage")
 				})
 			}
 		})
 	}
 }
+
+func TestHeadSampling(t *testing.T) {
+	tLog := xoptest.New(t)
+	seed := xop.NewSeed(xop.WithBase(tLog), xop.WithSampler(xop.ProbabilitySampler(0)))
+	inbound := xopmiddle.New(seed, func(r *http.Request) string { return r.URL.String() })
+	handler := inbound.HandlerMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	r := httptest.NewRequest("GET", "/root", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	assert.Regexp(t, `-00$`, w.Header().Get("traceresponse"), "new root trace not sampled")
+
+	r = httptest.NewRequest("GET", "/child", nil)
+	r.Header.Set("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	assert.Regexp(t, `^00-0af7651916cd43dd8448eb211c80319c-[0-9a-f]{16}-01$`, w.Header().Get("traceresponse"), "inbound decision kept")
+}
+
+func TestVerbosityHeader(t *testing.T) {
+	tLog := xoptest.New(t)
+	seed := xop.NewSeed(
+		xop.WithBase(tLog),
+		xop.WithReactive(xop.VerbosityFromBaggage(xop.DefaultVerbosityKey, xopnum.TraceLevel)),
+	)
+	inbound := xopmiddle.New(seed, func(r *http.Request) string { return r.URL.String() }).
+		WithVerbosityHeader("Xop-Debug", xop.DefaultVerbosityKey)
+	var baggage string
+	handler := inbound.HandlerMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log := xop.FromContextOrPanic(r.Context())
+		log.Trace().String("path", r.URL.Path).Msg("trace")
+		log.Sub().Fork("child").Trace().String("path", r.URL.Path).Msg("child trace")
+		baggage = log.Span().Bundle().Baggage.String()
+	}))
+
+	r := httptest.NewRequest("GET", "/quiet", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	assert.Equal(t, 0, tLog.Recorder().CountLines(xoprecorder.MessageEquals("trace")), "quiet")
+	assert.Equal(t, "", baggage)
+
+	r = httptest.NewRequest("GET", "/loud", nil)
+	r.Header.Set("Xop-Debug", "1")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	assert.Equal(t, 1, tLog.Recorder().CountLines(xoprecorder.MessageEquals("trace")), "loud")
+	assert.Equal(t, 1, tLog.Recorder().CountLines(xoprecorder.MessageEquals("child trace")), "loud child")
+	assert.Equal(t, "xop-debug=1", baggage, "propagated in baggage")
+}
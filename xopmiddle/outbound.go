@@ -0,0 +1,192 @@
+package xopmiddle
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/xoplog/xop-go"
+	"github.com/xoplog/xop-go/xopconst"
+	"github.com/xoplog/xop-go/xoptrace"
+)
+
+// Outbound is an http.RoundTripper that creates a child span for each
+// outgoing request. The parent logger is found in the request context.
+// If there is no logger in the context, the request is passed through
+// unmodified.
+//
+// Trace headers ("traceparent", "tracestate", and "baggage") are added
+// to the outgoing request.  If the logger's Config().UseB3 is true, then
+// B3 headers are added too. Use WithPropagator to send other formats.
+//
+// The URL is recorded without its query string or fragment since
+// those often carry tokens. Passwords in the URL are redacted.
+//
+// The child span is Done when the response body is closed or
+// fully read.
+type Outbound struct {
+	next          http.RoundTripper
+	requestToName func(*http.Request) string
+	propagator    xoptrace.Propagator
+	step          bool
+}
+
+var _ http.RoundTripper = Outbound{}
+
+// NewOutbound wraps an http.RoundTripper. If next is nil,
+// http.DefaultTransport is used. If requestToName is nil or returns "",
+// the span name will be the method and URL.
+func NewOutbound(next http.RoundTripper, requestToName func(*http.Request) string) Outbound {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return Outbound{
+		next:          next,
+		requestToName: requestToName,
+	}
+}
+
+// WithStep controls if the child span is created with Step() or
+// Fork(). The default is Fork() since outgoing requests are often
+// made in parallel.
+func (o Outbound) WithStep(step bool) Outbound {
+	o.step = step
+	return o
+}
+
+// WithPropagator overrides the headers that are added to
+// outgoing requests. The logger's Config().UseB3 is ignored
+// when a propagator is provided.
+func (o Outbound) WithPropagator(propagator xoptrace.Propagator) Outbound {
+	o.propagator = propagator
+	return o
+}
+
+func (o Outbound) RoundTrip(r *http.Request) (*http.Response, error) {
+	parent, ok := xop.FromContext(r.Context())
+	if !ok {
+		return o.next.RoundTrip(r)
+	}
+	var name string
+	if o.requestToName != nil {
+		name = o.requestToName(r)
+	}
+	if name == "" {
+		name = outboundURL(r.URL)
+	}
+	name = r.Method + " " + name
+
+	var log *xop.Logger
+	if o.step {
+		log = parent.Sub().Step(name)
+	} else {
+		log = parent.Sub().Fork(name)
+	}
+	log.Span().Enum(xopconst.SpanKind, xopconst.SpanKindClient)
+	log.Span().EmbeddedEnum(xopconst.SpanTypeHTTPClientRequest)
+	log.Span().String(xopconst.HTTPMethod, r.Method)
+	log.Span().String(xopconst.URL, outboundURL(r.URL))
+
+	// RoundTrippers must not modify the request
+	r = r.Clone(log.IntoContext(r.Context()))
+	if o.propagator != nil {
+		SetOutboundHeadersWith(r.Header, log.Span().Bundle(), o.propagator)
+	} else {
+		SetOutboundHeaders(r.Header, log.Span().Bundle(), log.Config().UseB3)
+	}
+
+	start := time.Now()
+	resp, err := o.next.RoundTrip(r)
+	if err != nil {
+		log.Error().Error("error", err).Msg("outbound request failed")
+		log.Done()
+		return resp, err
+	}
+	log.Span().Duration(xopconst.HTTPResponseTime, time.Since(start))
+	log.Span().Int(xopconst.HTTPStatusCode, resp.StatusCode)
+	if tr := resp.Header.Get("traceresponse"); tr != "" {
+		if trace, ok := xoptrace.TraceFromString(tr); ok {
+			log.Span().Link(xopconst.RemoteTrace, trace)
+		}
+	}
+	if resp.Body == nil || resp.Body == http.NoBody {
+		log.Span().Int64(xopconst.HTTPResponseContentLength, 0)
+		log.Done()
+		return resp, nil
+	}
+	resp.Body = &outboundBody{
+		ReadCloser: resp.Body,
+		log:        log,
+	}
+	return resp, nil
+}
+
+// outboundURL returns the URL to record for an outgoing request
+func outboundURL(u *url.URL) string {
+	c := *u
+	c.ForceQuery = false
+	c.RawQuery = ""
+	c.Fragment = ""
+	c.RawFragment = ""
+	return c.Redacted()
+}
+
+// SetOutboundHeaders sets the "traceparent", "tracestate", and "baggage"
+// headers from a bundle.  The bundle should be for the span that is making
+// the request.  If useB3 is true, the single "b3" header and the multiple
+// "X-B3-*" headers are set as well.
+func SetOutboundHeaders(h http.Header, bundle xoptrace.Bundle, useB3 bool) {
+	if useB3 {
+		SetOutboundHeadersWith(h, bundle, outboundB3Propagator)
+	} else {
+		SetOutboundHeadersWith(h, bundle, xoptrace.Default)
+	}
+}
+
+// SetOutboundHeadersWith sets headers from a bundle using a
+// propagator. Any headers that the propagator uses that are
+// already present are removed first.
+func SetOutboundHeadersWith(h http.Header, bundle xoptrace.Bundle, propagator xoptrace.Propagator) {
+	for _, field := range propagator.Fields() {
+		h.Del(field)
+	}
+	propagator.Inject(xoptrace.HeaderCarrier(h), bundle)
+}
+
+var outboundB3Propagator = xoptrace.Composite(
+	xoptrace.W3C,
+	xoptrace.W3CBaggage,
+	xoptrace.B3Single,
+	xoptrace.B3Multi,
+)
+
+type outboundBody struct {
+	io.ReadCloser
+	log  *xop.Logger
+	size int64
+	once sync.Once
+}
+
+func (b *outboundBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.size += int64(n)
+	if err == io.EOF {
+		b.done()
+	}
+	return n, err
+}
+
+func (b *outboundBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.done()
+	return err
+}
+
+func (b *outboundBody) done() {
+	b.once.Do(func() {
+		b.log.Span().Int64(xopconst.HTTPResponseContentLength, b.size)
+		b.log.Done()
+	})
+}
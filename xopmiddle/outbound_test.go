@@ -0,0 +1,173 @@
+package xopmiddle_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/xoplog/xop-go"
+	"github.com/xoplog/xop-go/xopmiddle"
+	"github.com/xoplog/xop-go/xoptest"
+	"github.com/xoplog/xop-go/xoptrace"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutbound(t *testing.T) {
+	for _, useB3 := range []bool{false, true} {
+		useB3 := useB3
+		name := "traceparent"
+		if useB3 {
+			name = "b3"
+		}
+		t.Run(name, func(t *testing.T) {
+			var got http.Header
+			remote := xoptrace.NewTrace()
+			remote.TraceID().SetRandom()
+			remote.SpanID().SetRandom()
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Clone()
+				w.Header().Set("traceresponse", remote.String())
+				w.WriteHeader(http.StatusTeapot)
+				_, _ = w.Write([]byte("short and stout"))
+			}))
+			defer server.Close()
+
+			tLog := xoptest.New(t)
+			var state xoptrace.State
+			state.SetString("congo=t61rcWkgMzE")
+			log := xop.NewSeed(
+				xop.WithBase(tLog),
+				xop.WithB3(useB3),
+				xop.WithTraceState(state),
+			).Request(t.Name())
+			require.NoError(t, log.Span().SetBaggage("tenant", "acme"))
+
+			client := &http.Client{
+				Transport: xopmiddle.NewOutbound(nil, nil),
+			}
+			req, err := http.NewRequestWithContext(log.IntoContext(context.Background()), "GET", server.URL+"/pot", nil)
+			require.NoError(t, err)
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+			assert.Equal(t, "short and stout", string(body))
+			log.Done()
+
+			require.Len(t, tLog.Recorder().Spans, 1, "one client span")
+			span := tLog.Recorder().Spans[0]
+			assert.Equal(t, "GET "+server.URL+"/pot", span.Name)
+			assert.Equal(t, span.Bundle.Trace.String(), got.Get("traceparent"), "traceparent")
+			assert.Equal(t, "congo=t61rcWkgMzE", got.Get("tracestate"), "tracestate")
+			assert.Equal(t, "tenant=acme", got.Get("baggage"), "baggage")
+			if useB3 {
+				assert.Equal(t, span.Bundle.Trace.TraceID().String()+"-"+span.Bundle.Trace.SpanID().String()+"-1-"+span.Bundle.Parent.SpanID().String(), got.Get("b3"), "b3")
+				assert.Equal(t, span.Bundle.Trace.SpanID().String(), got.Get("X-B3-SpanId"), "X-B3-SpanId")
+			} else {
+				assert.Empty(t, got.Get("b3"), "b3")
+			}
+
+			for k, want := range map[string]interface{}{
+				"http.status_code":             int64(http.StatusTeapot),
+				"http.method":                  "GET",
+				"http.url":                     server.URL + "/pot",
+				"http.response_content_length": int64(len("short and stout")),
+			} {
+				md := span.SpanMetadata.Get(k)
+				if assert.NotNilf(t, md, "has %s", k) {
+					assert.Equal(t, want, md.Value, k)
+				}
+			}
+			if md := span.SpanMetadata.Get("http.remote_trace"); assert.NotNil(t, md, "remote trace") {
+				assert.Equal(t, remote.String(), md.Value.(xoptrace.Trace).String())
+			}
+			assert.NotNil(t, span.SpanMetadata.Get("http.response_time"), "response time")
+		})
+	}
+}
+
+func TestOutboundNoLogger(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+	}))
+	defer server.Close()
+	client := &http.Client{
+		Transport: xopmiddle.NewOutbound(nil, nil),
+	}
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Empty(t, got.Get("traceparent"))
+}
+
+func TestOutboundURLRedacted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	tLog := xoptest.New(t)
+	log := tLog.Logger()
+	client := &http.Client{
+		Transport: xopmiddle.NewOutbound(nil, nil),
+	}
+	u, err := url.Parse(server.URL + "/pot?token=sekrit#frag")
+	require.NoError(t, err)
+	u.User = url.UserPassword("user", "pw")
+	req, err := http.NewRequestWithContext(log.IntoContext(context.Background()), "GET", u.String(), nil)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	log.Done()
+
+	require.Len(t, tLog.Recorder().Spans, 1)
+	span := tLog.Recorder().Spans[0]
+	want := "http://user:xxxxx@" + u.Host + "/pot"
+	assert.Equal(t, "GET "+want, span.Name)
+	if md := span.SpanMetadata.Get("http.url"); assert.NotNil(t, md) {
+		assert.Equal(t, want, md.Value)
+	}
+}
+
+func TestInboundBaggagePropagatesToOutbound(t *testing.T) {
+	var got http.Header
+	downstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+	}))
+	defer downstream.Close()
+
+	tLog := xoptest.New(t)
+	inbound := xopmiddle.New(xop.NewSeed(xop.WithBase(tLog)), func(r *http.Request) string { return r.URL.Path })
+	client := &http.Client{Transport: xopmiddle.NewOutbound(nil, nil)}
+	server := httptest.NewServer(inbound.HandlerFuncMiddleware()(func(w http.ResponseWriter, r *http.Request) {
+		log := xop.FromContextOrPanic(r.Context())
+		assert.Equal(t, "congo=t61rcWkgMzE", log.Span().TraceState().String(), "inbound trace state")
+		v, _ := log.Span().TraceBaggage().Get("tenant")
+		assert.Equal(t, "acme", v, "inbound baggage")
+		req, err := http.NewRequestWithContext(r.Context(), "GET", downstream.URL, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequest("GET", server.URL+"/upstream", nil)
+	require.NoError(t, err)
+	req.Header.Set("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	req.Header.Set("tracestate", "congo=t61rcWkgMzE")
+	req.Header.Set("baggage", "tenant=acme;ttl=5")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	require.NotNil(t, got, "downstream called")
+	assert.Equal(t, "congo=t61rcWkgMzE", got.Get("tracestate"), "outbound trace state")
+	assert.Equal(t, "tenant=acme;ttl=5", got.Get("baggage"), "outbound baggage")
+	assert.Contains(t, got.Get("traceparent"), "0af7651916cd43dd8448eb211c80319c", "same trace")
+}
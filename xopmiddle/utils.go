@@ -1,8 +1,6 @@
 package xopmiddle
 
 import (
-	"regexp"
-
 	"github.com/xoplog/xop-go/xoptrace"
 )
 
@@ -13,45 +11,42 @@ import (
 // "traceparent" header
 // Example: 00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01
 func SetByParentTraceHeader(b *xoptrace.Bundle, h string) {
-	parent, ok := xoptrace.TraceFromString(h)
-	if !ok {
+	if !xoptrace.W3C.Extract(xoptrace.MapCarrier{"traceparent": h}, b) {
 		b.Trace = xoptrace.NewTrace()
 		b.Trace.TraceID().SetRandom()
 		b.Trace.SpanID().SetRandom()
-		return
 	}
-	b.Parent = parent
-	b.Trace = parent
-	b.Trace.SpanID().SetRandom()
 }
 
-var b3RE = regexp.MustCompile(`^([a-fA-F0-9]{32})-([a-fA-F0-9]{16})-(0|1|true|false|d)(?:-([a-fA-F0-9]{16}))?$`)
+// SetByTraceStateHeader sets bundle.State from a "tracestate" header.
+// Invalid entries are dropped. If the header is sent as multiple
+// fields, they should be joined with "," first.
+//
+// Example: rojo=00f067aa0ba902b7,congo=t61rcWkgMzE
+func SetByTraceStateHeader(b *xoptrace.Bundle, h string) {
+	b.State.SetString(h)
+}
 
+// SetByBaggageHeader sets bundle.Baggage from a "baggage" header.
+// Invalid members are dropped. If the header is sent as multiple
+// fields, they should be joined with "," first.
+//
+// Example: userId=alice,serverNode=DF%2028,isProduction=false
+func SetByBaggageHeader(b *xoptrace.Bundle, h string) {
+	b.Baggage.SetString(h)
+}
+
+// SetByB3Header processes a combined "b3" header. It is a
+// wrapper around xoptrace.B3Single.
+//
+// A header that is only a sampling decision ("0", "1", "true",
+// "false", or "d") sets the flags of b.Trace. The trace and span
+// ids are not modified so it does not start a new trace.
+//
 // https://github.com/openzipkin/b3-propagation
 // b3: traceid-spanid-sampled-parentspanid
 func SetByB3Header(b *xoptrace.Bundle, h string) {
-	switch h {
-	case "0", "1", "true", "false", "d":
-		b.Parent = xoptrace.NewTrace()
-		SetByB3Sampled(&b.Parent, h)
-		b.Trace = b.Parent
-		b.Trace.TraceID().SetRandom()
-		b.Trace.SpanID().SetRandom()
-		return
-	}
-	m := b3RE.FindStringSubmatch(h)
-	if m == nil {
-		return
-	}
-	b.Parent.TraceID().SetString(m[1])
-	SetByB3Sampled(&b.Parent, m[3])
-	if m[4] == "" {
-		b.Parent.SpanID().SetZero()
-	} else {
-		b.Parent.SpanID().SetString(m[4])
-	}
-	b.Trace = b.Parent
-	b.Trace.SpanID().SetString(m[2])
+	xoptrace.B3Single.Extract(xoptrace.MapCarrier{"b3": h}, b)
 }
 
 // SetByB3Sampled process the "X-B3-Sampled" header or
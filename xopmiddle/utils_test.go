@@ -0,0 +1,27 @@
+package xopmiddle_test
+
+import (
+	"testing"
+
+	"github.com/xoplog/xop-go/xopmiddle"
+	"github.com/xoplog/xop-go/xoptrace"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetByB3Header(t *testing.T) {
+	bundle := xoptrace.NewBundle()
+	xopmiddle.SetByB3Header(&bundle, "0af7651916cd43dd8448eb211c80319c-91e961630d5d22de-1-b7ad6b7169203331")
+	assert.Equal(t, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", bundle.Parent.String(), "parent")
+	assert.Equal(t, "00-0af7651916cd43dd8448eb211c80319c-91e961630d5d22de-01", bundle.Trace.String(), "trace")
+
+	xopmiddle.SetByB3Header(&bundle, "0")
+	assert.Equal(t, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", bundle.Parent.String(), "sampling only keeps parent")
+	assert.Equal(t, "00-0af7651916cd43dd8448eb211c80319c-91e961630d5d22de-00", bundle.Trace.String(), "sampling only sets flags")
+
+	empty := xoptrace.NewBundle()
+	xopmiddle.SetByB3Header(&empty, "1")
+	assert.True(t, empty.Trace.IsZero(), "sampling only does not start a trace")
+	assert.True(t, empty.Parent.IsZero(), "no parent")
+	assert.Equal(t, "01", empty.Trace.GetFlags().String(), "flags")
+}
@@ -0,0 +1,162 @@
+package xopmiddle
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/xoplog/xop-go"
+	"github.com/xoplog/xop-go/xopconst"
+)
+
+// responseWriter wraps an http.ResponseWriter to track the status
+// code and the size of the response.
+type responseWriter struct {
+	http.ResponseWriter
+	log         *xop.Logger
+	start       time.Time
+	status      int
+	size        int64
+	wroteHeader bool
+}
+
+func newResponseWriter(w http.ResponseWriter, log *xop.Logger) *responseWriter {
+	return &responseWriter{
+		ResponseWriter: w,
+		log:            log,
+		start:          time.Now(),
+	}
+}
+
+func (rw *responseWriter) WriteHeader(status int) {
+	if !rw.wroteHeader && !informational(status) {
+		rw.wroteHeader = true
+		rw.status = status
+		rw.log.Span().Duration(xopconst.HTTPResponseTime, time.Since(rw.start))
+	}
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
+	n, err := rw.ResponseWriter.Write(b)
+	rw.size += int64(n)
+	return n, err
+}
+
+func (rw *responseWriter) flush() {
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
+	rw.ResponseWriter.(http.Flusher).Flush()
+}
+
+func (rw *responseWriter) hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return rw.ResponseWriter.(http.Hijacker).Hijack()
+}
+
+func (rw *responseWriter) readFrom(r io.Reader) (int64, error) {
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
+	n, err := rw.ResponseWriter.(io.ReaderFrom).ReadFrom(r)
+	rw.size += n
+	return n, err
+}
+
+// informational is true for 1xx status codes that are followed
+// by another response. 101 Switching Protocols is final.
+func informational(status int) bool {
+	return status >= 100 && status <= 199 && status != http.StatusSwitchingProtocols
+}
+
+// done records the status code and response size. Server errors
+// are logged at the Error level which also marks the request as
+// not boring. It must be invoked directly with defer: a panic in
+// the handler is recovered, recorded as a 500 if nothing was
+// written, and then re-thrown.
+func (rw *responseWriter) done() {
+	r := recover()
+	if !rw.wroteHeader {
+		if r != nil {
+			rw.status = http.StatusInternalServerError
+		} else {
+			// handler wrote nothing: net/http will send a 200
+			rw.status = http.StatusOK
+		}
+		rw.log.Span().Duration(xopconst.HTTPResponseTime, time.Since(rw.start))
+	}
+	rw.log.Span().Int(xopconst.HTTPStatusCode, rw.status)
+	rw.log.Span().Int64(xopconst.HTTPResponseContentLength, rw.size)
+	if rw.status >= 500 {
+		rw.log.Error().Int("status", rw.status).Msg("server error response")
+	}
+	if r != nil {
+		panic(r)
+	}
+}
+
+type flusher struct{ *responseWriter }
+type hijacker struct{ *responseWriter }
+type readerFrom struct{ *responseWriter }
+
+func (f flusher) Flush()                                        { f.flush() }
+func (h hijacker) Hijack() (net.Conn, *bufio.ReadWriter, error) { return h.hijack() }
+func (r readerFrom) ReadFrom(src io.Reader) (int64, error)      { return r.readFrom(src) }
+
+// wrapped returns an http.ResponseWriter that implements
+// http.Flusher, http.Hijacker, and io.ReaderFrom if and only if
+// the underlying http.ResponseWriter does.
+func (rw *responseWriter) wrapped() http.ResponseWriter {
+	_, isFlusher := rw.ResponseWriter.(http.Flusher)
+	_, isHijacker := rw.ResponseWriter.(http.Hijacker)
+	_, isReaderFrom := rw.ResponseWriter.(io.ReaderFrom)
+	switch {
+	case isFlusher && isHijacker && isReaderFrom:
+		return struct {
+			*responseWriter
+			flusher
+			hijacker
+			readerFrom
+		}{rw, flusher{rw}, hijacker{rw}, readerFrom{rw}}
+	case isFlusher && isHijacker:
+		return struct {
+			*responseWriter
+			flusher
+			hijacker
+		}{rw, flusher{rw}, hijacker{rw}}
+	case isFlusher && isReaderFrom:
+		return struct {
+			*responseWriter
+			flusher
+			readerFrom
+		}{rw, flusher{rw}, readerFrom{rw}}
+	case isHijacker && isReaderFrom:
+		return struct {
+			*responseWriter
+			hijacker
+			readerFrom
+		}{rw, hijacker{rw}, readerFrom{rw}}
+	case isFlusher:
+		return struct {
+			*responseWriter
+			flusher
+		}{rw, flusher{rw}}
+	case isHijacker:
+		return struct {
+			*responseWriter
+			hijacker
+		}{rw, hijacker{rw}}
+	case isReaderFrom:
+		return struct {
+			*responseWriter
+			readerFrom
+		}{rw, readerFrom{rw}}
+	default:
+		return rw
+	}
+}
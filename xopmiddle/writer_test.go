@@ -0,0 +1,155 @@
+package xopmiddle_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/xoplog/xop-go"
+	"github.com/xoplog/xop-go/xopmiddle"
+	"github.com/xoplog/xop-go/xopnum"
+	"github.com/xoplog/xop-go/xoprecorder"
+	"github.com/xoplog/xop-go/xoptest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInboundResponseRecording(t *testing.T) {
+	cases := []struct {
+		name        string
+		handler     http.HandlerFunc
+		status      int64
+		size        int64
+		expectError bool
+		noResponse  bool
+	}{
+		{
+			name: "implicit ok",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("hello"))
+			},
+			status: 200,
+			size:   5,
+		},
+		{
+			name:    "nothing written",
+			handler: func(w http.ResponseWriter, r *http.Request) {},
+			status:  200,
+			size:    0,
+		},
+		{
+			name: "readFrom",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusCreated)
+				_, _ = io.Copy(w, strings.NewReader("created!"))
+			},
+			status: 201,
+			size:   8,
+		},
+		{
+			name: "server error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "oops", http.StatusBadGateway)
+			},
+			status:      502,
+			size:        5,
+			expectError: true,
+		},
+		{
+			name: "informational",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Link", "</style.css>; rel=preload")
+				w.WriteHeader(http.StatusEarlyHints)
+				w.WriteHeader(http.StatusAccepted)
+				_, _ = w.Write([]byte("later"))
+			},
+			status: 202,
+			size:   5,
+		},
+		{
+			name: "panic",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				panic(http.ErrAbortHandler)
+			},
+			status:      500,
+			size:        0,
+			expectError: true,
+			noResponse:  true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			tLog := xoptest.New(t)
+			inbound := xopmiddle.New(xop.NewSeed(xop.WithBase(tLog)), func(r *http.Request) string {
+				return "/items"
+			}).WithRouteFinder(func(r *http.Request) string {
+				return "/items/{id}"
+			})
+			server := httptest.NewServer(inbound.HandlerMiddleware()(tc.handler))
+			defer server.Close()
+			resp, err := http.Get(server.URL + "/items/3")
+			if tc.noResponse {
+				require.Error(t, err, "connection closed")
+			} else {
+				require.NoError(t, err)
+				_, _ = io.Copy(io.Discard, resp.Body)
+				_ = resp.Body.Close()
+				assert.Equal(t, int(tc.status), resp.StatusCode)
+			}
+
+			require.Equal(t, 1, len(tLog.Recorder().Requests), "one request")
+			request := tLog.Recorder().Requests[0]
+			for k, want := range map[string]interface{}{
+				"http.status_code":             tc.status,
+				"http.response_content_length": tc.size,
+				"http.route":                   "/items/{id}",
+			} {
+				md := request.SpanMetadata.Get(k)
+				if assert.NotNilf(t, md, "has %s", k) {
+					assert.Equal(t, want, md.Value, k)
+				}
+			}
+			assert.NotNil(t, request.SpanMetadata.Get("http.response_time"), "response time")
+			errorCount := tLog.Recorder().CountLines(xoprecorder.MessageEquals("server error response"))
+			if tc.expectError {
+				assert.Equal(t, 1, errorCount, "error logged")
+				assert.Equal(t, xopnum.ErrorLevel, tLog.Recorder().Lines[0].Level, "error level")
+			} else {
+				assert.Equal(t, 0, errorCount, "no error logged")
+			}
+		})
+	}
+}
+
+func TestInboundWriterInterfaces(t *testing.T) {
+	tLog := xoptest.New(t)
+	inbound := xopmiddle.New(xop.NewSeed(xop.WithBase(tLog)), func(r *http.Request) string {
+		return "/"
+	})
+	var isFlusher, isHijacker, isReaderFrom bool
+	handler := inbound.HandlerFuncMiddleware()(func(w http.ResponseWriter, r *http.Request) {
+		_, isFlusher = w.(http.Flusher)
+		_, isHijacker = w.(http.Hijacker)
+		_, isReaderFrom = w.(io.ReaderFrom)
+	})
+
+	// httptest.ResponseRecorder is a Flusher but not a Hijacker or ReaderFrom
+	handler(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	assert.True(t, isFlusher, "recorder flusher")
+	assert.False(t, isHijacker, "recorder hijacker")
+	assert.False(t, isReaderFrom, "recorder readerFrom")
+
+	// the net/http server's ResponseWriter is all three
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.True(t, isFlusher, "server flusher")
+	assert.True(t, isHijacker, "server hijacker")
+	assert.True(t, isReaderFrom, "server readerFrom")
+}
@@ -0,0 +1,44 @@
+package xopotlp
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/xoplog/xop-go/xoputil/poster"
+
+	"github.com/google/uuid"
+)
+
+// NewCollector creates a Logger that POSTs OTLP/JSON to a collector.
+// The endpoint is the base URL of the collector's OTLP/HTTP receiver,
+// for example "http://localhost:4318": spans are sent to
+// endpoint+"/v1/traces" and log records to endpoint+"/v1/logs".
+//
+// Requests are sent synchronously from Flush. Failures are reported
+// to the error reporter (see xop.Config.ErrorReporter).
+func NewCollector(endpoint string, opts ...Option) *Logger {
+	log := &Logger{
+		id:       uuid.New(),
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		poster:   poster.New(),
+	}
+	for _, f := range opts {
+		f(log)
+	}
+	return log
+}
+
+// WithHTTPClient overrides http.DefaultClient for NewCollector
+func WithHTTPClient(client *http.Client) Option {
+	return func(log *Logger) {
+		log.poster.Client = client
+	}
+}
+
+// WithHeader adds a header, for example for authentication, to
+// the requests sent by NewCollector
+func WithHeader(key, value string) Option {
+	return func(log *Logger) {
+		log.poster.AddHeader(key, value)
+	}
+}
@@ -0,0 +1,53 @@
+/*
+Package xopotlp is a xop base logger (xopbase.Logger) that produces
+OTLP/JSON without depending upon the OpenTelemetry SDK.
+
+Each Flush of a request produces an ExportTraceServiceRequest that
+holds the request and the spans that have changed since the
+previous Flush.  Lines are span events unless WithLogRecords is used,
+in which case they are sent as log records in a separate
+ExportLogsServiceRequest.  Metrics are always span events.
+
+Use New to write newline-delimited export requests to an
+xopbytes.BytesWriter (the same format as the OpenTelemetry Collector's
+file exporter) or NewCollector to POST them to a collector's
+/v1/traces and /v1/logs endpoints.
+
+# Mapping
+
+The request's source becomes the resource (service.name and
+service.version) and the namespace becomes the instrumentation scope.
+
+Span metadata becomes span attributes. Attributes with Multiple set
+are arrays. Links and times are strings. Enums are {"v": name, "i": value}
+kvlists and models are {"modelType", "encoding", "encoded"} kvlists.
+The span.kind attribute sets the OTLP span kind.
+
+Information that OTLP has no place for is kept in attributes
+prefixed with "xop.":
+
+	xop.type        request, span, template, model, link, table, metric
+	xop.parent      the request's parent trace (traceparent format)
+	xop.baggage     the request's baggage
+	xop.attributes  attribute definitions, on requests
+	xop.seq         span sequence code
+	xop.level       log level of span events
+	xop.stack       stack frames, as "file:line"
+	xop.types       line attribute data types not implied by their OTLP value
+	xop.model       the model of Model lines
+	xop.link        the trace of Link lines
+	xop.table       the header and rows of Table lines
+	xop.value       the value of metrics
+
+Log records use the xop log level as the severity number: xop
+levels fall within the matching OTLP severity ranges.
+
+Spans that are flushed more than once are sent more than once. Each
+copy has the current metadata but only the events since the previous
+copy.
+
+# Replay
+
+Replay reads what New writes and is a full-fidelity round trip.
+*/
+package xopotlp
@@ -0,0 +1,93 @@
+package xopotlp
+
+import (
+	"sync"
+	"time"
+
+	"github.com/xoplog/xop-go/xopbase"
+	"github.com/xoplog/xop-go/xopbase/xopbaseutil"
+	"github.com/xoplog/xop-go/xopbytes"
+	"github.com/xoplog/xop-go/xopnum"
+	"github.com/xoplog/xop-go/xoptrace"
+	"github.com/xoplog/xop-go/xoputil/poster"
+
+	"github.com/google/uuid"
+)
+
+var _ xopbase.Logger = &Logger{}
+var _ xopbase.Request = &request{}
+var _ xopbase.Span = &span{}
+var _ xopbase.Line = &line{}
+var _ xopbase.Prefilling = &prefilling{}
+var _ xopbase.Prefilled = &prefilled{}
+var _ xopbytes.Request = &request{}
+var _ xopbytes.Buffer = document{}
+
+type Option func(*Logger)
+
+type Logger struct {
+	id         uuid.UUID
+	writer     xopbytes.BytesWriter // nil when sending to a collector
+	endpoint   string
+	poster     poster.Poster
+	logRecords bool
+}
+
+type request struct {
+	span
+	sourceInfo  xopbase.SourceInfo
+	bytes       xopbytes.BytesRequest
+	errorFunc   func(error)
+	errorCount  int32
+	alertCount  int32
+	boring      int32 // 1 = boring
+	mu          sync.Mutex
+	spans       []*span
+	lines       []*line // lines and metrics since the last Flush
+	definitions map[string]string
+}
+
+type span struct {
+	xopbaseutil.SpanMetadata
+	logger       *Logger
+	request      *request
+	bundle       xoptrace.Bundle
+	name         string
+	sequenceCode string
+	startTime    time.Time
+	isRequest    bool
+	endTime      int64
+	done         int32 // 1 once Done has been called
+	dirty        int32 // 1 if Done has been called since the last Flush
+}
+
+type builder struct {
+	span       *span
+	attributes []otlpKeyValue
+	types      []otlpKeyValue // data types that are not implied by the OTLP value
+}
+
+type prefilling struct {
+	*builder
+}
+
+type prefilled struct {
+	*builder
+	prefillMsg string
+}
+
+type line struct {
+	*builder
+	prefillMsg string
+	level      xopnum.Level
+	timestamp  time.Time
+	stack      []string
+	kind       string // "" for Msg, otherwise the xop.type attribute
+	message    string
+}
+
+// document is an encoded OTLP export request
+type document []byte
+
+func (d document) AsBytes() []byte { return d }
+func (d document) ReclaimMemory()  {}
@@ -0,0 +1,246 @@
+package xopotlp
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// The types in this file are the subset of the OTLP protobuf messages
+// that xopotlp uses, in their OTLP/JSON encoding: lowerCamelCase field
+// names, hex trace and span ids, and 64-bit integers as strings.
+//
+// https://opentelemetry.io/docs/specs/otlp/#json-protobuf-encoding
+
+// otlpDocument is either an ExportTraceServiceRequest or an
+// ExportLogsServiceRequest. Replay accepts both.
+type otlpDocument struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans,omitempty"`
+	ResourceLogs  []otlpResourceLogs  `json:"resourceLogs,omitempty"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResourceLogs struct {
+	Resource  otlpResource    `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope       `json:"scope"`
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	TraceState        string         `json:"traceState,omitempty"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Flags             uint32         `json:"flags,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano otlpUint64     `json:"startTimeUnixNano"`
+	EndTimeUnixNano   otlpUint64     `json:"endTimeUnixNano,omitempty"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Events            []otlpEvent    `json:"events,omitempty"`
+}
+
+type otlpEvent struct {
+	TimeUnixNano otlpUint64     `json:"timeUnixNano"`
+	Name         string         `json:"name"`
+	Attributes   []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano   otlpUint64     `json:"timeUnixNano"`
+	SeverityNumber int            `json:"severityNumber,omitempty"`
+	SeverityText   string         `json:"severityText,omitempty"`
+	Body           *otlpAnyValue  `json:"body,omitempty"`
+	Attributes     []otlpKeyValue `json:"attributes,omitempty"`
+	Flags          uint32         `json:"flags,omitempty"`
+	TraceID        string         `json:"traceId,omitempty"`
+	SpanID         string         `json:"spanId,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string          `json:"stringValue,omitempty"`
+	BoolValue   *bool            `json:"boolValue,omitempty"`
+	IntValue    *otlpInt64       `json:"intValue,omitempty"`
+	DoubleValue *otlpDouble      `json:"doubleValue,omitempty"`
+	ArrayValue  *otlpArrayValue  `json:"arrayValue,omitempty"`
+	KvlistValue *otlpKvlistValue `json:"kvlistValue,omitempty"`
+	BytesValue  []byte           `json:"bytesValue,omitempty"`
+}
+
+type otlpArrayValue struct {
+	Values []otlpAnyValue `json:"values"`
+}
+
+type otlpKvlistValue struct {
+	Values []otlpKeyValue `json:"values"`
+}
+
+// otlpUint64 is a fixed64: encoded as a string, decoded from a
+// string or a number.
+type otlpUint64 uint64
+
+// otlpInt64 is an int64: encoded as a string, decoded from a
+// string or a number.
+type otlpInt64 int64
+
+// otlpDouble encodes NaN and the infinities the way protojson does.
+type otlpDouble float64
+
+func (u otlpUint64) MarshalJSON() ([]byte, error) {
+	return strconv.AppendQuote(nil, strconv.FormatUint(uint64(u), 10)), nil
+}
+
+func (u *otlpUint64) UnmarshalJSON(b []byte) error {
+	v, err := strconv.ParseUint(unquote(b), 10, 64)
+	if err != nil {
+		return errors.Wrapf(err, "decode uint64 (%s)", string(b))
+	}
+	*u = otlpUint64(v)
+	return nil
+}
+
+func (i otlpInt64) MarshalJSON() ([]byte, error) {
+	return strconv.AppendQuote(nil, strconv.FormatInt(int64(i), 10)), nil
+}
+
+func (i *otlpInt64) UnmarshalJSON(b []byte) error {
+	v, err := strconv.ParseInt(unquote(b), 10, 64)
+	if err != nil {
+		return errors.Wrapf(err, "decode int64 (%s)", string(b))
+	}
+	*i = otlpInt64(v)
+	return nil
+}
+
+func (d otlpDouble) MarshalJSON() ([]byte, error) {
+	f := float64(d)
+	switch {
+	case math.IsNaN(f):
+		return []byte(`"NaN"`), nil
+	case math.IsInf(f, 1):
+		return []byte(`"Infinity"`), nil
+	case math.IsInf(f, -1):
+		return []byte(`"-Infinity"`), nil
+	}
+	return json.Marshal(f)
+}
+
+func (d *otlpDouble) UnmarshalJSON(b []byte) error {
+	switch s := unquote(b); s {
+	case "NaN":
+		*d = otlpDouble(math.NaN())
+	case "Infinity":
+		*d = otlpDouble(math.Inf(1))
+	case "-Infinity":
+		*d = otlpDouble(math.Inf(-1))
+	default:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return errors.Wrapf(err, "decode double (%s)", string(b))
+		}
+		*d = otlpDouble(f)
+	}
+	return nil
+}
+
+func unquote(b []byte) string {
+	if len(b) >= 2 && b[0] == '"' && b[len(b)-1] == '"' {
+		return string(b[1 : len(b)-1])
+	}
+	return string(b)
+}
+
+func stringValue(s string) otlpAnyValue { return otlpAnyValue{StringValue: &s} }
+func boolValue(b bool) otlpAnyValue     { return otlpAnyValue{BoolValue: &b} }
+func intValue(i int64) otlpAnyValue {
+	v := otlpInt64(i)
+	return otlpAnyValue{IntValue: &v}
+}
+
+func doubleValue(f float64) otlpAnyValue {
+	v := otlpDouble(f)
+	return otlpAnyValue{DoubleValue: &v}
+}
+
+func arrayValue(values []otlpAnyValue) otlpAnyValue {
+	return otlpAnyValue{ArrayValue: &otlpArrayValue{Values: values}}
+}
+
+func kvlistValue(values []otlpKeyValue) otlpAnyValue {
+	return otlpAnyValue{KvlistValue: &otlpKvlistValue{Values: values}}
+}
+
+func stringsValue(s []string) otlpAnyValue {
+	values := make([]otlpAnyValue, len(s))
+	for i, v := range s {
+		values[i] = stringValue(v)
+	}
+	return arrayValue(values)
+}
+
+func (v otlpAnyValue) asString() (string, bool) {
+	if v.StringValue == nil {
+		return "", false
+	}
+	return *v.StringValue, true
+}
+
+func (v otlpAnyValue) asStrings() ([]string, bool) {
+	if v.ArrayValue == nil {
+		return nil, false
+	}
+	s := make([]string, len(v.ArrayValue.Values))
+	for i, e := range v.ArrayValue.Values {
+		var ok bool
+		s[i], ok = e.asString()
+		if !ok {
+			return nil, false
+		}
+	}
+	return s, true
+}
+
+// find returns the value for a key
+func find(kvs []otlpKeyValue, key string) (otlpAnyValue, bool) {
+	for _, kv := range kvs {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return otlpAnyValue{}, false
+}
+
+func findString(kvs []otlpKeyValue, key string) string {
+	v, _ := find(kvs, key)
+	s, _ := v.asString()
+	return s
+}
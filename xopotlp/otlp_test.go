@@ -0,0 +1,158 @@
+package xopotlp_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/xoplog/xop-go"
+	"github.com/xoplog/xop-go/xopbytes"
+	"github.com/xoplog/xop-go/xopotlp"
+	"github.com/xoplog/xop-go/xoptest"
+	"github.com/xoplog/xop-go/xoptest/xoptestutil"
+	"github.com/xoplog/xop-go/xoputil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplayOTLP(t *testing.T) {
+	for _, logRecords := range []bool{false, true} {
+		logRecords := logRecords
+		name := "events"
+		if logRecords {
+			name = "records"
+		}
+		t.Run(name, func(t *testing.T) {
+			for _, mc := range xoptestutil.MessageCases {
+				mc := mc
+				t.Run(mc.Name, func(t *testing.T) {
+					var buffer xoputil.Buffer
+					tLog := xoptest.New(t)
+					seed := xop.NewSeed(
+						xop.WithBase(xopotlp.New(xopbytes.WriteToIOWriter(&buffer), xopotlp.WithLogRecords(logRecords))),
+						xop.WithBase(tLog),
+						xop.WithSettings(func(settings *xop.LogSettings) {
+							settings.SynchronousFlush(true)
+						}),
+					)
+					if len(mc.SeedMods) != 0 {
+						t.Logf("Applying %d extra seed mods", len(mc.SeedMods))
+						seed = seed.Copy(mc.SeedMods...)
+					}
+					log := seed.Request(t.Name())
+					mc.Do(t, log, tLog)
+					t.Log("\n", buffer.String())
+
+					t.Log("replay from generated OTLP")
+					rLog := xoptest.New(t)
+					err := xopotlp.Replay(context.Background(), &buffer, rLog)
+					require.NoError(t, err, "replay")
+
+					t.Log("verify replay equals original")
+					xoptestutil.VerifyTestReplay(t, tLog, rLog)
+				})
+			}
+		})
+	}
+}
+
+type collector struct {
+	mu     sync.Mutex
+	paths  []string
+	bodies [][]byte
+	auth   []string
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.paths = append(c.paths, r.URL.Path)
+	c.bodies = append(c.bodies, body)
+	c.auth = append(c.auth, r.Header.Get("Authorization"))
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestCollector(t *testing.T) {
+	c := &collector{}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	tLog := xoptest.New(t)
+	seed := xop.NewSeed(
+		xop.WithBase(xopotlp.NewCollector(server.URL,
+			xopotlp.WithLogRecords(true),
+			xopotlp.WithHeader("Authorization", "Bearer token"))),
+		xop.WithBase(tLog),
+		xop.WithSettings(func(settings *xop.LogSettings) {
+			settings.SynchronousFlush(true)
+		}),
+	)
+	log := seed.Request(t.Name())
+	log.Info().String(xop.Key("color"), "blue").Msg("a line")
+	log.Sub().Fork("a span").Done()
+	log.Done()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	require.Equal(t, []string{"/v1/traces", "/v1/logs"}, c.paths)
+	assert.Equal(t, []string{"Bearer token", "Bearer token"}, c.auth)
+	var traces map[string]interface{}
+	require.NoError(t, json.Unmarshal(c.bodies[0], &traces))
+	assert.Contains(t, traces, "resourceSpans")
+
+	rLog := xoptest.New(t)
+	err := xopotlp.Replay(context.Background(), bytes.NewReader(bytes.Join(c.bodies, []byte("\n"))), rLog)
+	require.NoError(t, err, "replay")
+	xoptestutil.VerifyTestReplay(t, tLog, rLog)
+}
+
+func TestCollectorError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	var reported []error
+	seed := xop.NewSeed(
+		xop.WithBase(xopotlp.NewCollector(server.URL)),
+		xop.WithConfigChanges(func(config *xop.Config) {
+			config.ErrorReporter = func(err error) { reported = append(reported, err) }
+		}),
+		xop.WithSettings(func(settings *xop.LogSettings) {
+			settings.SynchronousFlush(true)
+		}),
+	)
+	log := seed.Request(t.Name())
+	log.Info().Msg("lost")
+	log.Done()
+	require.NotEmpty(t, reported)
+	assert.Contains(t, reported[0].Error(), "503")
+}
+
+func TestBoringOTLP(t *testing.T) {
+	var buffer xoputil.Buffer
+	seed := xop.NewSeed(
+		xop.WithBase(xopotlp.New(xopbytes.WriteToIOWriter(&buffer))),
+		xop.WithSettings(func(settings *xop.LogSettings) {
+			settings.SynchronousFlush(true)
+		}),
+	)
+	log := seed.Request("boring")
+	log.Boring()
+	log.Info().Msg("nothing to see")
+	log.Done()
+	assert.Empty(t, buffer.String(), "boring request not flushed")
+
+	log = seed.Request("interesting")
+	log.Boring()
+	log.Error().Msg("oops")
+	log.Done()
+	assert.Contains(t, buffer.String(), `"name":"oops"`)
+}
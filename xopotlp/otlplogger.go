@@ -0,0 +1,555 @@
+package xopotlp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/xoplog/xop-go/xopat"
+	"github.com/xoplog/xop-go/xopbase"
+	"github.com/xoplog/xop-go/xopbase/xopbaseutil"
+	"github.com/xoplog/xop-go/xopbytes"
+	"github.com/xoplog/xop-go/xopconst"
+	"github.com/xoplog/xop-go/xopnum"
+	"github.com/xoplog/xop-go/xopproto"
+	"github.com/xoplog/xop-go/xoptrace"
+	"github.com/xoplog/xop-go/xoputil"
+
+	"github.com/google/uuid"
+	"github.com/muir/list"
+)
+
+// Attribute keys used for xop information that OTLP has no place for
+const (
+	typeKey       = "xop.type"
+	parentKey     = "xop.parent"
+	baggageKey    = "xop.baggage"
+	definitionKey = "xop.attributes"
+	sequenceKey   = "xop.seq"
+	levelKey      = "xop.level"
+	stackKey      = "xop.stack"
+	typesKey      = "xop.types"
+	modelKey      = "xop.model"
+	linkKey       = "xop.link"
+	tableKey      = "xop.table"
+	valueKey      = "xop.value"
+)
+
+const (
+	spanKindInternal = 1
+	tracesPath       = "/v1/traces"
+	logsPath         = "/v1/logs"
+)
+
+// New creates a Logger that writes newline-delimited OTLP/JSON export
+// requests to a BytesWriter.
+func New(w xopbytes.BytesWriter, opts ...Option) *Logger {
+	log := &Logger{
+		id:     uuid.New(),
+		writer: w,
+	}
+	for _, f := range opts {
+		f(log)
+	}
+	return log
+}
+
+// WithLogRecords sends lines as log records in ExportLogsServiceRequests
+// instead of as span events. Metrics remain span events.
+func WithLogRecords(b bool) Option {
+	return func(log *Logger) {
+		log.logRecords = b
+	}
+}
+
+func (logger *Logger) ID() string           { return logger.id.String() }
+func (logger *Logger) Buffered() bool       { return true }
+func (logger *Logger) ReferencesKept() bool { return false }
+
+func (logger *Logger) Request(_ context.Context, ts time.Time, bundle xoptrace.Bundle, name string, sourceInfo xopbase.SourceInfo) xopbase.Request {
+	r := &request{
+		span: span{
+			logger:    logger,
+			bundle:    bundle,
+			name:      name,
+			startTime: ts,
+			isRequest: true,
+		},
+		sourceInfo:  sourceInfo,
+		errorFunc:   func(error) {},
+		definitions: make(map[string]string),
+	}
+	r.request = r
+	r.spans = []*span{&r.span}
+	if logger.writer != nil {
+		r.bytes = logger.writer.Request(r)
+	}
+	return r
+}
+
+// Flush sends the spans that have changed since the previous Flush
+// along with their new lines.  A span that is flushed more than once
+// is sent more than once: the last copy is the most complete.
+//
+// Flush is skipped for boring requests that have no lines at the
+// Error or Alert level.
+func (r *request) Flush() {
+	if atomic.LoadInt32(&r.boring) == 1 && r.GetErrorCount() == 0 && r.GetAlertCount() == 0 {
+		return
+	}
+	var lines []*line
+	var spans []*span
+	func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		lines = r.lines
+		r.lines = nil
+		spans = list.Copy(r.spans)
+	}()
+	events := make(map[*span][]otlpEvent)
+	var records []otlpLogRecord
+	for _, line := range lines {
+		if r.logger.logRecords && line.kind != "metric" {
+			records = append(records, line.record())
+		} else {
+			events[line.span] = append(events[line.span], line.event())
+		}
+	}
+	otlpSpans := make([]otlpSpan, 1, len(spans))
+	for _, s := range spans[1:] {
+		e, ok := events[s]
+		if atomic.SwapInt32(&s.dirty, 0) == 0 && !ok {
+			continue
+		}
+		otlpSpans = append(otlpSpans, s.otlp(e))
+	}
+	// the request is last so that its attribute definitions
+	// include the metadata of the other spans
+	atomic.StoreInt32(&r.dirty, 0)
+	otlpSpans[0] = r.span.otlp(events[&r.span])
+
+	resource := otlpResource{
+		Attributes: []otlpKeyValue{
+			{Key: "service.name", Value: stringValue(r.sourceInfo.Source)},
+			{Key: "service.version", Value: stringValue(r.sourceInfo.SourceVersion.String())},
+		},
+	}
+	scope := otlpScope{
+		Name:    r.sourceInfo.Namespace,
+		Version: r.sourceInfo.NamespaceVersion.String(),
+	}
+	r.export(tracesPath, otlpDocument{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource:   resource,
+			ScopeSpans: []otlpScopeSpans{{Scope: scope, Spans: otlpSpans}},
+		}},
+	})
+	if len(records) != 0 {
+		r.export(logsPath, otlpDocument{
+			ResourceLogs: []otlpResourceLogs{{
+				Resource:  resource,
+				ScopeLogs: []otlpScopeLogs{{Scope: scope, LogRecords: records}},
+			}},
+		})
+	}
+}
+
+func (r *request) export(path string, doc otlpDocument) {
+	enc, err := json.Marshal(doc)
+	if err != nil {
+		r.errorFunc(err)
+		return
+	}
+	if r.bytes == nil {
+		err = r.logger.poster.Post(r.logger.endpoint+path, enc)
+	} else {
+		err = r.bytes.Span(r, document(append(enc, '\n')))
+		if err == nil {
+			err = r.bytes.Flush()
+		}
+	}
+	if err != nil {
+		r.errorFunc(err)
+	}
+}
+
+func (r *request) Final() {
+	if r.bytes != nil {
+		r.bytes.ReclaimMemory()
+	}
+}
+
+// Boring is honored for requests. Lines are kept until Flush, so
+// boring requests are simply not flushed.
+func (r *request) Boring(b bool) bool {
+	if b {
+		atomic.StoreInt32(&r.boring, 1)
+	} else {
+		atomic.StoreInt32(&r.boring, 0)
+	}
+	return true
+}
+
+func (r *request) SetErrorReporter(reporter func(error)) { r.errorFunc = reporter }
+func (r *request) GetErrorCount() int32                  { return atomic.LoadInt32(&r.errorCount) }
+func (r *request) GetAlertCount() int32                  { return atomic.LoadInt32(&r.alertCount) }
+
+func (r *request) define(k xopat.AttributeInterface) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.definitions[k.Key().String()]; !ok {
+		r.definitions[k.Key().String()] = strings.TrimSuffix(k.DefinitionJSONString(), "\n")
+	}
+}
+
+func (s *span) Span(_ context.Context, ts time.Time, bundle xoptrace.Bundle, name string, spanSequenceCode string) xopbase.Span {
+	n := &span{
+		logger:       s.logger,
+		request:      s.request,
+		bundle:       bundle,
+		name:         name,
+		sequenceCode: spanSequenceCode,
+		startTime:    ts,
+	}
+	s.request.mu.Lock()
+	defer s.request.mu.Unlock()
+	s.request.spans = append(s.request.spans, n)
+	return n
+}
+
+func (s *span) Done(t time.Time, _ bool) {
+	xoputil.AtomicMaxInt64(&s.endTime, t.UnixNano())
+	atomic.StoreInt32(&s.done, 1)
+	atomic.StoreInt32(&s.dirty, 1)
+}
+
+func (s *span) Metric(k *xopat.MetricAttribute, v float64, t time.Time) {
+	xoputil.AtomicMaxInt64(&s.endTime, t.UnixNano())
+	s.request.define(k)
+	s.request.add(&line{
+		builder: &builder{
+			span:       s,
+			attributes: []otlpKeyValue{{Key: valueKey, Value: doubleValue(v)}},
+		},
+		timestamp: t,
+		kind:      "metric",
+		message:   k.Key().String(),
+	})
+}
+
+func (s *span) Boring(bool) bool           { return false }
+func (s *span) ID() string                 { return s.logger.id.String() }
+func (s *span) GetBundle() xoptrace.Bundle { return s.bundle }
+func (s *span) GetStartTime() time.Time    { return s.startTime }
+func (s *span) GetEndTimeNano() int64      { return atomic.LoadInt64(&s.endTime) }
+func (s *span) IsRequest() bool            { return s.isRequest }
+
+// otlp must be called from Flush
+func (s *span) otlp(events []otlpEvent) otlpSpan {
+	o := otlpSpan{
+		TraceID:           s.bundle.Trace.GetTraceID().String(),
+		SpanID:            s.bundle.Trace.GetSpanID().String(),
+		Flags:             uint32(s.bundle.Trace.GetFlags().Bytes()[0]),
+		Name:              s.name,
+		Kind:              spanKindInternal,
+		StartTimeUnixNano: otlpUint64(s.startTime.UnixNano()),
+		Events:            events,
+	}
+	if !s.bundle.Parent.GetSpanID().IsZero() {
+		o.ParentSpanID = s.bundle.Parent.GetSpanID().String()
+	}
+	if atomic.LoadInt32(&s.done) == 1 {
+		o.EndTimeUnixNano = otlpUint64(atomic.LoadInt64(&s.endTime))
+	}
+	if s.isRequest {
+		o.TraceState = s.bundle.State.String()
+		o.Attributes = append(o.Attributes,
+			otlpKeyValue{Key: typeKey, Value: stringValue("request")},
+			otlpKeyValue{Key: parentKey, Value: stringValue(s.bundle.Parent.String())})
+		if !s.bundle.Baggage.IsZero() {
+			o.Attributes = append(o.Attributes, otlpKeyValue{Key: baggageKey, Value: stringValue(s.bundle.Baggage.String())})
+		}
+	} else {
+		o.Attributes = append(o.Attributes, otlpKeyValue{Key: typeKey, Value: stringValue("span")})
+		if s.sequenceCode != "" {
+			o.Attributes = append(o.Attributes, otlpKeyValue{Key: sequenceKey, Value: stringValue(s.sequenceCode)})
+		}
+	}
+	s.SpanMetadata.Map.Range(func(k string, tracker *xopbaseutil.MetadataTracker) bool {
+		tracker.Mu.Lock()
+		defer tracker.Mu.Unlock()
+		s.request.define(tracker.Attribute)
+		o.Attributes = append(o.Attributes, otlpKeyValue{Key: k, Value: metadataValue(tracker.Value)})
+		if k == xopconst.SpanKind.Key().String() {
+			if kind, ok := tracker.Value.(xopat.Enum); ok {
+				o.Kind = int(kind.Int64())
+			}
+		}
+		return true
+	})
+	if s.isRequest {
+		s.request.mu.Lock()
+		defer s.request.mu.Unlock()
+		keys := make([]string, 0, len(s.request.definitions))
+		for k := range s.request.definitions {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		definitions := make([]otlpKeyValue, len(keys))
+		for i, k := range keys {
+			definitions[i] = otlpKeyValue{Key: k, Value: stringValue(s.request.definitions[k])}
+		}
+		o.Attributes = append(o.Attributes, otlpKeyValue{Key: definitionKey, Value: kvlistValue(definitions)})
+	}
+	return o
+}
+
+func metadataValue(v interface{}) otlpAnyValue {
+	switch v := v.(type) {
+	case []interface{}:
+		values := make([]otlpAnyValue, len(v))
+		for i, e := range v {
+			values[i] = metadataValue(e)
+		}
+		return arrayValue(values)
+	case string:
+		return stringValue(v)
+	case bool:
+		return boolValue(v)
+	case int64:
+		return intValue(v)
+	case float64:
+		return doubleValue(v)
+	case time.Time:
+		return timeValue(v)
+	case xoptrace.Trace:
+		return stringValue(v.String())
+	case xopat.Enum:
+		return enumValue(v)
+	case xopbase.ModelArg:
+		return modelValue(v)
+	default:
+		return stringValue(fmt.Sprint(v))
+	}
+}
+
+func timeValue(t time.Time) otlpAnyValue {
+	return stringValue(t.Format(time.RFC3339Nano))
+}
+
+func enumValue(v xopat.Enum) otlpAnyValue {
+	return kvlistValue([]otlpKeyValue{
+		{Key: "v", Value: stringValue(v.String())},
+		{Key: "i", Value: intValue(v.Int64())},
+	})
+}
+
+func modelValue(v xopbase.ModelArg) otlpAnyValue {
+	v.Encode()
+	encoded := otlpAnyValue{BytesValue: v.Encoded}
+	if utf8.Valid(v.Encoded) {
+		encoded = stringValue(string(v.Encoded))
+	}
+	return kvlistValue([]otlpKeyValue{
+		{Key: "modelType", Value: stringValue(v.ModelType)},
+		{Key: "encoding", Value: stringValue(v.Encoding.String())},
+		{Key: "encoded", Value: encoded},
+	})
+}
+
+func (r *request) add(l *line) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lines = append(r.lines, l)
+}
+
+func (s *span) builder() *builder {
+	return &builder{
+		span: s,
+	}
+}
+
+func (s *span) NoPrefill() xopbase.Prefilled {
+	return &prefilled{
+		builder: s.builder(),
+	}
+}
+
+func (s *span) StartPrefill() xopbase.Prefilling {
+	return &prefilling{
+		builder: s.builder(),
+	}
+}
+
+func (p *prefilling) PrefillComplete(m string) xopbase.Prefilled {
+	return &prefilled{
+		builder:    p.builder,
+		prefillMsg: m,
+	}
+}
+
+func (p *prefilled) Line(level xopnum.Level, t time.Time, frames []runtime.Frame) xopbase.Line {
+	xoputil.AtomicMaxInt64(&p.span.endTime, t.UnixNano())
+	if level >= xopnum.ErrorLevel {
+		if level >= xopnum.AlertLevel {
+			_ = atomic.AddInt32(&p.span.request.alertCount, 1)
+		} else {
+			_ = atomic.AddInt32(&p.span.request.errorCount, 1)
+		}
+	}
+	l := &line{
+		builder: &builder{
+			span:       p.span,
+			attributes: list.Copy(p.attributes),
+			types:      list.Copy(p.types),
+		},
+		prefillMsg: p.prefillMsg,
+		level:      level,
+		timestamp:  t,
+	}
+	if len(frames) > 0 {
+		l.stack = make([]string, len(frames))
+		for i, frame := range frames {
+			l.stack[i] = frame.File + ":" + strconv.Itoa(frame.Line)
+		}
+	}
+	return l
+}
+
+func (l *line) Msg(m string) {
+	l.message = l.prefillMsg + m
+	l.span.request.add(l)
+}
+
+func (l *line) Template(m string) {
+	l.kind = "template"
+	l.message = l.prefillMsg + m
+	l.span.request.add(l)
+}
+
+func (l *line) Model(m string, v xopbase.ModelArg) {
+	l.kind = "model"
+	l.message = l.prefillMsg + m
+	l.attributes = append(l.attributes, otlpKeyValue{Key: modelKey, Value: modelValue(v)})
+	l.span.request.add(l)
+}
+
+func (l *line) Link(m string, v xoptrace.Trace) {
+	l.kind = "link"
+	l.message = l.prefillMsg + m
+	l.attributes = append(l.attributes, otlpKeyValue{Key: linkKey, Value: stringValue(v.String())})
+	l.span.request.add(l)
+}
+
+func (l *line) Table(m string, v xopbase.SimpleTable) {
+	rows := v.Rows()
+	values := make([]otlpAnyValue, len(rows))
+	for i, row := range rows {
+		values[i] = stringsValue(row)
+	}
+	l.kind = "table"
+	l.message = l.prefillMsg + m
+	l.attributes = append(l.attributes, otlpKeyValue{Key: tableKey, Value: kvlistValue([]otlpKeyValue{
+		{Key: "header", Value: stringsValue(v.Header())},
+		{Key: "rows", Value: arrayValue(values)},
+	})})
+	l.span.request.add(l)
+}
+
+// xopAttributes are the attributes that describe the line itself
+func (l *line) xopAttributes(withLevel bool) []otlpKeyValue {
+	attributes := l.attributes
+	if len(l.types) != 0 {
+		attributes = append(attributes, otlpKeyValue{Key: typesKey, Value: kvlistValue(l.types)})
+	}
+	if l.kind != "" {
+		attributes = append(attributes, otlpKeyValue{Key: typeKey, Value: stringValue(l.kind)})
+	}
+	if withLevel && l.kind != "metric" {
+		attributes = append(attributes, otlpKeyValue{Key: levelKey, Value: stringValue(l.level.String())})
+	}
+	if len(l.stack) != 0 {
+		attributes = append(attributes, otlpKeyValue{Key: stackKey, Value: stringsValue(l.stack)})
+	}
+	return attributes
+}
+
+func (l *line) event() otlpEvent {
+	return otlpEvent{
+		TimeUnixNano: otlpUint64(l.timestamp.UnixNano()),
+		Name:         l.message,
+		Attributes:   l.xopAttributes(true),
+	}
+}
+
+// record builds a log record. Xop log levels fall inside the
+// OTLP severity number ranges so they're used as-is.
+func (l *line) record() otlpLogRecord {
+	body := stringValue(l.message)
+	return otlpLogRecord{
+		TimeUnixNano:   otlpUint64(l.timestamp.UnixNano()),
+		SeverityNumber: int(l.level),
+		SeverityText:   l.level.String(),
+		Body:           &body,
+		Attributes:     l.xopAttributes(false),
+		Flags:          uint32(l.span.bundle.Trace.GetFlags().Bytes()[0]),
+		TraceID:        l.span.bundle.Trace.GetTraceID().String(),
+		SpanID:         l.span.bundle.Trace.GetSpanID().String(),
+	}
+}
+
+// noImpliedType is used for types that always need to be recorded in
+// xop.types because no OTLP value type implies them
+const noImpliedType = xopbase.DataType(xopproto.AttributeType_Unknown)
+
+func (b *builder) add(k string, v otlpAnyValue, dataType xopbase.DataType, implied xopbase.DataType) {
+	b.attributes = append(b.attributes, otlpKeyValue{Key: k, Value: v})
+	if dataType != implied {
+		b.types = append(b.types, otlpKeyValue{Key: k, Value: stringValue(xopbase.DataTypeToString[dataType])})
+	}
+}
+
+func (b *builder) Enum(k *xopat.EnumAttribute, v xopat.Enum) {
+	b.add(k.Key().String(), enumValue(v), xopbase.EnumDataType, noImpliedType)
+}
+
+func (b *builder) Any(k xopat.K, v xopbase.ModelArg) {
+	b.add(k.String(), modelValue(v), xopbase.AnyDataType, noImpliedType)
+}
+
+func (b *builder) Bool(k xopat.K, v bool) {
+	b.add(k.String(), boolValue(v), xopbase.BoolDataType, xopbase.BoolDataType)
+}
+
+func (b *builder) Time(k xopat.K, v time.Time) {
+	b.add(k.String(), timeValue(v), xopbase.TimeDataType, noImpliedType)
+}
+
+func (b *builder) Duration(k xopat.K, v time.Duration) {
+	b.add(k.String(), intValue(int64(v)), xopbase.DurationDataType, noImpliedType)
+}
+
+func (b *builder) Int64(k xopat.K, v int64, dataType xopbase.DataType) {
+	b.add(k.String(), intValue(v), dataType, xopbase.Int64DataType)
+}
+
+// Uint64 values that don't fit in an int64 appear negative to
+// other OTLP consumers.
+func (b *builder) Uint64(k xopat.K, v uint64, dataType xopbase.DataType) {
+	b.add(k.String(), intValue(int64(v)), dataType, noImpliedType)
+}
+
+func (b *builder) Float64(k xopat.K, v float64, dataType xopbase.DataType) {
+	b.add(k.String(), doubleValue(v), dataType, xopbase.Float64DataType)
+}
+
+func (b *builder) String(k xopat.K, v string, dataType xopbase.DataType) {
+	b.add(k.String(), stringValue(v), dataType, xopbase.StringDataType)
+}
@@ -0,0 +1,636 @@
+package xopotlp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xoplog/xop-go/xopat"
+	"github.com/xoplog/xop-go/xopbase"
+	"github.com/xoplog/xop-go/xopnum"
+	"github.com/xoplog/xop-go/xopproto"
+	"github.com/xoplog/xop-go/xoptrace"
+	"github.com/xoplog/xop-go/xoputil/replayutil"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/pkg/errors"
+)
+
+// spanKeys are the span attributes that are not metadata
+var spanKeys = map[string]struct{}{
+	typeKey:       {},
+	parentKey:     {},
+	baggageKey:    {},
+	definitionKey: {},
+	sequenceKey:   {},
+}
+
+// lineKeys are the event and log record attributes that are not
+// line attributes
+var lineKeys = map[string]struct{}{
+	typeKey:  {},
+	levelKey: {},
+	stackKey: {},
+	typesKey: {},
+	modelKey: {},
+	linkKey:  {},
+	tableKey: {},
+	valueKey: {},
+}
+
+type replayData struct {
+	logger   xopbase.Logger
+	spans    map[string]*replaySpan
+	requests []*replaySpan
+	ordered  []*replaySpan
+	events   []replayEvent
+}
+
+type replaySpan struct {
+	input       otlpSpan // the most recent copy, without events
+	resource    otlpResource
+	scope       otlpScope
+	children    []*replaySpan
+	request     *replaySpan
+	span        xopbase.Span
+	registry    *xopat.Registry
+	definitions map[string]*replayutil.DecodeAttributeDefinition
+}
+
+type replayEvent struct {
+	spanID     string
+	time       otlpUint64
+	name       string
+	level      xopnum.Level
+	attributes []otlpKeyValue
+	record     bool
+}
+
+// Replay reads newline-delimited OTLP/JSON export requests, as written
+// by New, and sends them to a base logger. Spans that were flushed more
+// than once are merged. Lines and metrics are replayed in timestamp
+// order.
+func Replay(ctx context.Context, input io.Reader, logger xopbase.Logger) error {
+	x := &replayData{
+		logger: logger,
+		spans:  make(map[string]*replaySpan),
+	}
+	dec := json.NewDecoder(input)
+	for {
+		var doc otlpDocument
+		err := dec.Decode(&doc)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errors.Wrap(err, "decode otlp")
+		}
+		for _, rs := range doc.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				for _, span := range ss.Spans {
+					x.addSpan(rs.Resource, ss.Scope, span)
+				}
+			}
+		}
+		for _, rl := range doc.ResourceLogs {
+			for _, sl := range rl.ScopeLogs {
+				for _, record := range sl.LogRecords {
+					var body string
+					if record.Body != nil {
+						body, _ = record.Body.asString()
+					}
+					x.events = append(x.events, replayEvent{
+						spanID:     record.SpanID,
+						time:       record.TimeUnixNano,
+						name:       body,
+						level:      xopnum.Level(record.SeverityNumber),
+						attributes: record.Attributes,
+						record:     true,
+					})
+				}
+			}
+		}
+	}
+	return x.replay(ctx)
+}
+
+// Replay implements xopbase.CanReplay
+func (logger *Logger) Replay(ctx context.Context, input []byte, output xopbase.Logger) error {
+	return Replay(ctx, bytes.NewReader(input), output)
+}
+
+func (x *replayData) addSpan(resource otlpResource, scope otlpScope, input otlpSpan) {
+	for _, event := range input.Events {
+		x.events = append(x.events, replayEvent{
+			spanID:     input.SpanID,
+			time:       event.TimeUnixNano,
+			name:       event.Name,
+			attributes: event.Attributes,
+		})
+	}
+	input.Events = nil
+	if rs, ok := x.spans[input.SpanID]; ok {
+		rs.input = input
+		rs.resource = resource
+		rs.scope = scope
+		return
+	}
+	rs := &replaySpan{
+		input:    input,
+		resource: resource,
+		scope:    scope,
+	}
+	x.spans[input.SpanID] = rs
+	x.ordered = append(x.ordered, rs)
+}
+
+func (x *replayData) replay(ctx context.Context) error {
+	for _, rs := range x.ordered {
+		switch t := findString(rs.input.Attributes, typeKey); t {
+		case "request":
+			x.requests = append(x.requests, rs)
+		case "span":
+			parent, ok := x.spans[rs.input.ParentSpanID]
+			if !ok {
+				return errors.Errorf("parent span (%s) of span (%s) does not exist", rs.input.ParentSpanID, rs.input.SpanID)
+			}
+			parent.children = append(parent.children, rs)
+		default:
+			return errors.Errorf("span (%s) has unknown %s (%s)", rs.input.SpanID, typeKey, t)
+		}
+	}
+	for _, rs := range x.requests {
+		err := rs.startRequest(ctx, x.logger)
+		if err != nil {
+			return errors.Wrapf(err, "request (%s)", rs.input.SpanID)
+		}
+	}
+	sort.SliceStable(x.events, func(i, j int) bool {
+		return x.events[i].time < x.events[j].time
+	})
+	for _, event := range x.events {
+		rs, ok := x.spans[event.spanID]
+		if !ok || rs.span == nil {
+			return errors.Errorf("line references span (%s) that does not exist", event.spanID)
+		}
+		var err error
+		if findString(event.attributes, typeKey) == "metric" {
+			err = rs.replayMetric(event)
+		} else {
+			err = rs.replayLine(event)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "event (%s) in span (%s)", event.name, event.spanID)
+		}
+	}
+	for i := len(x.ordered) - 1; i >= 0; i-- {
+		rs := x.ordered[i]
+		if rs.span != nil && rs.input.EndTimeUnixNano != 0 {
+			rs.span.Done(time.Unix(0, int64(rs.input.EndTimeUnixNano)), false)
+		}
+	}
+	return nil
+}
+
+func (rs *replaySpan) trace() xoptrace.Trace {
+	var trace xoptrace.Trace
+	trace.TraceID().SetString(rs.input.TraceID)
+	trace.SpanID().SetString(rs.input.SpanID)
+	trace.Flags().SetArray([1]byte{byte(rs.input.Flags)})
+	return trace
+}
+
+func (rs *replaySpan) startRequest(ctx context.Context, logger xopbase.Logger) error {
+	rs.request = rs
+	rs.registry = xopat.NewRegistry(false)
+	rs.definitions = make(map[string]*replayutil.DecodeAttributeDefinition)
+	if v, ok := find(rs.input.Attributes, definitionKey); ok && v.KvlistValue != nil {
+		for _, kv := range v.KvlistValue.Values {
+			s, _ := kv.Value.asString()
+			var def replayutil.DecodeAttributeDefinition
+			err := json.Unmarshal([]byte(s), &def)
+			if err != nil {
+				return errors.Wrapf(err, "decode attribute definition (%s)", s)
+			}
+			rs.definitions[kv.Key] = &def
+		}
+	}
+
+	var bundle xoptrace.Bundle
+	bundle.Trace = rs.trace()
+	if parent := findString(rs.input.Attributes, parentKey); parent != "" {
+		if !bundle.Parent.SetString(parent) {
+			return errors.Errorf("invalid parent (%s)", parent)
+		}
+	}
+	bundle.State.SetString(rs.input.TraceState)
+	bundle.Baggage.SetString(findString(rs.input.Attributes, baggageKey))
+
+	sourceInfo := xopbase.SourceInfo{
+		Source:    findString(rs.resource.Attributes, "service.name"),
+		Namespace: rs.scope.Name,
+	}
+	var err error
+	sourceInfo.SourceVersion, err = semver.StrictNewVersion(findString(rs.resource.Attributes, "service.version"))
+	if err != nil {
+		return errors.Wrap(err, "invalid service.version")
+	}
+	sourceInfo.NamespaceVersion, err = semver.StrictNewVersion(rs.scope.Version)
+	if err != nil {
+		return errors.Wrap(err, "invalid scope version")
+	}
+	rs.span = logger.Request(ctx,
+		time.Unix(0, int64(rs.input.StartTimeUnixNano)),
+		bundle,
+		rs.input.Name,
+		sourceInfo)
+	return rs.startSpans(ctx)
+}
+
+// startSpans replays metadata and then creates the child spans
+func (rs *replaySpan) startSpans(ctx context.Context) error {
+	for _, kv := range rs.input.Attributes {
+		if _, ok := spanKeys[kv.Key]; ok {
+			continue
+		}
+		err := rs.replayMetadata(kv)
+		if err != nil {
+			return errors.Wrapf(err, "metadata (%s)", kv.Key)
+		}
+	}
+	for _, child := range rs.children {
+		child.request = rs.request
+		var bundle xoptrace.Bundle
+		bundle.Trace = child.trace()
+		bundle.Parent = rs.trace()
+		child.span = rs.span.Span(ctx,
+			time.Unix(0, int64(child.input.StartTimeUnixNano)),
+			bundle,
+			child.input.Name,
+			findString(child.input.Attributes, sequenceKey))
+		err := child.startSpans(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "span (%s)", child.input.SpanID)
+		}
+	}
+	return nil
+}
+
+func (rs *replaySpan) replayMetadata(kv otlpKeyValue) error {
+	def, ok := rs.request.definitions[kv.Key]
+	if !ok {
+		return errors.Errorf("no attribute definition")
+	}
+	values := []otlpAnyValue{kv.Value}
+	if def.Multiple {
+		if kv.Value.ArrayValue == nil {
+			return errors.Errorf("expected an array")
+		}
+		values = kv.Value.ArrayValue.Values
+	}
+	registry := rs.request.registry
+	t := xopat.AttributeType(def.AttributeType)
+	switch t.SpanAttributeType() {
+	case xopat.AttributeTypeAny:
+		k, err := registry.ConstructAnyAttribute(def.Make, t)
+		if err != nil {
+			return err
+		}
+		for _, v := range values {
+			m, err := decodeModel(v)
+			if err != nil {
+				return err
+			}
+			rs.span.MetadataAny(k, m)
+		}
+	case xopat.AttributeTypeBool:
+		k, err := registry.ConstructBoolAttribute(def.Make, t)
+		if err != nil {
+			return err
+		}
+		for _, v := range values {
+			if v.BoolValue == nil {
+				return errors.Errorf("expected a boolValue")
+			}
+			rs.span.MetadataBool(k, *v.BoolValue)
+		}
+	case xopat.AttributeTypeEnum:
+		k, err := registry.ConstructEnumAttribute(def.Make, t)
+		if err != nil {
+			return err
+		}
+		for _, v := range values {
+			name, i, err := decodeEnum(v)
+			if err != nil {
+				return err
+			}
+			rs.span.MetadataEnum(&k.EnumAttribute, k.Add64(i, name))
+		}
+	case xopat.AttributeTypeFloat64:
+		k, err := registry.ConstructFloat64Attribute(def.Make, t)
+		if err != nil {
+			return err
+		}
+		for _, v := range values {
+			if v.DoubleValue == nil {
+				return errors.Errorf("expected a doubleValue")
+			}
+			rs.span.MetadataFloat64(k, float64(*v.DoubleValue))
+		}
+	case xopat.AttributeTypeInt64:
+		k, err := registry.ConstructInt64Attribute(def.Make, t)
+		if err != nil {
+			return err
+		}
+		for _, v := range values {
+			if v.IntValue == nil {
+				return errors.Errorf("expected an intValue")
+			}
+			rs.span.MetadataInt64(k, int64(*v.IntValue))
+		}
+	case xopat.AttributeTypeLink:
+		k, err := registry.ConstructLinkAttribute(def.Make, t)
+		if err != nil {
+			return err
+		}
+		for _, v := range values {
+			s, _ := v.asString()
+			trace, ok := xoptrace.TraceFromString(s)
+			if !ok {
+				return errors.Errorf("invalid trace (%s)", s)
+			}
+			rs.span.MetadataLink(k, trace)
+		}
+	case xopat.AttributeTypeString:
+		k, err := registry.ConstructStringAttribute(def.Make, t)
+		if err != nil {
+			return err
+		}
+		for _, v := range values {
+			s, ok := v.asString()
+			if !ok {
+				return errors.Errorf("expected a stringValue")
+			}
+			rs.span.MetadataString(k, s)
+		}
+	case xopat.AttributeTypeTime:
+		k, err := registry.ConstructTimeAttribute(def.Make, t)
+		if err != nil {
+			return err
+		}
+		for _, v := range values {
+			ts, err := decodeTime(v)
+			if err != nil {
+				return err
+			}
+			rs.span.MetadataTime(k, ts)
+		}
+	default:
+		return errors.Errorf("unexpected attribute type %s", t)
+	}
+	return nil
+}
+
+func (rs *replaySpan) replayMetric(event replayEvent) error {
+	def, ok := rs.request.definitions[event.name]
+	if !ok {
+		return errors.Errorf("no attribute definition for metric")
+	}
+	k, err := rs.request.registry.ConstructMetricAttribute(def.Make, xopat.AttributeType(def.AttributeType))
+	if err != nil {
+		return err
+	}
+	v, _ := find(event.attributes, valueKey)
+	if v.DoubleValue == nil {
+		return errors.Errorf("metric is missing %s", valueKey)
+	}
+	rs.span.Metric(k, float64(*v.DoubleValue), time.Unix(0, int64(event.time)))
+	return nil
+}
+
+func (rs *replaySpan) replayLine(event replayEvent) error {
+	level := event.level
+	if !event.record {
+		var err error
+		level, err = xopnum.LevelString(findString(event.attributes, levelKey))
+		if err != nil {
+			return errors.Wrapf(err, "invalid %s", levelKey)
+		}
+	}
+	var frames []runtime.Frame
+	if v, ok := find(event.attributes, stackKey); ok {
+		stack, ok := v.asStrings()
+		if !ok {
+			return errors.Errorf("invalid %s", stackKey)
+		}
+		frames = make([]runtime.Frame, len(stack))
+		for i, s := range stack {
+			n := strings.LastIndexByte(s, ':')
+			if n == -1 {
+				return errors.Errorf("invalid stack frame (%s)", s)
+			}
+			frames[i].File = s[:n]
+			frames[i].Line, _ = strconv.Atoi(s[n+1:])
+		}
+	}
+	line := rs.span.NoPrefill().Line(level, time.Unix(0, int64(event.time)), frames)
+
+	types := make(map[string]xopbase.DataType)
+	if v, ok := find(event.attributes, typesKey); ok && v.KvlistValue != nil {
+		for _, kv := range v.KvlistValue.Values {
+			s, _ := kv.Value.asString()
+			dt, ok := xopbase.StringToDataType[s]
+			if !ok {
+				return errors.Errorf("unknown data type (%s) for %s", s, kv.Key)
+			}
+			types[kv.Key] = dt
+		}
+	}
+	for _, kv := range event.attributes {
+		if _, ok := lineKeys[kv.Key]; ok {
+			continue
+		}
+		err := rs.replayLineAttribute(line, kv, types[kv.Key])
+		if err != nil {
+			return errors.Wrapf(err, "attribute (%s)", kv.Key)
+		}
+	}
+
+	switch t := findString(event.attributes, typeKey); t {
+	case "":
+		line.Msg(event.name)
+	case "template":
+		line.Template(event.name)
+	case "model":
+		v, _ := find(event.attributes, modelKey)
+		m, err := decodeModel(v)
+		if err != nil {
+			return err
+		}
+		line.Model(event.name, m)
+	case "link":
+		s := findString(event.attributes, linkKey)
+		trace, ok := xoptrace.TraceFromString(s)
+		if !ok {
+			return errors.Errorf("invalid trace (%s)", s)
+		}
+		line.Link(event.name, trace)
+	case "table":
+		v, _ := find(event.attributes, tableKey)
+		table, err := decodeTable(v)
+		if err != nil {
+			return err
+		}
+		line.Table(event.name, table)
+	default:
+		return errors.Errorf("unknown %s (%s)", typeKey, t)
+	}
+	return nil
+}
+
+func (rs *replaySpan) replayLineAttribute(line xopbase.Line, kv otlpKeyValue, dt xopbase.DataType) error {
+	k := xopat.K(kv.Key)
+	v := kv.Value
+	switch dt {
+	case noImpliedType:
+		switch {
+		case v.StringValue != nil:
+			line.String(k, *v.StringValue, xopbase.StringDataType)
+		case v.BoolValue != nil:
+			line.Bool(k, *v.BoolValue)
+		case v.IntValue != nil:
+			line.Int64(k, int64(*v.IntValue), xopbase.Int64DataType)
+		case v.DoubleValue != nil:
+			line.Float64(k, float64(*v.DoubleValue), xopbase.Float64DataType)
+		default:
+			return errors.Errorf("unsupported value")
+		}
+	case xopbase.StringDataType, xopbase.ErrorDataType, xopbase.StringerDataType:
+		s, ok := v.asString()
+		if !ok {
+			return errors.Errorf("expected a stringValue")
+		}
+		line.String(k, s, dt)
+	case xopbase.Int64DataType, xopbase.IntDataType, xopbase.Int8DataType, xopbase.Int16DataType, xopbase.Int32DataType:
+		if v.IntValue == nil {
+			return errors.Errorf("expected an intValue")
+		}
+		line.Int64(k, int64(*v.IntValue), dt)
+	case xopbase.UintDataType, xopbase.Uint8DataType, xopbase.Uint16DataType, xopbase.Uint32DataType,
+		xopbase.Uint64DataType, xopbase.UintptrDataType:
+		if v.IntValue == nil {
+			return errors.Errorf("expected an intValue")
+		}
+		line.Uint64(k, uint64(*v.IntValue), dt)
+	case xopbase.Float64DataType, xopbase.Float32DataType:
+		if v.DoubleValue == nil {
+			return errors.Errorf("expected a doubleValue")
+		}
+		line.Float64(k, float64(*v.DoubleValue), dt)
+	case xopbase.BoolDataType:
+		if v.BoolValue == nil {
+			return errors.Errorf("expected a boolValue")
+		}
+		line.Bool(k, *v.BoolValue)
+	case xopbase.DurationDataType:
+		if v.IntValue == nil {
+			return errors.Errorf("expected an intValue")
+		}
+		line.Duration(k, time.Duration(*v.IntValue))
+	case xopbase.TimeDataType:
+		t, err := decodeTime(v)
+		if err != nil {
+			return err
+		}
+		line.Time(k, t)
+	case xopbase.AnyDataType:
+		m, err := decodeModel(v)
+		if err != nil {
+			return err
+		}
+		line.Any(k, m)
+	case xopbase.EnumDataType:
+		name, i, err := decodeEnum(v)
+		if err != nil {
+			return err
+		}
+		ea, err := rs.request.registry.ConstructEnumAttribute(xopat.Make{Key: kv.Key}, xopat.AttributeTypeEnum)
+		if err != nil {
+			return errors.Wrap(err, "build enum attribute")
+		}
+		line.Enum(&ea.EnumAttribute, ea.Add64(i, name))
+	default:
+		return errors.Errorf("unexpected data type %s", dt)
+	}
+	return nil
+}
+
+func decodeTime(v otlpAnyValue) (time.Time, error) {
+	s, _ := v.asString()
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "invalid time (%s)", s)
+	}
+	return t, nil
+}
+
+func decodeEnum(v otlpAnyValue) (string, int64, error) {
+	if v.KvlistValue == nil {
+		return "", 0, errors.Errorf("expected a kvlistValue for an enum")
+	}
+	i, _ := find(v.KvlistValue.Values, "i")
+	if i.IntValue == nil {
+		return "", 0, errors.Errorf("enum is missing its value")
+	}
+	return findString(v.KvlistValue.Values, "v"), int64(*i.IntValue), nil
+}
+
+func decodeModel(v otlpAnyValue) (xopbase.ModelArg, error) {
+	if v.KvlistValue == nil {
+		return xopbase.ModelArg{}, errors.Errorf("expected a kvlistValue for a model")
+	}
+	kvs := v.KvlistValue.Values
+	encodingName := findString(kvs, "encoding")
+	encoding, ok := xopproto.Encoding_value[encodingName]
+	if !ok {
+		return xopbase.ModelArg{}, errors.Errorf("unknown model encoding (%s)", encodingName)
+	}
+	m := xopbase.ModelArg{
+		ModelType: findString(kvs, "modelType"),
+		Encoding:  xopproto.Encoding(encoding),
+	}
+	encoded, _ := find(kvs, "encoded")
+	if s, ok := encoded.asString(); ok {
+		m.Encoded = []byte(s)
+	} else {
+		m.Encoded = encoded.BytesValue
+	}
+	return m, nil
+}
+
+func decodeTable(v otlpAnyValue) (xopbase.TableData, error) {
+	if v.KvlistValue == nil {
+		return xopbase.TableData{}, errors.Errorf("expected a kvlistValue for a table")
+	}
+	var table xopbase.TableData
+	header, _ := find(v.KvlistValue.Values, "header")
+	table.Columns, _ = header.asStrings()
+	rows, _ := find(v.KvlistValue.Values, "rows")
+	if rows.ArrayValue != nil {
+		table.Cells = make([][]string, len(rows.ArrayValue.Values))
+		for i, row := range rows.ArrayValue.Values {
+			var ok bool
+			table.Cells[i], ok = row.asStrings()
+			if !ok {
+				return xopbase.TableData{}, errors.Errorf("invalid table row %d", i)
+			}
+		}
+	}
+	return table, nil
+}
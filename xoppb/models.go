@@ -33,10 +33,12 @@ type Logger struct {
 type request struct {
 	span
 	errorCount           int32
+	boring               int32 // 1 = boring
 	errorFunc            func(error)
 	alertCount           int32
 	sourceInfo           xopbase.SourceInfo
 	lines                []*xopproto.Line
+	metrics              []*xopproto.Metric
 	lineLock             sync.Mutex
 	requestLock          sync.Mutex
 	priorLines           int
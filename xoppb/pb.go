@@ -54,7 +54,12 @@ func (logger *Logger) Request(_ context.Context, ts time.Time, bundle xoptrace.B
 	return request
 }
 
+// Flush is skipped for boring requests that have no lines at the
+// Error or Alert level.
 func (r *request) Flush() {
+	if atomic.LoadInt32(&r.boring) == 1 && r.GetErrorCount() == 0 && r.GetAlertCount() == 0 {
+		return
+	}
 	r.flushGeneration++
 	rproto := xopproto.Request{
 		Span:                   r.span.getProto(r.flushGeneration),
@@ -67,11 +72,6 @@ func (r *request) Flush() {
 		TraceState:             r.bundle.State.String(),
 	}
 	nLines := make([]*xopproto.Line, 0, 200)
-	func() {
-		r.requestLock.Lock()
-		defer r.requestLock.Unlock()
-		rproto.AttributeDefinitions = r.attributeDefinitions[:]
-	}()
 	func() {
 		r.lineLock.Lock()
 		defer r.lineLock.Unlock()
@@ -79,9 +79,18 @@ func (r *request) Flush() {
 		r.priorLines += len(r.lines)
 		rproto.Lines = r.lines
 		r.lines = nLines
+		rproto.Metrics = r.metrics
+		r.metrics = nil
 		rproto.AlertCount = atomic.LoadInt32(&r.alertCount)
 		rproto.ErrorCount = atomic.LoadInt32(&r.errorCount)
 	}()
+	// attribute definitions are captured after metrics because
+	// metrics reference them
+	func() {
+		r.requestLock.Lock()
+		defer r.requestLock.Unlock()
+		rproto.AttributeDefinitions = r.attributeDefinitions[:]
+	}()
 	r.logger.writer.Request(r.bundle.Trace.GetTraceID(), &rproto)
 	r.logger.writer.Flush()
 }
@@ -122,6 +131,17 @@ func (s *span) getProto(flushGeneration int) *xopproto.Span {
 
 func (r *request) Final() {}
 
+// Boring is honored for requests. Lines are kept until Flush, so
+// boring requests are simply not flushed.
+func (r *request) Boring(b bool) bool {
+	if b {
+		atomic.StoreInt32(&r.boring, 1)
+	} else {
+		atomic.StoreInt32(&r.boring, 0)
+	}
+	return true
+}
+
 func (r *request) SetErrorReporter(reporter func(error)) { r.errorFunc = reporter }
 func (r *request) GetErrorCount() int32                  { return atomic.LoadInt32(&r.errorCount) }
 func (r *request) GetAlertCount() int32                  { return atomic.LoadInt32(&r.alertCount) }
@@ -185,7 +205,20 @@ func (s *span) done() {
 	s.parent.done()
 }
 
-func (s *span) Boring(bool)                {}
+func (s *span) Metric(k *xopat.MetricAttribute, v float64, t time.Time) {
+	xoputil.AtomicMaxInt64(&s.endTime, t.UnixNano())
+	metric := &xopproto.Metric{
+		SpanID:                            s.bundle.Trace.SpanID().Bytes(),
+		AttributeDefinitionSequenceNumber: s.request.defineAttribute(k),
+		Timestamp:                         t.UnixNano(),
+		Value:                             v,
+	}
+	s.request.lineLock.Lock()
+	defer s.request.lineLock.Unlock()
+	s.request.metrics = append(s.request.metrics, metric)
+}
+
+func (s *span) Boring(bool) bool           { return false }
 func (s *span) ID() string                 { return s.logger.id.String() }
 func (s *span) GetBundle() xoptrace.Bundle { return s.bundle }
 func (s *span) GetStartTime() time.Time    { return time.Unix(0, s.protoSpan.StartTime) }
@@ -280,6 +313,22 @@ func (l *line) Model(k string, v xopbase.ModelArg) {
 	l.done()
 }
 
+func (l *line) Table(k string, v xopbase.SimpleTable) {
+	rows := v.Rows()
+	l.protoLine.Table = &xopproto.Table{
+		Header: list.Copy(v.Header()),
+		Rows:   make([]*xopproto.TableRow, len(rows)),
+	}
+	for i, row := range rows {
+		l.protoLine.Table.Rows[i] = &xopproto.TableRow{
+			Cells: list.Copy(row),
+		}
+	}
+	l.protoLine.LineKind = xopproto.LineKind_KindTable
+	l.protoLine.Message = l.prefillMsg + k
+	l.done()
+}
+
 func (l *line) Link(k string, v xoptrace.Trace) {
 	l.protoLine.LineKind = xopproto.LineKind_KindLink
 	l.protoLine.Message = k
@@ -5,6 +5,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/xoplog/xop-go"
 	"github.com/xoplog/xop-go/xoppb"
@@ -61,3 +62,23 @@ func TestReplayPB(t *testing.T) {
 		})
 	}
 }
+
+func TestBoringPB(t *testing.T) {
+	tWriter := &testWriter{}
+	seed := xop.NewSeed(xop.WithBase(xoppb.New(tWriter)))
+
+	log := seed.Request("boring")
+	log.Boring()
+	log.Info().Msg("nothing to see")
+	log.Done()
+	assert.Empty(t, tWriter.captured, "boring request not flushed")
+
+	log = seed.Request("interesting")
+	log.Boring()
+	log.Info().Msg("before error")
+	log.Error().Msg("oops")
+	log.Done()
+	if assert.Len(t, tWriter.captured, 1, "request with error is flushed") {
+		assert.Len(t, tWriter.captured[0].Requests[0].Lines, 2)
+	}
+}
@@ -126,6 +126,23 @@ func (x replayRequest) Replay(ctx context.Context) error {
 		}
 	}
 
+	for _, metric := range x.requestInput.Metrics {
+		spanID := xoptrace.NewHexBytes8FromSlice(metric.SpanID)
+		span, ok := x.spansSeen[spanID]
+		if !ok {
+			return errors.Errorf("metric references spanID (%s) that does not exist", spanID)
+		}
+		if int(metric.AttributeDefinitionSequenceNumber) >= len(x.requestInput.AttributeDefinitions) {
+			return errors.Errorf("metric references attribute definition (%d) that does not exist", metric.AttributeDefinitionSequenceNumber)
+		}
+		def := x.requestInput.AttributeDefinitions[metric.AttributeDefinitionSequenceNumber]
+		registeredAttribute, err := x.registry.ConstructMetricAttribute(attributeMake(def), xopat.AttributeType(def.Type))
+		if err != nil {
+			return err
+		}
+		span.span.Metric(registeredAttribute, metric.Value, time.Unix(0, metric.Timestamp))
+	}
+
 	if x.requestInput.Span.EndTime != nil {
 		x.request.Done(time.Unix(0, *x.requestInput.Span.EndTime), false)
 	}
@@ -254,6 +271,15 @@ func (x replayLine) Replay(ctx context.Context) error {
 			Encoded:   x.lineInput.Model.Encoded,
 			Encoding:  x.lineInput.Model.Encoding,
 		})
+	case x.lineInput.Table != nil:
+		table := xopbase.TableData{
+			Columns: append([]string{}, x.lineInput.Table.Header...),
+			Cells:   make([][]string, len(x.lineInput.Table.Rows)),
+		}
+		for i, row := range x.lineInput.Table.Rows {
+			table.Cells[i] = append([]string{}, row.Cells...)
+		}
+		line.Table(x.lineInput.Message, table)
 	case x.lineInput.Link != "":
 		trace, ok := xoptrace.TraceFromString(x.lineInput.Link)
 		if !ok {
@@ -268,9 +294,8 @@ func (x replayLine) Replay(ctx context.Context) error {
 	return nil
 }
 
-func (x replaySpan) replayAttribute(attribute *xopproto.SpanAttribute) error {
-	def := x.requestInput.AttributeDefinitions[attribute.AttributeDefinitionSequenceNumber]
-	m := xopat.Make{
+func attributeMake(def *xopproto.AttributeDefinition) xopat.Make {
+	return xopat.Make{
 		Key:         def.Key,
 		Description: def.Description,
 		Namespace:   def.Namespace + " " + def.NamespaceSemver,
@@ -281,6 +306,11 @@ func (x replaySpan) replayAttribute(attribute *xopproto.SpanAttribute) error {
 		Ranged:      def.Ranged,
 		Locked:      def.Locked,
 	}
+}
+
+func (x replaySpan) replayAttribute(attribute *xopproto.SpanAttribute) error {
+	def := x.requestInput.AttributeDefinitions[attribute.AttributeDefinitionSequenceNumber]
+	m := attributeMake(def)
 	switch xopat.AttributeType(def.Type).SpanAttributeType() {
 	case xopat.AttributeTypeAny:
 		registeredAttribute, err := x.registry.ConstructAnyAttribute(m, xopat.AttributeType(def.Type))
@@ -26,6 +26,7 @@ const (
 	LineKind_KindLine  LineKind = 0
 	LineKind_KindModel LineKind = 1
 	LineKind_KindLink  LineKind = 2
+	LineKind_KindTable LineKind = 3
 )
 
 // Enum value maps for LineKind.
@@ -34,11 +35,13 @@ var (
 		0: "KindLine",
 		1: "KindModel",
 		2: "KindLink",
+		3: "KindTable",
 	}
 	LineKind_value = map[string]int32{
 		"KindLine":  0,
 		"KindModel": 1,
 		"KindLink":  2,
+		"KindTable": 3,
 	}
 )
 
@@ -146,6 +149,10 @@ const (
 	AttributeType_Time     AttributeType = 12 // value is in intValue, UnixNano.
 	AttributeType_Duration AttributeType = 13 // value is in intValue, nanoseconds
 	AttributeType_Enum     AttributeType = 14 // int is in intValue, string is in stringValue
+	// Metric attributes are only used with Metric, not with span or line attributes
+	AttributeType_Counter   AttributeType = 15
+	AttributeType_Gauge     AttributeType = 16
+	AttributeType_Histogram AttributeType = 17
 	// These are not included in ZZZAttribute, but are included in line attributes (AllData)
 	AttributeType_Uint64   AttributeType = 100
 	AttributeType_Uint32   AttributeType = 101
@@ -199,6 +206,9 @@ var (
 		12:  "Time",
 		13:  "Duration",
 		14:  "Enum",
+		15:  "Counter",
+		16:  "Gauge",
+		17:  "Histogram",
 		100: "Uint64",
 		101: "Uint32",
 		102: "Uint16",
@@ -246,6 +256,9 @@ var (
 		"Time":          12,
 		"Duration":      13,
 		"Enum":          14,
+		"Counter":       15,
+		"Gauge":         16,
+		"Histogram":     17,
 		"Uint64":        100,
 		"Uint32":        101,
 		"Uint16":        102,
@@ -586,6 +599,7 @@ type Request struct {
 	AttributeDefinitions   []*AttributeDefinition `protobuf:"bytes,11,rep,name=attributeDefinitions,proto3" json:"attributeDefinitions,omitempty"` // always includes full set, even if request is sent multiple times
 	Baggage                string                 `protobuf:"bytes,12,opt,name=baggage,proto3" json:"baggage,omitempty"`
 	TraceState             string                 `protobuf:"bytes,13,opt,name=traceState,proto3" json:"traceState,omitempty"`
+	Metrics                []*Metric              `protobuf:"bytes,14,rep,name=metrics,proto3" json:"metrics,omitempty"` // like lines, metrics are kept in the order they were recorded
 }
 
 func (x *Request) Reset() {
@@ -711,6 +725,13 @@ func (x *Request) GetTraceState() string {
 	return ""
 }
 
+func (x *Request) GetMetrics() []*Metric {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
 type Span struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -845,6 +866,7 @@ type Line struct {
 	Link            string        `protobuf:"bytes,8,opt,name=link,proto3" json:"link,omitempty"` // custom type?
 	Model           *Model        `protobuf:"bytes,9,opt,name=model,proto3,oneof" json:"model,omitempty"`
 	StackFrames     []*StackFrame `protobuf:"bytes,10,rep,name=stackFrames,proto3" json:"stackFrames,omitempty"`
+	Table           *Table        `protobuf:"bytes,11,opt,name=table,proto3,oneof" json:"table,omitempty"`
 }
 
 func (x *Line) Reset() {
@@ -949,6 +971,186 @@ func (x *Line) GetStackFrames() []*StackFrame {
 	return nil
 }
 
+func (x *Line) GetTable() *Table {
+	if x != nil {
+		return x.Table
+	}
+	return nil
+}
+
+type Table struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Header []string    `protobuf:"bytes,1,rep,name=header,proto3" json:"header,omitempty"`
+	Rows   []*TableRow `protobuf:"bytes,2,rep,name=rows,proto3" json:"rows,omitempty"`
+}
+
+func (x *Table) Reset() {
+	*x = Table{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_xop_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Table) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Table) ProtoMessage() {}
+
+func (x *Table) ProtoReflect() protoreflect.Message {
+	mi := &file_xop_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Table.ProtoReflect.Descriptor instead.
+func (*Table) Descriptor() ([]byte, []int) {
+	return file_xop_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Table) GetHeader() []string {
+	if x != nil {
+		return x.Header
+	}
+	return nil
+}
+
+func (x *Table) GetRows() []*TableRow {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+type TableRow struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Cells []string `protobuf:"bytes,1,rep,name=cells,proto3" json:"cells,omitempty"`
+}
+
+func (x *TableRow) Reset() {
+	*x = TableRow{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_xop_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TableRow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TableRow) ProtoMessage() {}
+
+func (x *TableRow) ProtoReflect() protoreflect.Message {
+	mi := &file_xop_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TableRow.ProtoReflect.Descriptor instead.
+func (*TableRow) Descriptor() ([]byte, []int) {
+	return file_xop_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *TableRow) GetCells() []string {
+	if x != nil {
+		return x.Cells
+	}
+	return nil
+}
+
+type Metric struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SpanID                            []byte  `protobuf:"bytes,1,opt,name=spanID,proto3" json:"spanID,omitempty"`
+	AttributeDefinitionSequenceNumber uint32  `protobuf:"varint,2,opt,name=attributeDefinitionSequenceNumber,proto3" json:"attributeDefinitionSequenceNumber,omitempty"` // index into Request.attributeDefinitions
+	Timestamp                         int64   `protobuf:"fixed64,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Value                             float64 `protobuf:"fixed64,4,opt,name=value,proto3" json:"value,omitempty"` // counter: amount added; gauge: current value; histogram: observed value
+}
+
+func (x *Metric) Reset() {
+	*x = Metric{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_xop_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Metric) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Metric) ProtoMessage() {}
+
+func (x *Metric) ProtoReflect() protoreflect.Message {
+	mi := &file_xop_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Metric.ProtoReflect.Descriptor instead.
+func (*Metric) Descriptor() ([]byte, []int) {
+	return file_xop_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Metric) GetSpanID() []byte {
+	if x != nil {
+		return x.SpanID
+	}
+	return nil
+}
+
+func (x *Metric) GetAttributeDefinitionSequenceNumber() uint32 {
+	if x != nil {
+		return x.AttributeDefinitionSequenceNumber
+	}
+	return 0
+}
+
+func (x *Metric) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *Metric) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
 type StackFrame struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -961,7 +1163,7 @@ type StackFrame struct {
 func (x *StackFrame) Reset() {
 	*x = StackFrame{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_xop_proto_msgTypes[6]
+		mi := &file_xop_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -974,7 +1176,7 @@ func (x *StackFrame) String() string {
 func (*StackFrame) ProtoMessage() {}
 
 func (x *StackFrame) ProtoReflect() protoreflect.Message {
-	mi := &file_xop_proto_msgTypes[6]
+	mi := &file_xop_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -987,7 +1189,7 @@ func (x *StackFrame) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StackFrame.ProtoReflect.Descriptor instead.
 func (*StackFrame) Descriptor() ([]byte, []int) {
-	return file_xop_proto_rawDescGZIP(), []int{6}
+	return file_xop_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *StackFrame) GetFile() string {
@@ -1017,7 +1219,7 @@ type Model struct {
 func (x *Model) Reset() {
 	*x = Model{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_xop_proto_msgTypes[7]
+		mi := &file_xop_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1030,7 +1232,7 @@ func (x *Model) String() string {
 func (*Model) ProtoMessage() {}
 
 func (x *Model) ProtoReflect() protoreflect.Message {
-	mi := &file_xop_proto_msgTypes[7]
+	mi := &file_xop_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1043,7 +1245,7 @@ func (x *Model) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Model.ProtoReflect.Descriptor instead.
 func (*Model) Descriptor() ([]byte, []int) {
-	return file_xop_proto_rawDescGZIP(), []int{7}
+	return file_xop_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *Model) GetType() string {
@@ -1079,7 +1281,7 @@ type SpanAttribute struct {
 func (x *SpanAttribute) Reset() {
 	*x = SpanAttribute{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_xop_proto_msgTypes[8]
+		mi := &file_xop_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1092,7 +1294,7 @@ func (x *SpanAttribute) String() string {
 func (*SpanAttribute) ProtoMessage() {}
 
 func (x *SpanAttribute) ProtoReflect() protoreflect.Message {
-	mi := &file_xop_proto_msgTypes[8]
+	mi := &file_xop_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1105,7 +1307,7 @@ func (x *SpanAttribute) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SpanAttribute.ProtoReflect.Descriptor instead.
 func (*SpanAttribute) Descriptor() ([]byte, []int) {
-	return file_xop_proto_rawDescGZIP(), []int{8}
+	return file_xop_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *SpanAttribute) GetAttributeDefinitionSequenceNumber() uint32 {
@@ -1135,7 +1337,7 @@ type Attribute struct {
 func (x *Attribute) Reset() {
 	*x = Attribute{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_xop_proto_msgTypes[9]
+		mi := &file_xop_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1148,7 +1350,7 @@ func (x *Attribute) String() string {
 func (*Attribute) ProtoMessage() {}
 
 func (x *Attribute) ProtoReflect() protoreflect.Message {
-	mi := &file_xop_proto_msgTypes[9]
+	mi := &file_xop_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1161,7 +1363,7 @@ func (x *Attribute) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Attribute.ProtoReflect.Descriptor instead.
 func (*Attribute) Descriptor() ([]byte, []int) {
-	return file_xop_proto_rawDescGZIP(), []int{9}
+	return file_xop_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *Attribute) GetKey() string {
@@ -1200,7 +1402,7 @@ type AttributeValue struct {
 func (x *AttributeValue) Reset() {
 	*x = AttributeValue{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_xop_proto_msgTypes[10]
+		mi := &file_xop_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1213,7 +1415,7 @@ func (x *AttributeValue) String() string {
 func (*AttributeValue) ProtoMessage() {}
 
 func (x *AttributeValue) ProtoReflect() protoreflect.Message {
-	mi := &file_xop_proto_msgTypes[10]
+	mi := &file_xop_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1226,7 +1428,7 @@ func (x *AttributeValue) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AttributeValue.ProtoReflect.Descriptor instead.
 func (*AttributeValue) Descriptor() ([]byte, []int) {
-	return file_xop_proto_rawDescGZIP(), []int{10}
+	return file_xop_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *AttributeValue) GetStringValue() string {
@@ -1285,7 +1487,7 @@ type AttributeDefinition struct {
 func (x *AttributeDefinition) Reset() {
 	*x = AttributeDefinition{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_xop_proto_msgTypes[11]
+		mi := &file_xop_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1298,7 +1500,7 @@ func (x *AttributeDefinition) String() string {
 func (*AttributeDefinition) ProtoMessage() {}
 
 func (x *AttributeDefinition) ProtoReflect() protoreflect.Message {
-	mi := &file_xop_proto_msgTypes[11]
+	mi := &file_xop_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1311,7 +1513,7 @@ func (x *AttributeDefinition) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AttributeDefinition.ProtoReflect.Descriptor instead.
 func (*AttributeDefinition) Descriptor() ([]byte, []int) {
-	return file_xop_proto_rawDescGZIP(), []int{11}
+	return file_xop_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *AttributeDefinition) GetKey() string {
@@ -1406,7 +1608,7 @@ type EnumDefinition struct {
 func (x *EnumDefinition) Reset() {
 	*x = EnumDefinition{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_xop_proto_msgTypes[12]
+		mi := &file_xop_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1419,7 +1621,7 @@ func (x *EnumDefinition) String() string {
 func (*EnumDefinition) ProtoMessage() {}
 
 func (x *EnumDefinition) ProtoReflect() protoreflect.Message {
-	mi := &file_xop_proto_msgTypes[12]
+	mi := &file_xop_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1432,7 +1634,7 @@ func (x *EnumDefinition) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EnumDefinition.ProtoReflect.Descriptor instead.
 func (*EnumDefinition) Descriptor() ([]byte, []int) {
-	return file_xop_proto_rawDescGZIP(), []int{12}
+	return file_xop_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *EnumDefinition) GetAttributeKey() string {
@@ -1481,7 +1683,7 @@ type ErrorResponse struct {
 func (x *ErrorResponse) Reset() {
 	*x = ErrorResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_xop_proto_msgTypes[13]
+		mi := &file_xop_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1494,7 +1696,7 @@ func (x *ErrorResponse) String() string {
 func (*ErrorResponse) ProtoMessage() {}
 
 func (x *ErrorResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_xop_proto_msgTypes[13]
+	mi := &file_xop_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1507,7 +1709,7 @@ func (x *ErrorResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ErrorResponse.ProtoReflect.Descriptor instead.
 func (*ErrorResponse) Descriptor() ([]byte, []int) {
-	return file_xop_proto_rawDescGZIP(), []int{13}
+	return file_xop_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *ErrorResponse) GetText() string {
@@ -1528,7 +1730,7 @@ type ReadyToStream struct {
 func (x *ReadyToStream) Reset() {
 	*x = ReadyToStream{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_xop_proto_msgTypes[14]
+		mi := &file_xop_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1541,7 +1743,7 @@ func (x *ReadyToStream) String() string {
 func (*ReadyToStream) ProtoMessage() {}
 
 func (x *ReadyToStream) ProtoReflect() protoreflect.Message {
-	mi := &file_xop_proto_msgTypes[14]
+	mi := &file_xop_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1554,7 +1756,7 @@ func (x *ReadyToStream) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReadyToStream.ProtoReflect.Descriptor instead.
 func (*ReadyToStream) Descriptor() ([]byte, []int) {
-	return file_xop_proto_rawDescGZIP(), []int{14}
+	return file_xop_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *ReadyToStream) GetStreamID() uint64 {
@@ -1573,7 +1775,7 @@ type Empty struct {
 func (x *Empty) Reset() {
 	*x = Empty{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_xop_proto_msgTypes[15]
+		mi := &file_xop_proto_msgTypes[18]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1586,7 +1788,7 @@ func (x *Empty) String() string {
 func (*Empty) ProtoMessage() {}
 
 func (x *Empty) ProtoReflect() protoreflect.Message {
-	mi := &file_xop_proto_msgTypes[15]
+	mi := &file_xop_proto_msgTypes[18]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1599,7 +1801,7 @@ func (x *Empty) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Empty.ProtoReflect.Descriptor instead.
 func (*Empty) Descriptor() ([]byte, []int) {
-	return file_xop_proto_rawDescGZIP(), []int{15}
+	return file_xop_proto_rawDescGZIP(), []int{18}
 }
 
 var File_xop_proto protoreflect.FileDescriptor
@@ -1636,7 +1838,7 @@ var file_xop_proto_rawDesc = []byte{
 	0x01, 0x28, 0x0c, 0x52, 0x07, 0x54, 0x72, 0x61, 0x63, 0x65, 0x49, 0x44, 0x12, 0x28, 0x0a, 0x08,
 	0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c,
 	0x2e, 0x78, 0x6f, 0x70, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x08, 0x72, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x22, 0xa4, 0x04, 0x0a, 0x07, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x22, 0xcb, 0x04, 0x0a, 0x07, 0x52, 0x65, 0x71, 0x75, 0x65,
 	0x73, 0x74, 0x12, 0x1d, 0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
 	0x32, 0x09, 0x2e, 0x78, 0x6f, 0x70, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61,
 	0x6e, 0x12, 0x29, 0x0a, 0x0d, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x54, 0x72, 0x61, 0x63, 0x65,
@@ -1669,192 +1871,217 @@ var file_xop_proto_rawDesc = []byte{
 	0x73, 0x12, 0x18, 0x0a, 0x07, 0x62, 0x61, 0x67, 0x67, 0x61, 0x67, 0x65, 0x18, 0x0c, 0x20, 0x01,
 	0x28, 0x09, 0x52, 0x07, 0x62, 0x61, 0x67, 0x67, 0x61, 0x67, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x74,
 	0x72, 0x61, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0a, 0x74, 0x72, 0x61, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x42, 0x10, 0x0a, 0x0e, 0x5f,
-	0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x54, 0x72, 0x61, 0x63, 0x65, 0x49, 0x44, 0x22, 0xc8, 0x02,
-	0x0a, 0x04, 0x53, 0x70, 0x61, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x70, 0x61, 0x6e, 0x49, 0x44,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x73, 0x70, 0x61, 0x6e, 0x49, 0x44, 0x12, 0x1a,
-	0x0a, 0x08, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
-	0x52, 0x08, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x49, 0x44, 0x12, 0x32, 0x0a, 0x0a, 0x61, 0x74,
-	0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12,
-	0x2e, 0x78, 0x6f, 0x70, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75,
-	0x74, 0x65, 0x52, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x12, 0x1c,
-	0x0a, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28,
-	0x10, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x1d, 0x0a, 0x07,
-	0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x10, 0x48, 0x00, 0x52,
-	0x07, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x18, 0x0a, 0x07, 0x76,
-	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x76, 0x65,
-	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x69, 0x73, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x73, 0x65, 0x71, 0x75, 0x65,
-	0x6e, 0x63, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73,
-	0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x1f, 0x0a, 0x05, 0x73,
-	0x70, 0x61, 0x6e, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x78, 0x6f, 0x70,
-	0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x05, 0x73, 0x70, 0x61, 0x6e, 0x73, 0x42, 0x0a, 0x0a, 0x08,
-	0x5f, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x22, 0xef, 0x02, 0x0a, 0x04, 0x4c, 0x69, 0x6e,
-	0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x70, 0x61, 0x6e, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0c, 0x52, 0x06, 0x73, 0x70, 0x61, 0x6e, 0x49, 0x44, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x6f, 0x67,
-	0x4c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x6c, 0x6f, 0x67,
-	0x4c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
-	0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x10, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
-	0x61, 0x6d, 0x70, 0x12, 0x2e, 0x0a, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65,
-	0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x78, 0x6f, 0x70, 0x2e, 0x41, 0x74,
-	0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x52, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75,
-	0x74, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x05,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x28, 0x0a,
-	0x0f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65,
-	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54,
-	0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x12, 0x29, 0x0a, 0x08, 0x6c, 0x69, 0x6e, 0x65, 0x4b,
-	0x69, 0x6e, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x78, 0x6f, 0x70, 0x2e,
-	0x4c, 0x69, 0x6e, 0x65, 0x4b, 0x69, 0x6e, 0x64, 0x52, 0x08, 0x6c, 0x69, 0x6e, 0x65, 0x4b, 0x69,
-	0x6e, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x69, 0x6e, 0x6b, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x04, 0x6c, 0x69, 0x6e, 0x6b, 0x12, 0x25, 0x0a, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x18,
-	0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x78, 0x6f, 0x70, 0x2e, 0x4d, 0x6f, 0x64, 0x65,
-	0x6c, 0x48, 0x00, 0x52, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x31, 0x0a,
-	0x0b, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x0a, 0x20, 0x03,
-	0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x78, 0x6f, 0x70, 0x2e, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x46, 0x72,
-	0x61, 0x6d, 0x65, 0x52, 0x0b, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x73,
-	0x42, 0x08, 0x0a, 0x06, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x22, 0x40, 0x0a, 0x0a, 0x53, 0x74,
-	0x61, 0x63, 0x6b, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x1e, 0x0a, 0x0a,
-	0x6c, 0x69, 0x6e, 0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05,
-	0x52, 0x0a, 0x6c, 0x69, 0x6e, 0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x22, 0x60, 0x0a, 0x05,
-	0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x6e, 0x63,
-	0x6f, 0x64, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x65, 0x6e, 0x63, 0x6f,
-	0x64, 0x65, 0x64, 0x12, 0x29, 0x0a, 0x08, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x78, 0x6f, 0x70, 0x2e, 0x45, 0x6e, 0x63, 0x6f,
-	0x64, 0x69, 0x6e, 0x67, 0x52, 0x08, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x22, 0x8a,
-	0x01, 0x0a, 0x0d, 0x53, 0x70, 0x61, 0x6e, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65,
-	0x12, 0x4c, 0x0a, 0x21, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x44, 0x65, 0x66,
-	0x69, 0x6e, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x4e,
-	0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x21, 0x61, 0x74, 0x74,
-	0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x44, 0x65, 0x66, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x6f, 0x6e,
-	0x53, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x2b,
-	0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13,
-	0x2e, 0x78, 0x6f, 0x70, 0x2e, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x56, 0x61,
-	0x6c, 0x75, 0x65, 0x52, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x22, 0x70, 0x0a, 0x09, 0x41,
-	0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x26, 0x0a, 0x04, 0x74, 0x79,
-	0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x78, 0x6f, 0x70, 0x2e, 0x41,
-	0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79,
-	0x70, 0x65, 0x12, 0x29, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0a, 0x74, 0x72, 0x61, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x25, 0x0a, 0x07, 0x6d,
+	0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x18, 0x0e, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x78,
+	0x6f, 0x70, 0x2e, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x52, 0x07, 0x6d, 0x65, 0x74, 0x72, 0x69,
+	0x63, 0x73, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x54, 0x72, 0x61,
+	0x63, 0x65, 0x49, 0x44, 0x22, 0xc8, 0x02, 0x0a, 0x04, 0x53, 0x70, 0x61, 0x6e, 0x12, 0x16, 0x0a,
+	0x06, 0x73, 0x70, 0x61, 0x6e, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x73,
+	0x70, 0x61, 0x6e, 0x49, 0x44, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x49,
+	0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x49,
+	0x44, 0x12, 0x32, 0x0a, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x18,
+	0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x78, 0x6f, 0x70, 0x2e, 0x53, 0x70, 0x61, 0x6e,
+	0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x52, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69,
+	0x62, 0x75, 0x74, 0x65, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69,
+	0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x10, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54,
+	0x69, 0x6d, 0x65, 0x12, 0x1d, 0x0a, 0x07, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x10, 0x48, 0x00, 0x52, 0x07, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x88,
+	0x01, 0x01, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09,
+	0x69, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x09, 0x69, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x22,
+	0x0a, 0x0c, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x0a,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x43, 0x6f,
+	0x64, 0x65, 0x12, 0x1f, 0x0a, 0x05, 0x73, 0x70, 0x61, 0x6e, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x09, 0x2e, 0x78, 0x6f, 0x70, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x52, 0x05, 0x73, 0x70,
+	0x61, 0x6e, 0x73, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x22,
+	0xa0, 0x03, 0x0a, 0x04, 0x4c, 0x69, 0x6e, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x70, 0x61, 0x6e,
+	0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x73, 0x70, 0x61, 0x6e, 0x49, 0x44,
+	0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x08, 0x6c, 0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x1c, 0x0a, 0x09,
+	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x10, 0x52,
+	0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x2e, 0x0a, 0x0a, 0x61, 0x74,
+	0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e,
+	0x2e, 0x78, 0x6f, 0x70, 0x2e, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x52, 0x0a,
+	0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54,
+	0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x12, 0x29,
+	0x0a, 0x08, 0x6c, 0x69, 0x6e, 0x65, 0x4b, 0x69, 0x6e, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x0d, 0x2e, 0x78, 0x6f, 0x70, 0x2e, 0x4c, 0x69, 0x6e, 0x65, 0x4b, 0x69, 0x6e, 0x64, 0x52,
+	0x08, 0x6c, 0x69, 0x6e, 0x65, 0x4b, 0x69, 0x6e, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x69, 0x6e,
+	0x6b, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6c, 0x69, 0x6e, 0x6b, 0x12, 0x25, 0x0a,
+	0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x78,
+	0x6f, 0x70, 0x2e, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x48, 0x00, 0x52, 0x05, 0x6d, 0x6f, 0x64, 0x65,
+	0x6c, 0x88, 0x01, 0x01, 0x12, 0x31, 0x0a, 0x0b, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x46, 0x72, 0x61,
+	0x6d, 0x65, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x78, 0x6f, 0x70, 0x2e,
+	0x53, 0x74, 0x61, 0x63, 0x6b, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x52, 0x0b, 0x73, 0x74, 0x61, 0x63,
+	0x6b, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x25, 0x0a, 0x05, 0x74, 0x61, 0x62, 0x6c, 0x65,
+	0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x78, 0x6f, 0x70, 0x2e, 0x54, 0x61, 0x62,
+	0x6c, 0x65, 0x48, 0x01, 0x52, 0x05, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x88, 0x01, 0x01, 0x42, 0x08,
+	0x0a, 0x06, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x74, 0x61, 0x62,
+	0x6c, 0x65, 0x22, 0x42, 0x0a, 0x05, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x68,
+	0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x68, 0x65, 0x61,
+	0x64, 0x65, 0x72, 0x12, 0x21, 0x0a, 0x04, 0x72, 0x6f, 0x77, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x0d, 0x2e, 0x78, 0x6f, 0x70, 0x2e, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x52, 0x6f, 0x77,
+	0x52, 0x04, 0x72, 0x6f, 0x77, 0x73, 0x22, 0x20, 0x0a, 0x08, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x52,
+	0x6f, 0x77, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x65, 0x6c, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x05, 0x63, 0x65, 0x6c, 0x6c, 0x73, 0x22, 0xa2, 0x01, 0x0a, 0x06, 0x4d, 0x65, 0x74,
+	0x72, 0x69, 0x63, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x70, 0x61, 0x6e, 0x49, 0x44, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x06, 0x73, 0x70, 0x61, 0x6e, 0x49, 0x44, 0x12, 0x4c, 0x0a, 0x21, 0x61,
+	0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x44, 0x65, 0x66, 0x69, 0x6e, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x53, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x21, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74,
+	0x65, 0x44, 0x65, 0x66, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x65, 0x71, 0x75, 0x65,
+	0x6e, 0x63, 0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x10, 0x52, 0x09, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x40, 0x0a,
+	0x0a, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x66,
+	0x69, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x12,
+	0x1e, 0x0a, 0x0a, 0x6c, 0x69, 0x6e, 0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0a, 0x6c, 0x69, 0x6e, 0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x22,
+	0x60, 0x0a, 0x05, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x18, 0x0a, 0x07,
+	0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x65,
+	0x6e, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x12, 0x29, 0x0a, 0x08, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x69,
+	0x6e, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x78, 0x6f, 0x70, 0x2e, 0x45,
+	0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x08, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e,
+	0x67, 0x22, 0x8a, 0x01, 0x0a, 0x0d, 0x53, 0x70, 0x61, 0x6e, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62,
+	0x75, 0x74, 0x65, 0x12, 0x4c, 0x0a, 0x21, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65,
+	0x44, 0x65, 0x66, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x65, 0x71, 0x75, 0x65, 0x6e,
+	0x63, 0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x21,
+	0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x44, 0x65, 0x66, 0x69, 0x6e, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x53, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65,
+	0x72, 0x12, 0x2b, 0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
 	0x0b, 0x32, 0x13, 0x2e, 0x78, 0x6f, 0x70, 0x2e, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74,
-	0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0xac, 0x01,
-	0x0a, 0x0e, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x56, 0x61, 0x6c, 0x75, 0x65,
-	0x12, 0x20, 0x0a, 0x0b, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x56, 0x61, 0x6c,
-	0x75, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x6e, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x12, 0x52, 0x08, 0x69, 0x6e, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x1e,
-	0x0a, 0x0a, 0x66, 0x6c, 0x6f, 0x61, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x01, 0x52, 0x0a, 0x66, 0x6c, 0x6f, 0x61, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x1e,
-	0x0a, 0x0a, 0x62, 0x79, 0x74, 0x65, 0x73, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x0c, 0x52, 0x0a, 0x62, 0x79, 0x74, 0x65, 0x73, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x1c,
-	0x0a, 0x09, 0x75, 0x69, 0x6e, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28,
-	0x04, 0x52, 0x09, 0x75, 0x69, 0x6e, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x22, 0xe3, 0x02, 0x0a,
-	0x13, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x44, 0x65, 0x66, 0x69, 0x6e, 0x69,
-	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69,
-	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73,
-	0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x61, 0x6d, 0x65,
-	0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x61, 0x6d,
-	0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70,
-	0x61, 0x63, 0x65, 0x53, 0x65, 0x6d, 0x76, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0f, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x53, 0x65, 0x6d, 0x76, 0x65, 0x72,
-	0x12, 0x26, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12,
-	0x2e, 0x78, 0x6f, 0x70, 0x2e, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x54, 0x79,
-	0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x73, 0x68, 0x6f, 0x75,
-	0x6c, 0x64, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x73,
-	0x68, 0x6f, 0x75, 0x6c, 0x64, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x72,
-	0x6f, 0x6d, 0x69, 0x6e, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a,
-	0x70, 0x72, 0x6f, 0x6d, 0x69, 0x6e, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x6f,
-	0x63, 0x6b, 0x65, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x6c, 0x6f, 0x63, 0x6b,
-	0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x63, 0x74, 0x18, 0x09,
-	0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x64, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x63, 0x74, 0x12, 0x1a,
-	0x0a, 0x08, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x08,
-	0x52, 0x08, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x61,
-	0x6e, 0x67, 0x65, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x72, 0x61, 0x6e, 0x67,
-	0x65, 0x64, 0x22, 0xb0, 0x01, 0x0a, 0x0e, 0x45, 0x6e, 0x75, 0x6d, 0x44, 0x65, 0x66, 0x69, 0x6e,
-	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x22, 0x0a, 0x0c, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75,
-	0x74, 0x65, 0x4b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x61, 0x74, 0x74,
-	0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x4b, 0x65, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x61, 0x6d,
-	0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x61,
-	0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x6e, 0x61, 0x6d, 0x65, 0x73,
-	0x70, 0x61, 0x63, 0x65, 0x53, 0x65, 0x6d, 0x76, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x0f, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x53, 0x65, 0x6d, 0x76, 0x65,
-	0x72, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x06, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x6e, 0x74,
-	0x56, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x69, 0x6e, 0x74,
-	0x56, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x23, 0x0a, 0x0d, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x22, 0x2b, 0x0a, 0x0d, 0x52, 0x65,
-	0x61, 0x64, 0x79, 0x54, 0x6f, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x1a, 0x0a, 0x08, 0x73,
-	0x74, 0x72, 0x65, 0x61, 0x6d, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x06, 0x52, 0x08, 0x73,
-	0x74, 0x72, 0x65, 0x61, 0x6d, 0x49, 0x44, 0x22, 0x07, 0x0a, 0x05, 0x45, 0x6d, 0x70, 0x74, 0x79,
-	0x2a, 0x35, 0x0a, 0x08, 0x4c, 0x69, 0x6e, 0x65, 0x4b, 0x69, 0x6e, 0x64, 0x12, 0x0c, 0x0a, 0x08,
-	0x4b, 0x69, 0x6e, 0x64, 0x4c, 0x69, 0x6e, 0x65, 0x10, 0x00, 0x12, 0x0d, 0x0a, 0x09, 0x4b, 0x69,
-	0x6e, 0x64, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x4b, 0x69, 0x6e,
-	0x64, 0x4c, 0x69, 0x6e, 0x6b, 0x10, 0x02, 0x2a, 0x53, 0x0a, 0x08, 0x45, 0x6e, 0x63, 0x6f, 0x64,
-	0x69, 0x6e, 0x67, 0x12, 0x09, 0x0a, 0x05, 0x55, 0x6e, 0x73, 0x65, 0x74, 0x10, 0x00, 0x12, 0x08,
-	0x0a, 0x04, 0x4a, 0x53, 0x4f, 0x4e, 0x10, 0x01, 0x12, 0x07, 0x0a, 0x03, 0x58, 0x4d, 0x4c, 0x10,
-	0x02, 0x12, 0x07, 0x0a, 0x03, 0x43, 0x53, 0x56, 0x10, 0x03, 0x12, 0x10, 0x0a, 0x0c, 0x45, 0x72,
-	0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x10, 0x04, 0x12, 0x0e, 0x0a, 0x0a,
-	0x4f, 0x6e, 0x65, 0x4c, 0x69, 0x6e, 0x65, 0x43, 0x53, 0x56, 0x10, 0x05, 0x2a, 0x90, 0x05, 0x0a,
-	0x0d, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0b,
-	0x0a, 0x07, 0x55, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x42,
-	0x6f, 0x6f, 0x6c, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07, 0x46, 0x6c, 0x6f, 0x61, 0x74, 0x36, 0x34,
-	0x10, 0x02, 0x12, 0x0b, 0x0a, 0x07, 0x46, 0x6c, 0x6f, 0x61, 0x74, 0x33, 0x32, 0x10, 0x03, 0x12,
-	0x09, 0x0a, 0x05, 0x49, 0x6e, 0x74, 0x36, 0x34, 0x10, 0x04, 0x12, 0x09, 0x0a, 0x05, 0x49, 0x6e,
-	0x74, 0x33, 0x32, 0x10, 0x05, 0x12, 0x09, 0x0a, 0x05, 0x49, 0x6e, 0x74, 0x31, 0x36, 0x10, 0x06,
-	0x12, 0x08, 0x0a, 0x04, 0x49, 0x6e, 0x74, 0x38, 0x10, 0x07, 0x12, 0x07, 0x0a, 0x03, 0x49, 0x6e,
-	0x74, 0x10, 0x08, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x10, 0x09, 0x12,
-	0x08, 0x0a, 0x04, 0x4c, 0x69, 0x6e, 0x6b, 0x10, 0x0a, 0x12, 0x07, 0x0a, 0x03, 0x41, 0x6e, 0x79,
-	0x10, 0x0b, 0x12, 0x08, 0x0a, 0x04, 0x54, 0x69, 0x6d, 0x65, 0x10, 0x0c, 0x12, 0x0c, 0x0a, 0x08,
-	0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x10, 0x0d, 0x12, 0x08, 0x0a, 0x04, 0x45, 0x6e,
-	0x75, 0x6d, 0x10, 0x0e, 0x12, 0x0a, 0x0a, 0x06, 0x55, 0x69, 0x6e, 0x74, 0x36, 0x34, 0x10, 0x64,
-	0x12, 0x0a, 0x0a, 0x06, 0x55, 0x69, 0x6e, 0x74, 0x33, 0x32, 0x10, 0x65, 0x12, 0x0a, 0x0a, 0x06,
-	0x55, 0x69, 0x6e, 0x74, 0x31, 0x36, 0x10, 0x66, 0x12, 0x09, 0x0a, 0x05, 0x55, 0x69, 0x6e, 0x74,
-	0x38, 0x10, 0x67, 0x12, 0x08, 0x0a, 0x04, 0x55, 0x69, 0x6e, 0x74, 0x10, 0x68, 0x12, 0x0b, 0x0a,
-	0x07, 0x55, 0x69, 0x6e, 0x74, 0x70, 0x74, 0x72, 0x10, 0x69, 0x12, 0x0c, 0x0a, 0x08, 0x53, 0x74,
-	0x72, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x10, 0x6a, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f,
-	0x72, 0x10, 0x6b, 0x12, 0x0e, 0x0a, 0x09, 0x41, 0x72, 0x72, 0x61, 0x79, 0x42, 0x6f, 0x6f, 0x6c,
-	0x10, 0xc9, 0x01, 0x12, 0x11, 0x0a, 0x0c, 0x41, 0x72, 0x72, 0x61, 0x79, 0x46, 0x6c, 0x6f, 0x61,
-	0x74, 0x36, 0x34, 0x10, 0xca, 0x01, 0x12, 0x11, 0x0a, 0x0c, 0x41, 0x72, 0x72, 0x61, 0x79, 0x46,
-	0x6c, 0x6f, 0x61, 0x74, 0x33, 0x32, 0x10, 0xcb, 0x01, 0x12, 0x0f, 0x0a, 0x0a, 0x41, 0x72, 0x72,
-	0x61, 0x79, 0x49, 0x6e, 0x74, 0x36, 0x34, 0x10, 0xcc, 0x01, 0x12, 0x0f, 0x0a, 0x0a, 0x41, 0x72,
-	0x72, 0x61, 0x79, 0x49, 0x6e, 0x74, 0x33, 0x32, 0x10, 0xcd, 0x01, 0x12, 0x0f, 0x0a, 0x0a, 0x41,
-	0x72, 0x72, 0x61, 0x79, 0x49, 0x6e, 0x74, 0x31, 0x36, 0x10, 0xce, 0x01, 0x12, 0x0e, 0x0a, 0x09,
-	0x41, 0x72, 0x72, 0x61, 0x79, 0x49, 0x6e, 0x74, 0x38, 0x10, 0xcf, 0x01, 0x12, 0x0d, 0x0a, 0x08,
-	0x41, 0x72, 0x72, 0x61, 0x79, 0x49, 0x6e, 0x74, 0x10, 0xd0, 0x01, 0x12, 0x10, 0x0a, 0x0b, 0x41,
-	0x72, 0x72, 0x61, 0x79, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x10, 0xd1, 0x01, 0x12, 0x0e, 0x0a,
-	0x09, 0x41, 0x72, 0x72, 0x61, 0x79, 0x4c, 0x69, 0x6e, 0x6b, 0x10, 0xd2, 0x01, 0x12, 0x0d, 0x0a,
-	0x08, 0x41, 0x72, 0x72, 0x61, 0x79, 0x41, 0x6e, 0x79, 0x10, 0xd3, 0x01, 0x12, 0x0e, 0x0a, 0x09,
-	0x41, 0x72, 0x72, 0x61, 0x79, 0x54, 0x69, 0x6d, 0x65, 0x10, 0xd4, 0x01, 0x12, 0x12, 0x0a, 0x0d,
-	0x41, 0x72, 0x72, 0x61, 0x79, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x10, 0xd5, 0x01,
-	0x12, 0x0e, 0x0a, 0x09, 0x41, 0x72, 0x72, 0x61, 0x79, 0x45, 0x6e, 0x75, 0x6d, 0x10, 0xd6, 0x01,
-	0x12, 0x10, 0x0a, 0x0b, 0x41, 0x72, 0x72, 0x61, 0x79, 0x55, 0x69, 0x6e, 0x74, 0x36, 0x34, 0x10,
-	0xac, 0x02, 0x12, 0x10, 0x0a, 0x0b, 0x41, 0x72, 0x72, 0x61, 0x79, 0x55, 0x69, 0x6e, 0x74, 0x33,
-	0x32, 0x10, 0xad, 0x02, 0x12, 0x10, 0x0a, 0x0b, 0x41, 0x72, 0x72, 0x61, 0x79, 0x55, 0x69, 0x6e,
-	0x74, 0x31, 0x36, 0x10, 0xae, 0x02, 0x12, 0x0f, 0x0a, 0x0a, 0x41, 0x72, 0x72, 0x61, 0x79, 0x55,
-	0x69, 0x6e, 0x74, 0x38, 0x10, 0xaf, 0x02, 0x12, 0x0e, 0x0a, 0x09, 0x41, 0x72, 0x72, 0x61, 0x79,
-	0x55, 0x69, 0x6e, 0x74, 0x10, 0xb0, 0x02, 0x12, 0x11, 0x0a, 0x0c, 0x41, 0x72, 0x72, 0x61, 0x79,
-	0x55, 0x69, 0x6e, 0x74, 0x70, 0x74, 0x72, 0x10, 0xb1, 0x02, 0x12, 0x12, 0x0a, 0x0d, 0x41, 0x72,
-	0x72, 0x61, 0x79, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x10, 0xb2, 0x02, 0x12, 0x0f,
-	0x0a, 0x0a, 0x41, 0x72, 0x72, 0x61, 0x79, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x10, 0xb3, 0x02, 0x2a,
-	0x3c, 0x0a, 0x09, 0x4c, 0x6f, 0x67, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x13, 0x0a, 0x0f,
-	0x55, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x10,
-	0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x4a, 0x53, 0x4f, 0x4e, 0x49, 0x6e, 0x50, 0x72, 0x6f, 0x74, 0x6f,
-	0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x10, 0x02, 0x32, 0x67, 0x0a,
-	0x06, 0x49, 0x6e, 0x67, 0x65, 0x73, 0x74, 0x12, 0x20, 0x0a, 0x04, 0x50, 0x69, 0x6e, 0x67, 0x12,
-	0x0a, 0x2e, 0x78, 0x6f, 0x70, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x0a, 0x2e, 0x78, 0x6f,
-	0x70, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x3b, 0x0a, 0x0e, 0x55, 0x70, 0x6c,
-	0x6f, 0x61, 0x64, 0x46, 0x72, 0x61, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x13, 0x2e, 0x78, 0x6f,
-	0x70, 0x2e, 0x49, 0x6e, 0x67, 0x65, 0x73, 0x74, 0x46, 0x72, 0x61, 0x67, 0x6d, 0x65, 0x6e, 0x74,
-	0x1a, 0x12, 0x2e, 0x78, 0x6f, 0x70, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x23, 0x5a, 0x21, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
-	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x78, 0x6f, 0x70, 0x6c, 0x6f, 0x67, 0x2f, 0x78, 0x6f, 0x70, 0x2d,
-	0x67, 0x6f, 0x2f, 0x78, 0x6f, 0x70, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x33,
+	0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x22, 0x70,
+	0x0a, 0x09, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x26, 0x0a,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x78, 0x6f,
+	0x70, 0x2e, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x29, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x78, 0x6f, 0x70, 0x2e, 0x41, 0x74, 0x74, 0x72, 0x69,
+	0x62, 0x75, 0x74, 0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x22, 0xac, 0x01, 0x0a, 0x0e, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x56, 0x61,
+	0x6c, 0x75, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x56, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67,
+	0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x6e, 0x74, 0x56, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x12, 0x52, 0x08, 0x69, 0x6e, 0x74, 0x56, 0x61, 0x6c, 0x75,
+	0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x66, 0x6c, 0x6f, 0x61, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x66, 0x6c, 0x6f, 0x61, 0x74, 0x56, 0x61, 0x6c, 0x75,
+	0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x62, 0x79, 0x74, 0x65, 0x73, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x62, 0x79, 0x74, 0x65, 0x73, 0x56, 0x61, 0x6c, 0x75,
+	0x65, 0x12, 0x1c, 0x0a, 0x09, 0x75, 0x69, 0x6e, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x75, 0x69, 0x6e, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x22,
+	0xe3, 0x02, 0x0a, 0x13, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x44, 0x65, 0x66,
+	0x69, 0x6e, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73,
+	0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x6e,
+	0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x6e, 0x61, 0x6d,
+	0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x53, 0x65, 0x6d, 0x76, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0f, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x53, 0x65, 0x6d,
+	0x76, 0x65, 0x72, 0x12, 0x26, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x12, 0x2e, 0x78, 0x6f, 0x70, 0x2e, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74,
+	0x65, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x73,
+	0x68, 0x6f, 0x75, 0x6c, 0x64, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0b, 0x73, 0x68, 0x6f, 0x75, 0x6c, 0x64, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x1e, 0x0a,
+	0x0a, 0x70, 0x72, 0x6f, 0x6d, 0x69, 0x6e, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x0a, 0x70, 0x72, 0x6f, 0x6d, 0x69, 0x6e, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x16, 0x0a,
+	0x06, 0x6c, 0x6f, 0x63, 0x6b, 0x65, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x6c,
+	0x6f, 0x63, 0x6b, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x63,
+	0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x64, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x63,
+	0x74, 0x12, 0x1a, 0x0a, 0x08, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x65, 0x18, 0x0a, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x08, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x65, 0x12, 0x16, 0x0a,
+	0x06, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x72,
+	0x61, 0x6e, 0x67, 0x65, 0x64, 0x22, 0xb0, 0x01, 0x0a, 0x0e, 0x45, 0x6e, 0x75, 0x6d, 0x44, 0x65,
+	0x66, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x22, 0x0a, 0x0c, 0x61, 0x74, 0x74, 0x72,
+	0x69, 0x62, 0x75, 0x74, 0x65, 0x4b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c,
+	0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x4b, 0x65, 0x79, 0x12, 0x1c, 0x0a, 0x09,
+	0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x6e, 0x61,
+	0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x53, 0x65, 0x6d, 0x76, 0x65, 0x72, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0f, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x53, 0x65,
+	0x6d, 0x76, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x12, 0x1a, 0x0a, 0x08,
+	0x69, 0x6e, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08,
+	0x69, 0x6e, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x23, 0x0a, 0x0d, 0x45, 0x72, 0x72, 0x6f,
+	0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x78,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x22, 0x2b, 0x0a,
+	0x0d, 0x52, 0x65, 0x61, 0x64, 0x79, 0x54, 0x6f, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x1a,
+	0x0a, 0x08, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x06,
+	0x52, 0x08, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x49, 0x44, 0x22, 0x07, 0x0a, 0x05, 0x45, 0x6d,
+	0x70, 0x74, 0x79, 0x2a, 0x44, 0x0a, 0x08, 0x4c, 0x69, 0x6e, 0x65, 0x4b, 0x69, 0x6e, 0x64, 0x12,
+	0x0c, 0x0a, 0x08, 0x4b, 0x69, 0x6e, 0x64, 0x4c, 0x69, 0x6e, 0x65, 0x10, 0x00, 0x12, 0x0d, 0x0a,
+	0x09, 0x4b, 0x69, 0x6e, 0x64, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08,
+	0x4b, 0x69, 0x6e, 0x64, 0x4c, 0x69, 0x6e, 0x6b, 0x10, 0x02, 0x12, 0x0d, 0x0a, 0x09, 0x4b, 0x69,
+	0x6e, 0x64, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x10, 0x03, 0x2a, 0x53, 0x0a, 0x08, 0x45, 0x6e, 0x63,
+	0x6f, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x09, 0x0a, 0x05, 0x55, 0x6e, 0x73, 0x65, 0x74, 0x10, 0x00,
+	0x12, 0x08, 0x0a, 0x04, 0x4a, 0x53, 0x4f, 0x4e, 0x10, 0x01, 0x12, 0x07, 0x0a, 0x03, 0x58, 0x4d,
+	0x4c, 0x10, 0x02, 0x12, 0x07, 0x0a, 0x03, 0x43, 0x53, 0x56, 0x10, 0x03, 0x12, 0x10, 0x0a, 0x0c,
+	0x45, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x10, 0x04, 0x12, 0x0e,
+	0x0a, 0x0a, 0x4f, 0x6e, 0x65, 0x4c, 0x69, 0x6e, 0x65, 0x43, 0x53, 0x56, 0x10, 0x05, 0x2a, 0xb7,
+	0x05, 0x0a, 0x0d, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x0b, 0x0a, 0x07, 0x55, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x10, 0x00, 0x12, 0x08, 0x0a,
+	0x04, 0x42, 0x6f, 0x6f, 0x6c, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07, 0x46, 0x6c, 0x6f, 0x61, 0x74,
+	0x36, 0x34, 0x10, 0x02, 0x12, 0x0b, 0x0a, 0x07, 0x46, 0x6c, 0x6f, 0x61, 0x74, 0x33, 0x32, 0x10,
+	0x03, 0x12, 0x09, 0x0a, 0x05, 0x49, 0x6e, 0x74, 0x36, 0x34, 0x10, 0x04, 0x12, 0x09, 0x0a, 0x05,
+	0x49, 0x6e, 0x74, 0x33, 0x32, 0x10, 0x05, 0x12, 0x09, 0x0a, 0x05, 0x49, 0x6e, 0x74, 0x31, 0x36,
+	0x10, 0x06, 0x12, 0x08, 0x0a, 0x04, 0x49, 0x6e, 0x74, 0x38, 0x10, 0x07, 0x12, 0x07, 0x0a, 0x03,
+	0x49, 0x6e, 0x74, 0x10, 0x08, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x10,
+	0x09, 0x12, 0x08, 0x0a, 0x04, 0x4c, 0x69, 0x6e, 0x6b, 0x10, 0x0a, 0x12, 0x07, 0x0a, 0x03, 0x41,
+	0x6e, 0x79, 0x10, 0x0b, 0x12, 0x08, 0x0a, 0x04, 0x54, 0x69, 0x6d, 0x65, 0x10, 0x0c, 0x12, 0x0c,
+	0x0a, 0x08, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x10, 0x0d, 0x12, 0x08, 0x0a, 0x04,
+	0x45, 0x6e, 0x75, 0x6d, 0x10, 0x0e, 0x12, 0x0b, 0x0a, 0x07, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x65,
+	0x72, 0x10, 0x0f, 0x12, 0x09, 0x0a, 0x05, 0x47, 0x61, 0x75, 0x67, 0x65, 0x10, 0x10, 0x12, 0x0d,
+	0x0a, 0x09, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x67, 0x72, 0x61, 0x6d, 0x10, 0x11, 0x12, 0x0a, 0x0a,
+	0x06, 0x55, 0x69, 0x6e, 0x74, 0x36, 0x34, 0x10, 0x64, 0x12, 0x0a, 0x0a, 0x06, 0x55, 0x69, 0x6e,
+	0x74, 0x33, 0x32, 0x10, 0x65, 0x12, 0x0a, 0x0a, 0x06, 0x55, 0x69, 0x6e, 0x74, 0x31, 0x36, 0x10,
+	0x66, 0x12, 0x09, 0x0a, 0x05, 0x55, 0x69, 0x6e, 0x74, 0x38, 0x10, 0x67, 0x12, 0x08, 0x0a, 0x04,
+	0x55, 0x69, 0x6e, 0x74, 0x10, 0x68, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x69, 0x6e, 0x74, 0x70, 0x74,
+	0x72, 0x10, 0x69, 0x12, 0x0c, 0x0a, 0x08, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x10,
+	0x6a, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x10, 0x6b, 0x12, 0x0e, 0x0a, 0x09,
+	0x41, 0x72, 0x72, 0x61, 0x79, 0x42, 0x6f, 0x6f, 0x6c, 0x10, 0xc9, 0x01, 0x12, 0x11, 0x0a, 0x0c,
+	0x41, 0x72, 0x72, 0x61, 0x79, 0x46, 0x6c, 0x6f, 0x61, 0x74, 0x36, 0x34, 0x10, 0xca, 0x01, 0x12,
+	0x11, 0x0a, 0x0c, 0x41, 0x72, 0x72, 0x61, 0x79, 0x46, 0x6c, 0x6f, 0x61, 0x74, 0x33, 0x32, 0x10,
+	0xcb, 0x01, 0x12, 0x0f, 0x0a, 0x0a, 0x41, 0x72, 0x72, 0x61, 0x79, 0x49, 0x6e, 0x74, 0x36, 0x34,
+	0x10, 0xcc, 0x01, 0x12, 0x0f, 0x0a, 0x0a, 0x41, 0x72, 0x72, 0x61, 0x79, 0x49, 0x6e, 0x74, 0x33,
+	0x32, 0x10, 0xcd, 0x01, 0x12, 0x0f, 0x0a, 0x0a, 0x41, 0x72, 0x72, 0x61, 0x79, 0x49, 0x6e, 0x74,
+	0x31, 0x36, 0x10, 0xce, 0x01, 0x12, 0x0e, 0x0a, 0x09, 0x41, 0x72, 0x72, 0x61, 0x79, 0x49, 0x6e,
+	0x74, 0x38, 0x10, 0xcf, 0x01, 0x12, 0x0d, 0x0a, 0x08, 0x41, 0x72, 0x72, 0x61, 0x79, 0x49, 0x6e,
+	0x74, 0x10, 0xd0, 0x01, 0x12, 0x10, 0x0a, 0x0b, 0x41, 0x72, 0x72, 0x61, 0x79, 0x53, 0x74, 0x72,
+	0x69, 0x6e, 0x67, 0x10, 0xd1, 0x01, 0x12, 0x0e, 0x0a, 0x09, 0x41, 0x72, 0x72, 0x61, 0x79, 0x4c,
+	0x69, 0x6e, 0x6b, 0x10, 0xd2, 0x01, 0x12, 0x0d, 0x0a, 0x08, 0x41, 0x72, 0x72, 0x61, 0x79, 0x41,
+	0x6e, 0x79, 0x10, 0xd3, 0x01, 0x12, 0x0e, 0x0a, 0x09, 0x41, 0x72, 0x72, 0x61, 0x79, 0x54, 0x69,
+	0x6d, 0x65, 0x10, 0xd4, 0x01, 0x12, 0x12, 0x0a, 0x0d, 0x41, 0x72, 0x72, 0x61, 0x79, 0x44, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x10, 0xd5, 0x01, 0x12, 0x0e, 0x0a, 0x09, 0x41, 0x72, 0x72,
+	0x61, 0x79, 0x45, 0x6e, 0x75, 0x6d, 0x10, 0xd6, 0x01, 0x12, 0x10, 0x0a, 0x0b, 0x41, 0x72, 0x72,
+	0x61, 0x79, 0x55, 0x69, 0x6e, 0x74, 0x36, 0x34, 0x10, 0xac, 0x02, 0x12, 0x10, 0x0a, 0x0b, 0x41,
+	0x72, 0x72, 0x61, 0x79, 0x55, 0x69, 0x6e, 0x74, 0x33, 0x32, 0x10, 0xad, 0x02, 0x12, 0x10, 0x0a,
+	0x0b, 0x41, 0x72, 0x72, 0x61, 0x79, 0x55, 0x69, 0x6e, 0x74, 0x31, 0x36, 0x10, 0xae, 0x02, 0x12,
+	0x0f, 0x0a, 0x0a, 0x41, 0x72, 0x72, 0x61, 0x79, 0x55, 0x69, 0x6e, 0x74, 0x38, 0x10, 0xaf, 0x02,
+	0x12, 0x0e, 0x0a, 0x09, 0x41, 0x72, 0x72, 0x61, 0x79, 0x55, 0x69, 0x6e, 0x74, 0x10, 0xb0, 0x02,
+	0x12, 0x11, 0x0a, 0x0c, 0x41, 0x72, 0x72, 0x61, 0x79, 0x55, 0x69, 0x6e, 0x74, 0x70, 0x74, 0x72,
+	0x10, 0xb1, 0x02, 0x12, 0x12, 0x0a, 0x0d, 0x41, 0x72, 0x72, 0x61, 0x79, 0x53, 0x74, 0x72, 0x69,
+	0x6e, 0x67, 0x65, 0x72, 0x10, 0xb2, 0x02, 0x12, 0x0f, 0x0a, 0x0a, 0x41, 0x72, 0x72, 0x61, 0x79,
+	0x45, 0x72, 0x72, 0x6f, 0x72, 0x10, 0xb3, 0x02, 0x2a, 0x3c, 0x0a, 0x09, 0x4c, 0x6f, 0x67, 0x46,
+	0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x13, 0x0a, 0x0f, 0x55, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e,
+	0x45, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x4a, 0x53,
+	0x4f, 0x4e, 0x49, 0x6e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x50,
+	0x72, 0x6f, 0x74, 0x6f, 0x10, 0x02, 0x32, 0x67, 0x0a, 0x06, 0x49, 0x6e, 0x67, 0x65, 0x73, 0x74,
+	0x12, 0x20, 0x0a, 0x04, 0x50, 0x69, 0x6e, 0x67, 0x12, 0x0a, 0x2e, 0x78, 0x6f, 0x70, 0x2e, 0x45,
+	0x6d, 0x70, 0x74, 0x79, 0x1a, 0x0a, 0x2e, 0x78, 0x6f, 0x70, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79,
+	0x22, 0x00, 0x12, 0x3b, 0x0a, 0x0e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x72, 0x61, 0x67,
+	0x6d, 0x65, 0x6e, 0x74, 0x12, 0x13, 0x2e, 0x78, 0x6f, 0x70, 0x2e, 0x49, 0x6e, 0x67, 0x65, 0x73,
+	0x74, 0x46, 0x72, 0x61, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x1a, 0x12, 0x2e, 0x78, 0x6f, 0x70, 0x2e,
+	0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42,
+	0x23, 0x5a, 0x21, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x78, 0x6f,
+	0x70, 0x6c, 0x6f, 0x67, 0x2f, 0x78, 0x6f, 0x70, 0x2d, 0x67, 0x6f, 0x2f, 0x78, 0x6f, 0x70, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1870,7 +2097,7 @@ func file_xop_proto_rawDescGZIP() []byte {
 }
 
 var file_xop_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
-var file_xop_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_xop_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
 var file_xop_proto_goTypes = []interface{}{
 	(LineKind)(0),               // 0: xop.LineKind
 	(Encoding)(0),               // 1: xop.Encoding
@@ -1882,16 +2109,19 @@ var file_xop_proto_goTypes = []interface{}{
 	(*Request)(nil),             // 7: xop.Request
 	(*Span)(nil),                // 8: xop.Span
 	(*Line)(nil),                // 9: xop.Line
-	(*StackFrame)(nil),          // 10: xop.StackFrame
-	(*Model)(nil),               // 11: xop.Model
-	(*SpanAttribute)(nil),       // 12: xop.SpanAttribute
-	(*Attribute)(nil),           // 13: xop.Attribute
-	(*AttributeValue)(nil),      // 14: xop.AttributeValue
-	(*AttributeDefinition)(nil), // 15: xop.AttributeDefinition
-	(*EnumDefinition)(nil),      // 16: xop.EnumDefinition
-	(*ErrorResponse)(nil),       // 17: xop.ErrorResponse
-	(*ReadyToStream)(nil),       // 18: xop.ReadyToStream
-	(*Empty)(nil),               // 19: xop.Empty
+	(*Table)(nil),               // 10: xop.Table
+	(*TableRow)(nil),            // 11: xop.TableRow
+	(*Metric)(nil),              // 12: xop.Metric
+	(*StackFrame)(nil),          // 13: xop.StackFrame
+	(*Model)(nil),               // 14: xop.Model
+	(*SpanAttribute)(nil),       // 15: xop.SpanAttribute
+	(*Attribute)(nil),           // 16: xop.Attribute
+	(*AttributeValue)(nil),      // 17: xop.AttributeValue
+	(*AttributeDefinition)(nil), // 18: xop.AttributeDefinition
+	(*EnumDefinition)(nil),      // 19: xop.EnumDefinition
+	(*ErrorResponse)(nil),       // 20: xop.ErrorResponse
+	(*ReadyToStream)(nil),       // 21: xop.ReadyToStream
+	(*Empty)(nil),               // 22: xop.Empty
 }
 var file_xop_proto_depIdxs = []int32{
 	4,  // 0: xop.IngestFragment.sender:type_name -> xop.Sender
@@ -1900,27 +2130,30 @@ var file_xop_proto_depIdxs = []int32{
 	7,  // 3: xop.Trace.requests:type_name -> xop.Request
 	8,  // 4: xop.Request.span:type_name -> xop.Span
 	9,  // 5: xop.Request.lines:type_name -> xop.Line
-	15, // 6: xop.Request.attributeDefinitions:type_name -> xop.AttributeDefinition
-	12, // 7: xop.Span.attributes:type_name -> xop.SpanAttribute
-	8,  // 8: xop.Span.spans:type_name -> xop.Span
-	13, // 9: xop.Line.attributes:type_name -> xop.Attribute
-	0,  // 10: xop.Line.lineKind:type_name -> xop.LineKind
-	11, // 11: xop.Line.model:type_name -> xop.Model
-	10, // 12: xop.Line.stackFrames:type_name -> xop.StackFrame
-	1,  // 13: xop.Model.encoding:type_name -> xop.Encoding
-	14, // 14: xop.SpanAttribute.values:type_name -> xop.AttributeValue
-	2,  // 15: xop.Attribute.type:type_name -> xop.AttributeType
-	14, // 16: xop.Attribute.value:type_name -> xop.AttributeValue
-	2,  // 17: xop.AttributeDefinition.type:type_name -> xop.AttributeType
-	19, // 18: xop.Ingest.Ping:input_type -> xop.Empty
-	5,  // 19: xop.Ingest.UploadFragment:input_type -> xop.IngestFragment
-	19, // 20: xop.Ingest.Ping:output_type -> xop.Empty
-	17, // 21: xop.Ingest.UploadFragment:output_type -> xop.ErrorResponse
-	20, // [20:22] is the sub-list for method output_type
-	18, // [18:20] is the sub-list for method input_type
-	18, // [18:18] is the sub-list for extension type_name
-	18, // [18:18] is the sub-list for extension extendee
-	0,  // [0:18] is the sub-list for field type_name
+	18, // 6: xop.Request.attributeDefinitions:type_name -> xop.AttributeDefinition
+	12, // 7: xop.Request.metrics:type_name -> xop.Metric
+	15, // 8: xop.Span.attributes:type_name -> xop.SpanAttribute
+	8,  // 9: xop.Span.spans:type_name -> xop.Span
+	16, // 10: xop.Line.attributes:type_name -> xop.Attribute
+	0,  // 11: xop.Line.lineKind:type_name -> xop.LineKind
+	14, // 12: xop.Line.model:type_name -> xop.Model
+	13, // 13: xop.Line.stackFrames:type_name -> xop.StackFrame
+	10, // 14: xop.Line.table:type_name -> xop.Table
+	11, // 15: xop.Table.rows:type_name -> xop.TableRow
+	1,  // 16: xop.Model.encoding:type_name -> xop.Encoding
+	17, // 17: xop.SpanAttribute.values:type_name -> xop.AttributeValue
+	2,  // 18: xop.Attribute.type:type_name -> xop.AttributeType
+	17, // 19: xop.Attribute.value:type_name -> xop.AttributeValue
+	2,  // 20: xop.AttributeDefinition.type:type_name -> xop.AttributeType
+	22, // 21: xop.Ingest.Ping:input_type -> xop.Empty
+	5,  // 22: xop.Ingest.UploadFragment:input_type -> xop.IngestFragment
+	22, // 23: xop.Ingest.Ping:output_type -> xop.Empty
+	20, // 24: xop.Ingest.UploadFragment:output_type -> xop.ErrorResponse
+	23, // [23:25] is the sub-list for method output_type
+	21, // [21:23] is the sub-list for method input_type
+	21, // [21:21] is the sub-list for extension type_name
+	21, // [21:21] is the sub-list for extension extendee
+	0,  // [0:21] is the sub-list for field type_name
 }
 
 func init() { file_xop_proto_init() }
@@ -2002,7 +2235,7 @@ func file_xop_proto_init() {
 			}
 		}
 		file_xop_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StackFrame); i {
+			switch v := v.(*Table); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2014,7 +2247,7 @@ func file_xop_proto_init() {
 			}
 		}
 		file_xop_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Model); i {
+			switch v := v.(*TableRow); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2026,7 +2259,7 @@ func file_xop_proto_init() {
 			}
 		}
 		file_xop_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SpanAttribute); i {
+			switch v := v.(*Metric); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2038,7 +2271,7 @@ func file_xop_proto_init() {
 			}
 		}
 		file_xop_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Attribute); i {
+			switch v := v.(*StackFrame); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2050,7 +2283,7 @@ func file_xop_proto_init() {
 			}
 		}
 		file_xop_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AttributeValue); i {
+			switch v := v.(*Model); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2062,7 +2295,7 @@ func file_xop_proto_init() {
 			}
 		}
 		file_xop_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AttributeDefinition); i {
+			switch v := v.(*SpanAttribute); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2074,7 +2307,7 @@ func file_xop_proto_init() {
 			}
 		}
 		file_xop_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*EnumDefinition); i {
+			switch v := v.(*Attribute); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2086,7 +2319,7 @@ func file_xop_proto_init() {
 			}
 		}
 		file_xop_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ErrorResponse); i {
+			switch v := v.(*AttributeValue); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2098,7 +2331,7 @@ func file_xop_proto_init() {
 			}
 		}
 		file_xop_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ReadyToStream); i {
+			switch v := v.(*AttributeDefinition); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2110,6 +2343,42 @@ func file_xop_proto_init() {
 			}
 		}
 		file_xop_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EnumDefinition); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_xop_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ErrorResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_xop_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReadyToStream); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_xop_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Empty); i {
 			case 0:
 				return &v.state
@@ -2131,7 +2400,7 @@ func file_xop_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_xop_proto_rawDesc,
 			NumEnums:      4,
-			NumMessages:   16,
+			NumMessages:   19,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
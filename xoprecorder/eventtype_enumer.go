@@ -9,11 +9,11 @@ import (
 	"strings"
 )
 
-const _EventTypeName = "linerequestStartrequestDonespanStartspanStartflushmetadatacustom"
+const _EventTypeName = "linerequestStartrequestDonespanStartspanStartflushmetadatacustommetric"
 
-var _EventTypeIndex = [...]uint8{0, 4, 16, 27, 36, 45, 50, 58, 64}
+var _EventTypeIndex = [...]uint8{0, 4, 16, 27, 36, 45, 50, 58, 64, 70}
 
-const _EventTypeLowerName = "linerequeststartrequestdonespanstartspanstartflushmetadatacustom"
+const _EventTypeLowerName = "linerequeststartrequestdonespanstartspanstartflushmetadatacustommetric"
 
 func (i EventType) String() string {
 	if i < 0 || i >= EventType(len(_EventTypeIndex)-1) {
@@ -34,9 +34,10 @@ func _EventTypeNoOp() {
 	_ = x[FlushEvent-(5)]
 	_ = x[MetadataSet-(6)]
 	_ = x[CustomEvent-(7)]
+	_ = x[MetricEvent-(8)]
 }
 
-var _EventTypeValues = []EventType{LineEvent, RequestStart, RequestDone, SpanStart, SpanDone, FlushEvent, MetadataSet, CustomEvent}
+var _EventTypeValues = []EventType{LineEvent, RequestStart, RequestDone, SpanStart, SpanDone, FlushEvent, MetadataSet, CustomEvent, MetricEvent}
 
 var _EventTypeNameToValueMap = map[string]EventType{
 	_EventTypeName[0:4]:        LineEvent,
@@ -55,6 +56,8 @@ var _EventTypeNameToValueMap = map[string]EventType{
 	_EventTypeLowerName[50:58]: MetadataSet,
 	_EventTypeName[58:64]:      CustomEvent,
 	_EventTypeLowerName[58:64]: CustomEvent,
+	_EventTypeName[64:70]:      MetricEvent,
+	_EventTypeLowerName[64:70]: MetricEvent,
 }
 
 var _EventTypeNames = []string{
@@ -66,6 +69,7 @@ var _EventTypeNames = []string{
 	_EventTypeName[45:50],
 	_EventTypeName[50:58],
 	_EventTypeName[58:64],
+	_EventTypeName[64:70],
 }
 
 // EventTypeString retrieves an enum value from the enum constants string name.
@@ -18,6 +18,7 @@ import (
 	"github.com/xoplog/xop-go/xopat"
 	"github.com/xoplog/xop-go/xopbase"
 	"github.com/xoplog/xop-go/xopbase/xopbaseutil"
+	"github.com/xoplog/xop-go/xopconst"
 	"github.com/xoplog/xop-go/xopnum"
 	"github.com/xoplog/xop-go/xoptrace"
 	"github.com/xoplog/xop-go/xoputil"
@@ -41,6 +42,7 @@ const (
 	FlushEvent                    // flush
 	MetadataSet                   // metadata
 	CustomEvent                   // custom
+	MetricEvent                   // metric
 )
 
 var (
@@ -77,6 +79,7 @@ type Logger struct {
 	Requests       []*Span
 	Spans          []*Span
 	Lines          []*Line
+	Metrics        []*Metric
 	Events         []*Event
 	SpanIndex      map[[8]byte]*Span
 	requestCounter *xoputil.RequestCounter
@@ -103,6 +106,7 @@ type Span struct {
 	Spans              []*Span
 	Lines              []*Line
 	Links              []*Line // also recorded in Lines
+	Metrics            []*Metric
 	StartTime          time.Time
 	Name               string
 	SpanSequenceCode   string
@@ -138,6 +142,7 @@ type Line struct {
 	Tmpl      string // un-evaluated template
 	AsLink    *xoptrace.Trace
 	AsModel   *xopbase.ModelArg
+	AsTable   *xopbase.TableData
 	Stack     []runtime.Frame
 }
 
@@ -148,16 +153,27 @@ func (l Line) Copy() Line {
 	if l.AsModel != nil {
 		l.AsModel = pointer.To(l.AsModel.Copy())
 	}
+	if l.AsTable != nil {
+		l.AsTable = pointer.To(xopbase.NewTableData(*l.AsTable))
+	}
 	l.Enums = generic.CopyMap(l.Enums)
 	l.Data = generic.CopyMap(l.Data)
 	l.DataType = generic.CopyMap(l.DataType)
 	return l
 }
 
+type Metric struct {
+	Attribute *xopat.MetricAttribute
+	Value     float64
+	Timestamp time.Time
+	Span      *Span
+}
+
 type Event struct {
 	Type      EventType
 	Line      *Line
 	Span      *Span
+	Metric    *Metric
 	Msg       string
 	Attribute xopat.AttributeInterface
 	Done      bool
@@ -251,12 +267,41 @@ func (span *Span) Flush() {
 // Final is a required method for xopbase.Request
 func (span *Span) Final() {}
 
-// Boring is a required method for xopbase.Span
-func (span *Span) Boring(bool) {}
+// Boring is a required method for xopbase.Span. Boring requests
+// are tagged with xopconst.Boring.
+func (span *Span) Boring(b bool) bool {
+	if !span.IsRequest {
+		return false
+	}
+	span.MetadataBool(xopconst.Boring, b)
+	return true
+}
 
 // ID is a required method for xopbase.Span
 func (span *Span) ID() string { return span.logger.id }
 
+// Metric is a required method for xopbase.Span
+func (span *Span) Metric(k *xopat.MetricAttribute, v float64, t time.Time) {
+	xoputil.AtomicMaxInt64(&span.provisionalEndTime, t.UnixNano())
+	metric := &Metric{
+		Attribute: k,
+		Value:     v,
+		Timestamp: t,
+		Span:      span,
+	}
+	span.logger.lock.Lock()
+	defer span.logger.lock.Unlock()
+	span.lock.Lock()
+	defer span.lock.Unlock()
+	span.Metrics = append(span.Metrics, metric)
+	span.logger.Metrics = append(span.logger.Metrics, metric)
+	span.logger.Events = append(span.logger.Events, &Event{
+		Type:   MetricEvent,
+		Span:   span,
+		Metric: metric,
+	})
+}
+
 // ID is a required method for xopbase.Request
 func (span *Span) SetErrorReporter(func(error)) {}
 
@@ -374,6 +419,13 @@ func (line *Line) Model(m string, v xopbase.ModelArg) {
 	line.send(false)
 }
 
+// Table is a required method for xopbase.Line
+func (line *Line) Table(m string, v xopbase.SimpleTable) {
+	line.AsTable = pointer.To(xopbase.NewTableData(v))
+	line.Message += m
+	line.send(false)
+}
+
 // Msg is a required method for xopbase.Line
 func (line *Line) Msg(m string) {
 	line.Message += m
@@ -427,6 +479,9 @@ func (line *Line) Text() string {
 		line.AsModel.Encode()
 		start = "MODEL:"
 		end = string(line.AsModel.Encoded)
+	case line.AsTable != nil:
+		start = "TABLE:"
+		end = fmt.Sprint(line.AsTable.Columns, line.AsTable.Cells)
 	case line.Tmpl != "":
 		used := make(map[xopat.K]struct{})
 		msg = templateRE.ReplaceAllStringFunc(line.Tmpl, func(k string) string {
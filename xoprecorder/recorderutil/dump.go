@@ -30,6 +30,8 @@ func DumpEvents(t testing.TB, tlog *xoprecorder.Logger) {
 				o = append(o, "Custom: "+event.Msg)
 			case xoprecorder.MetadataSet:
 				o = append(o, fmt.Sprintf("Metadata on %s: %s", event.Span.Bundle.Trace.SpanID().String(), event.Msg))
+			case xoprecorder.MetricEvent:
+				o = append(o, fmt.Sprintf("Metric on %s: %s %s=%v", event.Span.Bundle.Trace.SpanID().String(), event.Metric.Attribute.SubType(), event.Metric.Attribute.Key(), event.Metric.Value))
 			default:
 				o = append(o, "unknown event")
 			}
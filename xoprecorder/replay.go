@@ -74,9 +74,17 @@ func (log *Logger) Replay(ctx context.Context, dest xopbase.Logger) error {
 				line.Link(event.Line.Message, *event.Line.AsLink)
 			case event.Line.AsModel != nil:
 				line.Model(event.Line.Message, *event.Line.AsModel)
+			case event.Line.AsTable != nil:
+				line.Table(event.Line.Message, *event.Line.AsTable)
 			default:
 				line.Msg(event.Line.Message)
 			}
+		case MetricEvent:
+			span, ok := spans[event.Span.Bundle.Trace.GetSpanID()]
+			if !ok {
+				return fmt.Errorf("missing span %s for metric", event.Span.Bundle.Trace)
+			}
+			span.Metric(event.Metric.Attribute, event.Metric.Value, event.Metric.Timestamp)
 		case MetadataSet:
 			span, ok := spans[event.Span.Bundle.Trace.GetSpanID()]
 			if !ok {
@@ -2,7 +2,6 @@ package xoptest_test
 
 import (
 	"fmt"
-	"strings"
 	"testing"
 
 	"github.com/xoplog/xop-go"
@@ -11,63 +10,37 @@ import (
 	"github.com/xoplog/xop-go/xoprecorder"
 	"github.com/xoplog/xop-go/xoptest"
 
-	"github.com/mohae/deepcopy"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-type selfRedactor struct {
-	V string
+type story struct {
+	Title string
+	Text  string `xopredact:"pii"`
 }
 
-func (r selfRedactor) Redact() interface{} {
-	r.V = strings.ReplaceAll(r.V, "bribe", "consideration")
-	return r
-}
-
-func (r selfRedactor) String() string {
-	return r.V
-}
-
-type redactor interface {
-	Redact() interface{}
+func (s story) String() string {
+	return s.Text
 }
 
 func TestRedaction(t *testing.T) {
 	tLog := xoptest.New(t)
 	log := xop.NewSeed(
 		xop.WithBase(tLog),
-		xop.WithSettings(func(settings *xop.LogSettings) {
-			settings.SetRedactStringFunc(func(baseLine xopbase.Line, k xopat.K, v string) {
-				v = strings.ReplaceAll(v, "sunflower", "daisy")
-				baseLine.String(k, v, xopbase.StringDataType)
-			})
-			settings.SetRedactAnyFunc(func(baseLine xopbase.Line, k xopat.K, v interface{}, alreadyImmutable bool) {
-				if !alreadyImmutable {
-					v = deepcopy.Copy(v)
-				}
-				if canRedact, ok := v.(redactor); ok {
-					baseLine.Any(k, xopbase.ModelArg{
-						Model: canRedact.Redact(),
-					})
-				} else {
-					baseLine.Any(k, xopbase.ModelArg{
-						Model: v,
-					})
-				}
-			})
-			settings.SetRedactErrorFunc(func(baseLine xopbase.Line, k xopat.K, v error) {
-				baseLine.String(k, v.Error()+"(as string)", xopbase.ErrorDataType)
-			})
-		}),
+		xop.WithRedaction(xop.NewRedactionPolicy().
+			Action(xopat.PII, xop.RedactionMask).
+			Mask("daisy").
+			Key("garden", xopat.PII).
+			Key("success", xopat.PII).
+			Key("oops", xopat.PII)),
 	).Request(t.Name())
 
-	a := selfRedactor{V: "I got the contract with a small bribe, just a sunflower cookie"}
+	a := story{Title: "contract", Text: "I got the contract with a small bribe, just a sunflower cookie"}
 
 	log.Info().
 		String(xop.Key("garden"), "nothing in my garden is taller than my sunflower!").
+		String(xop.Key("plain"), "sunflower").
 		Any(xop.Key("story"), a).
-		Any(xop.Key("tale"), a).
 		AnyWithoutRedaction(xop.Key("raw"), a).
 		Stringer(xop.Key("success"), a).
 		Error(xop.Key("oops"), fmt.Errorf("outer: %w", fmt.Errorf("inner"))).
@@ -76,10 +49,11 @@ func TestRedaction(t *testing.T) {
 	foos := tLog.Recorder().FindLines(xoprecorder.MessageEquals("foo"))
 	require.NotEmpty(t, foos, "foo line")
 
-	assert.Equal(t, "nothing in my garden is taller than my daisy!", foos[0].Data["garden"], "garden")
-	assert.Equal(t, "I got the contract with a small consideration, just a sunflower cookie", foos[0].Data["story"].(xopbase.ModelArg).Model.(selfRedactor).V, "story")
-	assert.Equal(t, "I got the contract with a small consideration, just a sunflower cookie", foos[0].Data["tale"].(xopbase.ModelArg).Model.(selfRedactor).V, "tale")
-	assert.Equal(t, "I got the contract with a small bribe, just a sunflower cookie", foos[0].Data["raw"].(xopbase.ModelArg).Model.(selfRedactor).V, "raw")
-	assert.Equal(t, "I got the contract with a small bribe, just a daisy cookie", foos[0].Data["success"], "success")
-	assert.Equal(t, "outer: inner(as string)", foos[0].Data["oops"], "oops")
+	assert.Equal(t, "daisy", foos[0].Data["garden"], "garden")
+	assert.Equal(t, "sunflower", foos[0].Data["plain"], "plain")
+	assert.Equal(t, story{Title: "contract", Text: "daisy"}, foos[0].Data["story"].(xopbase.ModelArg).Model, "story")
+	assert.Equal(t, story{Title: "contract", Text: "daisy"}, foos[0].Data["raw"].(xopbase.ModelArg).Model, "raw")
+	assert.Equal(t, "daisy", foos[0].Data["success"], "success")
+	assert.Equal(t, "daisy", foos[0].Data["oops"], "oops")
+	assert.Equal(t, "I got the contract with a small bribe, just a sunflower cookie", a.Text, "original not modified")
 }
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/xoplog/xop-go"
+	"github.com/xoplog/xop-go/xopbase"
 	"github.com/xoplog/xop-go/xopconst"
 	"github.com/xoplog/xop-go/xopnum"
 	"github.com/xoplog/xop-go/xoprecorder"
@@ -340,6 +341,21 @@ var MessageCases = []struct {
 			log.Done()
 		},
 	},
+	{
+		Name: "metrics",
+		Do: func(t *testing.T, log *xop.Logger, tlog *xoptest.Logger) {
+			log.Span().Counter(ExampleMetricCounter).Add(1)
+			log.Span().Counter(ExampleMetricCounter).Add(2.5)
+			log.Span().Gauge(ExampleMetricGauge).Set(-3.25)
+			ss := log.Sub().Fork("a fork with metrics")
+			histogram := ss.Span().Histogram(ExampleMetricHistogram)
+			histogram.Observe(0.125)
+			histogram.Observe(1e9)
+			ss.Span().Gauge(ExampleMetricGauge).Set(7)
+			MicroNap()
+			log.Done()
+		},
+	},
 	{
 		Name: "one-done",
 		Do: func(t *testing.T, log *xop.Logger, tlog *xoptest.Logger) {
@@ -488,6 +504,22 @@ var MessageCases = []struct {
 			log.Done()
 		},
 	},
+	{
+		Name: "type-table",
+		Do: func(t *testing.T, log *xop.Logger, tlog *xoptest.Logger) {
+			p := log.Sub().PrefillText("prefilled: ").Logger()
+			p.Warn().Int("count", 3).Table(xopbase.TableData{
+				Columns: []string{"name", "with space", "t", ""},
+				Cells: [][]string{
+					{"alice", "a | b", "", NeedsEscaping},
+					{"bob", "ünïcode"},
+					{"carol", "1", "2", "3", "extra"},
+				},
+			}, "some rows")
+			MicroNap()
+			log.Done()
+		},
+	},
 	{
 		Name: "type-error",
 		Do: func(t *testing.T, log *xop.Logger, tlog *xoptest.Logger) {
@@ -93,6 +93,12 @@ var (
 	ExampleMetadataDistinctXEnum = xopat.Make{Key: "d-xenum", Multiple: true, Distinct: true, Namespace: "test"}.EnumAttribute(xopconst.SpanKindServer)
 )
 
+var (
+	ExampleMetricCounter   = xopat.Make{Key: "counter", Namespace: "test"}.CounterAttribute()
+	ExampleMetricGauge     = xopat.Make{Key: "gauge", Namespace: "test"}.GaugeAttribute()
+	ExampleMetricHistogram = xopat.Make{Key: "histogram", Namespace: "test"}.HistogramAttribute()
+)
+
 var (
 	ExampleMetadataSingleBool       = xopat.Make{Key: "s-bool", Namespace: "test"}.BoolAttribute()
 	ExampleMetadataLockedBool       = xopat.Make{Key: "l-bool", Locked: true, Namespace: "test"}.BoolAttribute()
@@ -28,6 +28,31 @@ func VerifyRecorderReplay(t *testing.T, want *xoprecorder.Logger, got *xoprecord
 	verifyReplaySpans(t, "request", want.Requests, got.Requests)
 	verifyReplaySpans(t, "spans", want.Spans, got.Spans)
 	verifyReplayLines(t, want.Lines, got.Lines)
+	verifyReplayMetrics(t, want.Metrics, got.Metrics)
+}
+
+func verifyReplayMetrics(t *testing.T, want []*xoprecorder.Metric, got []*xoprecorder.Metric) {
+	require.Equal(t, len(want), len(got), "count of metrics")
+	// base loggers are not required to preserve the ordering of metrics
+	// across spans, only within a span
+	sortMetrics := func(metrics []*xoprecorder.Metric) []*xoprecorder.Metric {
+		metrics = list.Copy(metrics)
+		sort.SliceStable(metrics, func(i, j int) bool {
+			return metrics[i].Span.Bundle.Trace.GetSpanID().String() < metrics[j].Span.Bundle.Trace.GetSpanID().String()
+		})
+		return metrics
+	}
+	want = sortMetrics(want)
+	got = sortMetrics(got)
+	for i := range want {
+		t.Logf("verify metric %s", want[i].Attribute.Key())
+		assert.Equal(t, want[i].Span.Bundle.Trace.String(), got[i].Span.Bundle.Trace.String(), "metric span")
+		assert.Equal(t, want[i].Attribute.Key(), got[i].Attribute.Key(), "metric key")
+		assert.Equal(t, want[i].Attribute.SubType().String(), got[i].Attribute.SubType().String(), "metric kind")
+		assert.Equal(t, want[i].Attribute.Namespace(), got[i].Attribute.Namespace(), "metric namespace")
+		assert.Equal(t, want[i].Value, got[i].Value, "metric value")
+		assert.Truef(t, want[i].Timestamp.Equal(got[i].Timestamp), "metric timestamp %s vs %s", want[i].Timestamp.Format(time.RFC3339Nano), got[i].Timestamp.Format(time.RFC3339Nano))
+	}
 }
 
 func verifyReplayLines(t *testing.T, want []*xoprecorder.Line, got []*xoprecorder.Line) {
@@ -53,6 +78,10 @@ func verifyReplayLine(t *testing.T, want *xoprecorder.Line, got *xoprecorder.Lin
 		assert.Equal(t, want.AsModel.ModelType, got.AsModel.ModelType, "model type")
 		assert.Equal(t, want.AsModel.Encoded, got.AsModel.Encoded, "encoded")
 	}
+	if want.AsTable != nil && assert.NotNil(t, got.AsTable, "table") {
+		assert.Equal(t, want.AsTable.Columns, got.AsTable.Columns, "table header")
+		assert.Equal(t, want.AsTable.Cells, got.AsTable.Cells, "table rows")
+	}
 	assert.Equal(t, want.Tmpl, got.Tmpl, "template")
 	for key, wdata := range want.Data {
 		gdata, ok := got.Data[key]
@@ -1,23 +1,371 @@
 package xoptrace
 
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Limits on the size of baggage.  These are the minimums that the
+// W3C baggage specification requires be propagated. Baggage beyond
+// these limits is dropped when parsed with SetString and rejected
+// by Set and ParseBaggage.
+const (
+	MaxBaggageMembers = 64
+	MaxBaggageBytes   = 8192
+)
+
 // Baggage tracks the contents of key/values that are passed
 // through a trace in the "baggage" header.
 // See https://w3c.github.io/baggage/
 // Note that baggage values may contain PII and should not be logged
 // where PII isn't allowed.
 //
-// TODO: allow baggage to be used and modified
+// Baggage is immutable except through its pointer methods (SetString, Set,
+// and Delete) and those replace, rather than modify, the underlying
+// storage so copies of a Baggage are not affected by changes to
+// the original.
 type Baggage struct {
-	// TODO: allow bagggage to be manipulated
-	// baggage []struct {
-	// 	key    string   // printable except ()<>@,;:\"/[]?={}
-	// 	values []string // no whitespace, double-quote, comman, semicolon or backslash
-	// }
+	members  []BaggageMember
 	asString string
 }
 
-func (b *Baggage) SetString(h string) { b.asString = h }
-func (b Baggage) IsZero() bool        { return b.asString == "" }
-func (b Baggage) String() string      { return b.asString }
-func (b Baggage) Copy() Baggage       { return b }
-func (b Baggage) Bytes() []byte       { return []byte(b.asString) } // TODO: improve performance
+// BaggageMember is one key/value pair from the baggage header.
+// The Value is stored decoded: percent-encoding is removed when
+// parsing and added when serializing.
+type BaggageMember struct {
+	Key        string
+	Value      string
+	Properties []BaggageProperty
+}
+
+// BaggageProperty is metadata attached to a BaggageMember.  Properties
+// may be a key by itself or a key/value pair. Property values are
+// kept exactly as they appear in the header.
+type BaggageProperty struct {
+	Key      string
+	Value    string
+	HasValue bool
+}
+
+// ParseBaggage parses a "baggage" header. Unlike SetString, it returns
+// an error if the header is not well-formed or exceeds the size limits.
+func ParseBaggage(h string) (Baggage, error) {
+	var b Baggage
+	err := b.parse(h, true)
+	return b, err
+}
+
+// SetString parses a "baggage" header, replacing the current contents.
+// List members that are not well-formed are dropped as are
+// any list members beyond MaxBaggageMembers or MaxBaggageBytes.
+func (b *Baggage) SetString(h string) { _ = b.parse(h, false) }
+
+func (b Baggage) IsZero() bool   { return len(b.members) == 0 }
+func (b Baggage) String() string { return b.asString }
+func (b Baggage) Copy() Baggage  { return b }
+func (b Baggage) Bytes() []byte  { return []byte(b.asString) } // TODO: improve performance
+func (b Baggage) Len() int       { return len(b.members) }
+
+// Get returns the decoded value for a key.
+func (b Baggage) Get(key string) (string, bool) {
+	if i := b.index(key); i != -1 {
+		return b.members[i].Value, true
+	}
+	return "", false
+}
+
+// Member returns the full member, including properties, for a key.
+func (b Baggage) Member(key string) (BaggageMember, bool) {
+	if i := b.index(key); i != -1 {
+		return b.members[i].copy(), true
+	}
+	return BaggageMember{}, false
+}
+
+// Members returns a copy of all the members in header order.
+func (b Baggage) Members() []BaggageMember {
+	m := make([]BaggageMember, len(b.members))
+	for i, member := range b.members {
+		m[i] = member.copy()
+	}
+	return m
+}
+
+// Set adds or replaces a member. Replaced members keep their
+// position. An error is returned if the key or properties are not
+// valid or if adding the member would exceed the size limits.
+func (b *Baggage) Set(key string, value string, properties ...BaggageProperty) error {
+	member := BaggageMember{
+		Key:        key,
+		Value:      value,
+		Properties: properties,
+	}
+	if err := member.validate(); err != nil {
+		return err
+	}
+	member = member.copy()
+	i := b.index(key)
+	var members []BaggageMember
+	if i == -1 {
+		if len(b.members) >= MaxBaggageMembers {
+			return errors.Errorf("baggage cannot have more than %d members", MaxBaggageMembers)
+		}
+		members = make([]BaggageMember, len(b.members), len(b.members)+1)
+		copy(members, b.members)
+		members = append(members, member)
+	} else {
+		members = make([]BaggageMember, len(b.members))
+		copy(members, b.members)
+		members[i] = member
+	}
+	s := serializeBaggage(members)
+	if len(s) > MaxBaggageBytes {
+		return errors.Errorf("baggage cannot be longer than %d bytes", MaxBaggageBytes)
+	}
+	b.members = members
+	b.asString = s
+	return nil
+}
+
+// Delete removes a member. It is not an error if the member is not present.
+func (b *Baggage) Delete(key string) {
+	i := b.index(key)
+	if i == -1 {
+		return
+	}
+	members := make([]BaggageMember, 0, len(b.members)-1)
+	members = append(members, b.members[:i]...)
+	members = append(members, b.members[i+1:]...)
+	b.members = members
+	b.asString = serializeBaggage(members)
+}
+
+func (b Baggage) index(key string) int {
+	for i, member := range b.members {
+		if member.Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func (b *Baggage) parse(h string, strict bool) error {
+	var members []BaggageMember
+	size := 0
+	var firstErr error
+	fail := func(err error) {
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	for _, raw := range strings.Split(h, ",") {
+		raw = trimOWS(raw)
+		if raw == "" {
+			if strings.TrimSpace(h) != "" {
+				fail(errors.Errorf("empty list member in baggage"))
+			}
+			continue
+		}
+		member, err := parseBaggageMember(raw)
+		if err != nil {
+			fail(err)
+			continue
+		}
+		found := false
+		for i := range members {
+			if members[i].Key == member.Key {
+				members[i] = member
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+		if len(members) >= MaxBaggageMembers {
+			fail(errors.Errorf("baggage cannot have more than %d members", MaxBaggageMembers))
+			continue
+		}
+		add := len(member.serialize(nil))
+		if len(members) > 0 {
+			add++
+		}
+		if size+add > MaxBaggageBytes {
+			fail(errors.Errorf("baggage cannot be longer than %d bytes", MaxBaggageBytes))
+			continue
+		}
+		size += add
+		members = append(members, member)
+	}
+	if strict && firstErr != nil {
+		return firstErr
+	}
+	b.members = members
+	b.asString = serializeBaggage(members)
+	return firstErr
+}
+
+func parseBaggageMember(raw string) (BaggageMember, error) {
+	parts := strings.Split(raw, ";")
+	k, v, ok := strings.Cut(parts[0], "=")
+	if !ok {
+		return BaggageMember{}, errors.Errorf("baggage list member (%s) is missing '='", raw)
+	}
+	value, err := decodeBaggageValue(trimOWS(v))
+	if err != nil {
+		return BaggageMember{}, err
+	}
+	member := BaggageMember{
+		Key:   trimOWS(k),
+		Value: value,
+	}
+	for _, p := range parts[1:] {
+		pk, pv, hasValue := strings.Cut(p, "=")
+		member.Properties = append(member.Properties, BaggageProperty{
+			Key:      trimOWS(pk),
+			Value:    trimOWS(pv),
+			HasValue: hasValue,
+		})
+	}
+	return member, member.validate()
+}
+
+func (m BaggageMember) validate() error {
+	if !isToken(m.Key) {
+		return errors.Errorf("invalid baggage key (%s)", m.Key)
+	}
+	for _, p := range m.Properties {
+		if !isToken(p.Key) {
+			return errors.Errorf("invalid baggage property key (%s) for %s", p.Key, m.Key)
+		}
+		if !p.HasValue && p.Value != "" {
+			return errors.Errorf("baggage property (%s) for %s has a value but HasValue is false", p.Key, m.Key)
+		}
+		for i := 0; i < len(p.Value); i++ {
+			if !isBaggageOctet(p.Value[i]) {
+				return errors.Errorf("invalid baggage property value (%s) for %s", p.Value, m.Key)
+			}
+		}
+	}
+	return nil
+}
+
+func (m BaggageMember) copy() BaggageMember {
+	if m.Properties != nil {
+		p := make([]BaggageProperty, len(m.Properties))
+		copy(p, m.Properties)
+		m.Properties = p
+	}
+	return m
+}
+
+func (m BaggageMember) serialize(b []byte) []byte {
+	b = append(b, m.Key...)
+	b = append(b, '=')
+	b = encodeBaggageValue(b, m.Value)
+	for _, p := range m.Properties {
+		b = append(b, ';')
+		b = append(b, p.Key...)
+		if p.HasValue {
+			b = append(b, '=')
+			b = append(b, p.Value...)
+		}
+	}
+	return b
+}
+
+func serializeBaggage(members []BaggageMember) string {
+	var b []byte
+	for i, member := range members {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = member.serialize(b)
+	}
+	return string(b)
+}
+
+func trimOWS(s string) string { return strings.Trim(s, " \t") }
+
+// isToken checks for RFC 7230 token characters
+func isToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case strings.IndexByte("!#$%&'*+-.^_`|~", c) != -1:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// isBaggageOctet checks for characters allowed in baggage values:
+// printable ASCII except space, double-quote, comma, semicolon, and backslash
+func isBaggageOctet(c byte) bool {
+	return c >= 0x21 && c <= 0x7e && c != '"' && c != ',' && c != ';' && c != '\\'
+}
+
+const hexDigits = "0123456789ABCDEF"
+
+func encodeBaggageValue(b []byte, v string) []byte {
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+		if isBaggageOctet(c) && c != '%' {
+			b = append(b, c)
+		} else {
+			b = append(b, '%', hexDigits[c>>4], hexDigits[c&0xf])
+		}
+	}
+	return b
+}
+
+func decodeBaggageValue(v string) (string, error) {
+	if strings.IndexByte(v, '%') == -1 {
+		for i := 0; i < len(v); i++ {
+			if !isBaggageOctet(v[i]) {
+				return "", errors.Errorf("invalid baggage value (%s)", v)
+			}
+		}
+		return v, nil
+	}
+	b := make([]byte, 0, len(v))
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+		switch {
+		case c == '%':
+			if i+2 >= len(v) {
+				return "", errors.Errorf("invalid percent-encoding in baggage value (%s)", v)
+			}
+			hi, ok1 := unhex(v[i+1])
+			lo, ok2 := unhex(v[i+2])
+			if !ok1 || !ok2 {
+				return "", errors.Errorf("invalid percent-encoding in baggage value (%s)", v)
+			}
+			b = append(b, hi<<4|lo)
+			i += 2
+		case isBaggageOctet(c):
+			b = append(b, c)
+		default:
+			return "", errors.Errorf("invalid baggage value (%s)", v)
+		}
+	}
+	return string(b), nil
+}
+
+func unhex(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
@@ -0,0 +1,155 @@
+package xoptrace_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/xoplog/xop-go/xoptrace"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaggageParse(t *testing.T) {
+	cases := []struct {
+		name    string
+		input   string
+		want    string
+		members []xoptrace.BaggageMember
+		bad     bool
+	}{
+		{
+			name:  "simple",
+			input: "userId=alice,serverNode=DF%2028,isProduction=false",
+			want:  "userId=alice,serverNode=DF%2028,isProduction=false",
+			members: []xoptrace.BaggageMember{
+				{Key: "userId", Value: "alice"},
+				{Key: "serverNode", Value: "DF 28"},
+				{Key: "isProduction", Value: "false"},
+			},
+		},
+		{
+			name:  "whitespace and properties",
+			input: " key1 = value1 ; p1 ; p2=v2 ,key2=value2",
+			want:  "key1=value1;p1;p2=v2,key2=value2",
+			members: []xoptrace.BaggageMember{
+				{Key: "key1", Value: "value1", Properties: []xoptrace.BaggageProperty{
+					{Key: "p1"},
+					{Key: "p2", Value: "v2", HasValue: true},
+				}},
+				{Key: "key2", Value: "value2"},
+			},
+		},
+		{
+			name:  "duplicate key replaced",
+			input: "a=1,b=2,a=3",
+			want:  "a=3,b=2",
+			members: []xoptrace.BaggageMember{
+				{Key: "a", Value: "3"},
+				{Key: "b", Value: "2"},
+			},
+		},
+		{
+			name:  "bad member dropped",
+			input: "a=1,b c=2,d=%zz,e=5",
+			want:  "a=1,e=5",
+			members: []xoptrace.BaggageMember{
+				{Key: "a", Value: "1"},
+				{Key: "e", Value: "5"},
+			},
+			bad: true,
+		},
+		{
+			name:  "missing equals",
+			input: "a",
+			want:  "",
+			bad:   true,
+		},
+		{
+			name:  "empty",
+			input: "",
+			want:  "",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var b xoptrace.Baggage
+			b.SetString(tc.input)
+			assert.Equal(t, tc.want, b.String(), "string")
+			assert.Equal(t, len(tc.members), b.Len(), "len")
+			if len(tc.members) > 0 {
+				assert.Equal(t, tc.members, b.Members(), "members")
+			}
+			_, err := xoptrace.ParseBaggage(tc.input)
+			if tc.bad {
+				assert.Error(t, err, "strict parse")
+			} else {
+				assert.NoError(t, err, "strict parse")
+			}
+		})
+	}
+}
+
+func TestBaggageMutation(t *testing.T) {
+	var b xoptrace.Baggage
+	b.SetString("tenant=acme,flags=a%2Cb")
+	v, ok := b.Get("flags")
+	assert.True(t, ok)
+	assert.Equal(t, "a,b", v)
+
+	orig := b.Copy()
+	require.NoError(t, b.Set("tenant", "wile e", xoptrace.BaggageProperty{Key: "ttl", Value: "30", HasValue: true}))
+	require.NoError(t, b.Set("new", "x"))
+	assert.Equal(t, "tenant=wile%20e;ttl=30,flags=a%2Cb,new=x", b.String())
+	assert.Equal(t, "tenant=acme,flags=a%2Cb", orig.String(), "copy not modified")
+
+	m, ok := b.Member("tenant")
+	require.True(t, ok)
+	assert.Equal(t, "wile e", m.Value)
+	assert.Equal(t, []xoptrace.BaggageProperty{{Key: "ttl", Value: "30", HasValue: true}}, m.Properties)
+
+	b.Delete("flags")
+	b.Delete("missing")
+	assert.Equal(t, "tenant=wile%20e;ttl=30,new=x", b.String())
+	_, ok = b.Get("flags")
+	assert.False(t, ok)
+
+	reparsed, err := xoptrace.ParseBaggage(b.String())
+	require.NoError(t, err)
+	assert.Equal(t, b.Members(), reparsed.Members())
+
+	assert.Error(t, b.Set("bad key", "x"))
+	assert.Error(t, b.Set("k", "x", xoptrace.BaggageProperty{Key: "p", Value: "a,b", HasValue: true}))
+	assert.Equal(t, "tenant=wile%20e;ttl=30,new=x", b.String(), "unchanged after errors")
+
+	b.Delete("tenant")
+	b.Delete("new")
+	assert.True(t, b.IsZero())
+	assert.Equal(t, "", b.String())
+}
+
+func TestBaggageLimits(t *testing.T) {
+	var b xoptrace.Baggage
+	for i := 0; i < xoptrace.MaxBaggageMembers; i++ {
+		require.NoError(t, b.Set("k"+strconv.Itoa(i), "v"))
+	}
+	assert.Error(t, b.Set("one-too-many", "v"))
+	assert.NoError(t, b.Set("k0", "replaced"), "replacing does not add a member")
+
+	var big xoptrace.Baggage
+	assert.Error(t, big.Set("k", strings.Repeat("x", xoptrace.MaxBaggageBytes)))
+	assert.True(t, big.IsZero())
+
+	parts := make([]string, xoptrace.MaxBaggageMembers+5)
+	for i := range parts {
+		parts[i] = "k" + strconv.Itoa(i) + "=v"
+	}
+	h := strings.Join(parts, ",")
+	var dropped xoptrace.Baggage
+	dropped.SetString(h)
+	assert.Equal(t, xoptrace.MaxBaggageMembers, dropped.Len())
+	_, err := xoptrace.ParseBaggage(h)
+	assert.Error(t, err)
+}
@@ -0,0 +1,393 @@
+package xoptrace
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Carrier is what Propagators read from and write to. Keys
+// are always lowercase. Carrier implementations for which
+// case matters must account for that.
+type Carrier interface {
+	// Get returns the value for a key. If there are
+	// multiple values, they should be joined with ",".
+	Get(key string) string
+	Set(key string, value string)
+}
+
+// MapCarrier is a Carrier for use with things like message
+// queues that have a flat set of string attributes.
+type MapCarrier map[string]string
+
+func (m MapCarrier) Get(key string) string        { return m[key] }
+func (m MapCarrier) Set(key string, value string) { m[key] = value }
+
+// HeaderCarrier adapts http.Header to be a Carrier
+type HeaderCarrier http.Header
+
+func (h HeaderCarrier) Get(key string) string {
+	return strings.Join(http.Header(h).Values(key), ",")
+}
+func (h HeaderCarrier) Set(key string, value string) { http.Header(h).Set(key, value) }
+
+// Propagator moves the trace context between a Bundle and
+// a Carrier.
+type Propagator interface {
+	// Extract updates the bundle from the carrier.  For an inbound
+	// request, the bundle should start as a copy of the Seed's bundle.
+	//
+	// When the carrier has a trace, bundle.Parent is set to the
+	// remote span and bundle.Trace is set to be a new span that
+	// is a child of that remote span.  Extract returns true if
+	// a trace was found.  When no trace is found, the bundle is
+	// not modified except that propagators for additional data
+	// (like baggage) may still set that data.
+	Extract(carrier Carrier, bundle *Bundle) bool
+
+	// Inject writes bundle.Trace (and other data, if supported)
+	// into the carrier.  The bundle should be for the span that
+	// is making the outgoing request.
+	Inject(carrier Carrier, bundle Bundle)
+
+	// Fields returns the keys that the propagator uses
+	Fields() []string
+}
+
+var (
+	// W3C handles the "traceparent" and "tracestate" headers
+	// See https://www.w3.org/TR/trace-context/
+	W3C Propagator = w3cPropagator{}
+
+	// W3CBaggage handles the "baggage" header
+	// See https://www.w3.org/TR/baggage/
+	W3CBaggage Propagator = baggagePropagator{}
+
+	// B3Single handles the combined "b3" header
+	// See https://github.com/openzipkin/b3-propagation
+	B3Single Propagator = b3SinglePropagator{}
+
+	// B3Multi handles the "X-B3-TraceId", "X-B3-SpanId",
+	// "X-B3-ParentSpanId", and "X-B3-Sampled" headers.
+	// See https://github.com/openzipkin/b3-propagation
+	B3Multi Propagator = b3MultiPropagator{}
+
+	// Jaeger handles the "uber-trace-id" header
+	// See https://www.jaegertracing.io/docs/1.41/client-libraries/#propagation-format
+	Jaeger Propagator = jaegerPropagator{}
+
+	// AWS handles the "X-Amzn-Trace-Id" header used by AWS X-Ray.  Note
+	// that X-Ray expects the first 8 hex digits of the TraceID to
+	// be the time, in seconds, when the trace started.
+	// See https://docs.aws.amazon.com/xray/latest/devguide/xray-concepts.html#xray-concepts-tracingheader
+	AWS Propagator = awsPropagator{}
+
+	// Default is W3C trace context and baggage
+	Default = Composite(W3C, W3CBaggage)
+)
+
+// Composite combines multiple propagators. Inject calls Inject
+// on all of them. Extract calls Extract on all of them, in order,
+// so when an inbound request has more than one trace format, the
+// last propagator that finds a trace wins.
+func Composite(propagators ...Propagator) Propagator {
+	return composite(propagators)
+}
+
+type composite []Propagator
+
+func (c composite) Extract(carrier Carrier, bundle *Bundle) bool {
+	var found bool
+	for _, p := range c {
+		if p.Extract(carrier, bundle) {
+			found = true
+		}
+	}
+	return found
+}
+
+func (c composite) Inject(carrier Carrier, bundle Bundle) {
+	for _, p := range c {
+		p.Inject(carrier, bundle)
+	}
+}
+
+func (c composite) Fields() []string {
+	var fields []string
+	for _, p := range c {
+		fields = append(fields, p.Fields()...)
+	}
+	return fields
+}
+
+// childOf sets bundle.Parent to be the remote trace and
+// sets bundle.Trace to be a new span in the same trace.
+func childOf(bundle *Bundle, remote Trace) {
+	bundle.Parent = remote
+	bundle.Trace = remote
+	bundle.Trace.SpanID().SetRandom()
+}
+
+func sampledFlag(t *Trace, sampled bool) {
+	if sampled {
+		t.Flags().SetBytes([]byte{1})
+	} else {
+		t.Flags().SetBytes([]byte{0})
+	}
+}
+
+func isSampled(t Trace) bool { return t.GetFlags().Bytes()[0]&1 == 1 }
+
+func leftPad(s string, n int) string {
+	if len(s) >= n {
+		return s
+	}
+	return strings.Repeat("0", n-len(s)) + s
+}
+
+type w3cPropagator struct{}
+
+func (w3cPropagator) Extract(carrier Carrier, bundle *Bundle) bool {
+	remote, ok := TraceFromString(carrier.Get("traceparent"))
+	if !ok {
+		return false
+	}
+	childOf(bundle, remote)
+	if ts := carrier.Get("tracestate"); ts != "" {
+		bundle.State.SetString(ts)
+	}
+	return true
+}
+
+func (w3cPropagator) Inject(carrier Carrier, bundle Bundle) {
+	carrier.Set("traceparent", bundle.Trace.String())
+	if !bundle.State.IsZero() {
+		carrier.Set("tracestate", bundle.State.String())
+	}
+}
+
+func (w3cPropagator) Fields() []string { return []string{"traceparent", "tracestate"} }
+
+type baggagePropagator struct{}
+
+func (baggagePropagator) Extract(carrier Carrier, bundle *Bundle) bool {
+	if bg := carrier.Get("baggage"); bg != "" {
+		bundle.Baggage.SetString(bg)
+	}
+	return false
+}
+
+func (baggagePropagator) Inject(carrier Carrier, bundle Bundle) {
+	if !bundle.Baggage.IsZero() {
+		carrier.Set("baggage", bundle.Baggage.String())
+	}
+}
+
+func (baggagePropagator) Fields() []string { return []string{"baggage"} }
+
+var b3RE = regexp.MustCompile(`^([a-fA-F0-9]{16}|[a-fA-F0-9]{32})-([a-fA-F0-9]{16})(?:-(0|1|true|false|d)(?:-([a-fA-F0-9]{16}))?)?$`)
+
+// B3 shares span ids between the client and the server so
+// the B3 propagators do not create a new SpanID.
+type b3SinglePropagator struct{}
+
+func (b3SinglePropagator) Extract(carrier Carrier, bundle *Bundle) bool {
+	h := carrier.Get("b3")
+	switch h {
+	case "":
+		return false
+	case "0", "1", "true", "false", "d":
+		// sampling decision only: there is no parent to extract so
+		// only the flags of the existing trace are adjusted
+		b3Sampled(&bundle.Trace, h)
+		return false
+	}
+	m := b3RE.FindStringSubmatch(h)
+	if m == nil {
+		return false
+	}
+	bundle.Parent.TraceID().SetString(leftPad(m[1], 32))
+	b3Sampled(&bundle.Parent, m[3])
+	if m[4] == "" {
+		bundle.Parent.SpanID().SetZero()
+	} else {
+		bundle.Parent.SpanID().SetString(m[4])
+	}
+	bundle.Trace = bundle.Parent
+	bundle.Trace.SpanID().SetString(m[2])
+	return true
+}
+
+func (b3SinglePropagator) Inject(carrier Carrier, bundle Bundle) {
+	b3 := bundle.Trace.GetTraceID().String() + "-" + bundle.Trace.GetSpanID().String()
+	if isSampled(bundle.Trace) {
+		b3 += "-1"
+	} else {
+		b3 += "-0"
+	}
+	if !bundle.Parent.GetSpanID().IsZero() {
+		b3 += "-" + bundle.Parent.GetSpanID().String()
+	}
+	carrier.Set("b3", b3)
+}
+
+func (b3SinglePropagator) Fields() []string { return []string{"b3"} }
+
+type b3MultiPropagator struct{}
+
+var (
+	b3TraceIDRE = regexp.MustCompile(`^(?:[a-fA-F0-9]{16}|[a-fA-F0-9]{32})$`)
+	b3SpanIDRE  = regexp.MustCompile(`^[a-fA-F0-9]{16}$`)
+)
+
+func (b3MultiPropagator) Extract(carrier Carrier, bundle *Bundle) bool {
+	traceID := carrier.Get("x-b3-traceid")
+	if !b3TraceIDRE.MatchString(traceID) {
+		return false
+	}
+	parentSpanID := carrier.Get("x-b3-parentspanid")
+	if parentSpanID != "" && !b3SpanIDRE.MatchString(parentSpanID) {
+		return false
+	}
+	spanID := carrier.Get("x-b3-spanid")
+	if spanID != "" && !b3SpanIDRE.MatchString(spanID) {
+		return false
+	}
+	bundle.Trace.TraceID().SetString(leftPad(traceID, 32))
+	if sampled := carrier.Get("x-b3-sampled"); sampled != "" {
+		b3Sampled(&bundle.Trace, sampled)
+	} else if carrier.Get("x-b3-flags") == "1" {
+		b3Sampled(&bundle.Trace, "d")
+	}
+	bundle.Parent = bundle.Trace
+	if parentSpanID != "" {
+		bundle.Parent.SpanID().SetString(parentSpanID)
+	} else {
+		// Uh oh, no parent span id
+		bundle.Parent.SpanID().SetZero()
+	}
+	if spanID != "" {
+		bundle.Trace.SpanID().SetString(spanID)
+	} else {
+		bundle.Trace.SpanID().SetRandom()
+	}
+	return true
+}
+
+func (b3MultiPropagator) Inject(carrier Carrier, bundle Bundle) {
+	carrier.Set("x-b3-traceid", bundle.Trace.GetTraceID().String())
+	carrier.Set("x-b3-spanid", bundle.Trace.GetSpanID().String())
+	if isSampled(bundle.Trace) {
+		carrier.Set("x-b3-sampled", "1")
+	} else {
+		carrier.Set("x-b3-sampled", "0")
+	}
+	if !bundle.Parent.GetSpanID().IsZero() {
+		carrier.Set("x-b3-parentspanid", bundle.Parent.GetSpanID().String())
+	}
+}
+
+func (b3MultiPropagator) Fields() []string {
+	return []string{"x-b3-traceid", "x-b3-spanid", "x-b3-parentspanid", "x-b3-sampled", "x-b3-flags"}
+}
+
+// b3Sampled processes the "X-B3-Sampled" header or
+// the sampled portion of a combined "b3" header
+// Potentially the "d" value could be used to decrease
+// the minimum logging level.
+func b3Sampled(t *Trace, h string) {
+	switch h {
+	case "1", "true", "d":
+		sampledFlag(t, true)
+	case "0", "false":
+		sampledFlag(t, false)
+	}
+}
+
+var jaegerRE = regexp.MustCompile(`^([a-fA-F0-9]{1,32}):([a-fA-F0-9]{1,16}):([a-fA-F0-9]{1,16}):([a-fA-F0-9]{1,2})$`)
+
+type jaegerPropagator struct{}
+
+func (jaegerPropagator) Extract(carrier Carrier, bundle *Bundle) bool {
+	h := strings.ReplaceAll(carrier.Get("uber-trace-id"), "%3A", ":")
+	m := jaegerRE.FindStringSubmatch(h)
+	if m == nil {
+		return false
+	}
+	remote := NewTrace()
+	remote.TraceID().SetString(leftPad(m[1], 32))
+	remote.SpanID().SetString(leftPad(m[2], 16))
+	flags, _ := strconv.ParseUint(m[4], 16, 8)
+	sampledFlag(&remote, flags&1 == 1)
+	if remote.GetTraceID().IsZero() || remote.GetSpanID().IsZero() {
+		return false
+	}
+	childOf(bundle, remote)
+	return true
+}
+
+func (jaegerPropagator) Inject(carrier Carrier, bundle Bundle) {
+	flags := "0"
+	if isSampled(bundle.Trace) {
+		flags = "1"
+	}
+	carrier.Set("uber-trace-id", bundle.Trace.GetTraceID().String()+":"+bundle.Trace.GetSpanID().String()+":0:"+flags)
+}
+
+func (jaegerPropagator) Fields() []string { return []string{"uber-trace-id"} }
+
+var awsRootRE = regexp.MustCompile(`^1-([a-fA-F0-9]{8})-([a-fA-F0-9]{24})$`)
+
+type awsPropagator struct{}
+
+func (awsPropagator) Extract(carrier Carrier, bundle *Bundle) bool {
+	h := carrier.Get("x-amzn-trace-id")
+	if h == "" {
+		return false
+	}
+	remote := NewTrace()
+	var haveRoot bool
+	for _, part := range strings.Split(h, ";") {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch k {
+		case "Root":
+			m := awsRootRE.FindStringSubmatch(v)
+			if m == nil {
+				return false
+			}
+			remote.TraceID().SetString(m[1] + m[2])
+			haveRoot = true
+		case "Parent":
+			if len(v) != 16 {
+				return false
+			}
+			remote.SpanID().SetString(v)
+		case "Sampled":
+			switch v {
+			case "1":
+				sampledFlag(&remote, true)
+			case "0":
+				sampledFlag(&remote, false)
+			}
+		}
+	}
+	if !haveRoot {
+		return false
+	}
+	childOf(bundle, remote)
+	return true
+}
+
+func (awsPropagator) Inject(carrier Carrier, bundle Bundle) {
+	traceID := bundle.Trace.GetTraceID().String()
+	sampled := "0"
+	if isSampled(bundle.Trace) {
+		sampled = "1"
+	}
+	carrier.Set("x-amzn-trace-id", "Root=1-"+traceID[:8]+"-"+traceID[8:]+
+		";Parent="+bundle.Trace.GetSpanID().String()+
+		";Sampled="+sampled)
+}
+
+func (awsPropagator) Fields() []string { return []string{"x-amzn-trace-id"} }
@@ -0,0 +1,267 @@
+package xoptrace_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/xoplog/xop-go/xoptrace"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPropagatorExtract(t *testing.T) {
+	cases := []struct {
+		name        string
+		propagator  xoptrace.Propagator
+		carrier     xoptrace.MapCarrier
+		notFound    bool
+		parent      string
+		traceID     string // defaults to the parent's
+		spanID      string // defaults to random
+		flags       string
+		state       string
+		baggageUser string
+	}{
+		{
+			name:       "w3c",
+			propagator: xoptrace.W3C,
+			carrier: xoptrace.MapCarrier{
+				"traceparent": "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+				"tracestate":  "rojo=00f067aa0ba902b7",
+			},
+			parent: "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+			flags:  "01",
+			state:  "rojo=00f067aa0ba902b7",
+		},
+		{
+			name:       "w3c invalid",
+			propagator: xoptrace.W3C,
+			carrier:    xoptrace.MapCarrier{"traceparent": "00-0af7651916cd43dd8448eb211c80319c-01"},
+			notFound:   true,
+		},
+		{
+			name:        "baggage alone",
+			propagator:  xoptrace.W3CBaggage,
+			carrier:     xoptrace.MapCarrier{"baggage": "user=alice"},
+			notFound:    true,
+			baggageUser: "alice",
+		},
+		{
+			name:       "b3 single",
+			propagator: xoptrace.B3Single,
+			carrier:    xoptrace.MapCarrier{"b3": "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-0-05e3ac9a4f6e3b90"},
+			parent:     "00-80f198ee56343ba864fe8b2a57d3eff7-05e3ac9a4f6e3b90-00",
+			spanID:     "e457b5a2e4d86bd1",
+			flags:      "00",
+		},
+		{
+			name:       "b3 single 64 bit",
+			propagator: xoptrace.B3Single,
+			carrier:    xoptrace.MapCarrier{"b3": "64fe8b2a57d3eff7-e457b5a2e4d86bd1"},
+			parent:     "00-000000000000000064fe8b2a57d3eff7-0000000000000000-01",
+			spanID:     "e457b5a2e4d86bd1",
+			flags:      "01",
+		},
+		{
+			name:       "b3 multi",
+			propagator: xoptrace.B3Multi,
+			carrier: xoptrace.MapCarrier{
+				"x-b3-traceid":      "0af7651916cd43dd8448eb211c80319c",
+				"x-b3-spanid":       "91e961630d5d22de",
+				"x-b3-parentspanid": "b7ad6b7169203331",
+				"x-b3-sampled":      "1",
+			},
+			parent: "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+			spanID: "91e961630d5d22de",
+			flags:  "01",
+		},
+		{
+			name:       "jaeger",
+			propagator: xoptrace.Jaeger,
+			carrier:    xoptrace.MapCarrier{"uber-trace-id": "af7651916cd43dd8448eb211c80319c%3Ab7ad6b7169203331%3A0%3A1"},
+			parent:     "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+			flags:      "01",
+		},
+		{
+			name:       "jaeger short ids",
+			propagator: xoptrace.Jaeger,
+			carrier:    xoptrace.MapCarrier{"uber-trace-id": "abc:def:0:0"},
+			parent:     "00-00000000000000000000000000000abc-0000000000000def-00",
+			flags:      "00",
+		},
+		{
+			name:       "jaeger zero trace",
+			propagator: xoptrace.Jaeger,
+			carrier:    xoptrace.MapCarrier{"uber-trace-id": "0:def:0:1"},
+			notFound:   true,
+		},
+		{
+			name:       "aws",
+			propagator: xoptrace.AWS,
+			carrier:    xoptrace.MapCarrier{"x-amzn-trace-id": "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1"},
+			parent:     "00-5759e988bd862e3fe1be46a994272793-53995c3f42cd8ad8-01",
+			flags:      "01",
+		},
+		{
+			name:       "aws without root",
+			propagator: xoptrace.AWS,
+			carrier:    xoptrace.MapCarrier{"x-amzn-trace-id": "Self=1-5759e988-bd862e3fe1be46a994272793"},
+			notFound:   true,
+		},
+		{
+			name:       "composite later wins",
+			propagator: xoptrace.Composite(xoptrace.W3C, xoptrace.Jaeger, xoptrace.W3CBaggage),
+			carrier: xoptrace.MapCarrier{
+				"traceparent":   "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+				"uber-trace-id": "80f198ee56343ba864fe8b2a57d3eff7:e457b5a2e4d86bd1:0:1",
+				"baggage":       "user=bob",
+			},
+			parent:      "00-80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-01",
+			flags:       "01",
+			baggageUser: "bob",
+		},
+		{
+			name:       "b3 multi malformed trace id",
+			propagator: xoptrace.B3Multi,
+			carrier: xoptrace.MapCarrier{
+				"x-b3-traceid": "not-a-trace-id",
+				"x-b3-spanid":  "91e961630d5d22de",
+			},
+			notFound: true,
+		},
+		{
+			name:       "b3 multi short trace id",
+			propagator: xoptrace.B3Multi,
+			carrier: xoptrace.MapCarrier{
+				"x-b3-traceid": "0af76519",
+				"x-b3-spanid":  "91e961630d5d22de",
+			},
+			notFound: true,
+		},
+		{
+			name:       "b3 multi malformed span id",
+			propagator: xoptrace.B3Multi,
+			carrier: xoptrace.MapCarrier{
+				"x-b3-traceid": "0af7651916cd43dd8448eb211c80319c",
+				"x-b3-spanid":  "91e961630d5d22dz",
+			},
+			notFound: true,
+		},
+		{
+			name:       "b3 multi malformed parent span id",
+			propagator: xoptrace.B3Multi,
+			carrier: xoptrace.MapCarrier{
+				"x-b3-traceid":      "0af7651916cd43dd8448eb211c80319c",
+				"x-b3-spanid":       "91e961630d5d22de",
+				"x-b3-parentspanid": "b7ad6b71692033",
+			},
+			notFound: true,
+		},
+		{
+			name:       "b3 sampling only",
+			propagator: xoptrace.B3Single,
+			carrier:    xoptrace.MapCarrier{"b3": "0"},
+			notFound:   true,
+		},
+		{
+			name:       "composite b3 sampling only keeps parent",
+			propagator: xoptrace.Composite(xoptrace.W3C, xoptrace.B3Single),
+			carrier: xoptrace.MapCarrier{
+				"traceparent": "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+				"b3":          "0",
+			},
+			parent: "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+			flags:  "00",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			bundle := xoptrace.NewBundle()
+			found := tc.propagator.Extract(tc.carrier, &bundle)
+			assert.Equal(t, !tc.notFound, found, "found")
+			if u, ok := bundle.Baggage.Get("user"); tc.baggageUser != "" || ok {
+				assert.Equal(t, tc.baggageUser, u, "baggage")
+			}
+			if tc.notFound {
+				assert.True(t, bundle.Trace.IsZero(), "trace not set")
+				return
+			}
+			assert.Equal(t, tc.parent, bundle.Parent.String(), "parent")
+			assert.Equal(t, bundle.Parent.GetTraceID().String(), bundle.Trace.GetTraceID().String(), "trace id")
+			if tc.spanID != "" {
+				assert.Equal(t, tc.spanID, bundle.Trace.GetSpanID().String(), "span id")
+			} else {
+				assert.False(t, bundle.Trace.GetSpanID().IsZero(), "span id set")
+				assert.NotEqual(t, bundle.Parent.GetSpanID().String(), bundle.Trace.GetSpanID().String(), "new span id")
+			}
+			assert.Equal(t, tc.flags, bundle.Trace.GetFlags().String(), "flags")
+			assert.Equal(t, tc.state, bundle.State.String(), "state")
+		})
+	}
+}
+
+func TestPropagatorRoundTrip(t *testing.T) {
+	for _, p := range []struct {
+		name       string
+		propagator xoptrace.Propagator
+		newSpan    bool
+	}{
+		{name: "w3c", propagator: xoptrace.W3C, newSpan: true},
+		{name: "b3 single", propagator: xoptrace.B3Single},
+		{name: "b3 multi", propagator: xoptrace.B3Multi},
+		{name: "jaeger", propagator: xoptrace.Jaeger, newSpan: true},
+		{name: "aws", propagator: xoptrace.AWS, newSpan: true},
+	} {
+		p := p
+		t.Run(p.name, func(t *testing.T) {
+			client := xoptrace.NewBundle()
+			client.Trace.TraceID().SetRandom()
+			client.Trace.SpanID().SetRandom()
+			client.Parent.SpanID().SetRandom()
+			client.Trace.Flags().SetBytes([]byte{0})
+
+			header := make(http.Header)
+			p.propagator.Inject(xoptrace.HeaderCarrier(header), client)
+			for _, field := range p.propagator.Fields() {
+				delete(header, http.CanonicalHeaderKey(field))
+			}
+			assert.Empty(t, header, "only declared fields are set")
+
+			header = make(http.Header)
+			p.propagator.Inject(xoptrace.HeaderCarrier(header), client)
+			server := xoptrace.NewBundle()
+			require.True(t, p.propagator.Extract(xoptrace.HeaderCarrier(header), &server), "found")
+			assert.Equal(t, client.Trace.GetTraceID().String(), server.Trace.GetTraceID().String(), "trace id")
+			assert.Equal(t, "00", server.Trace.GetFlags().String(), "flags")
+			if p.newSpan {
+				assert.Equal(t, client.Trace.String(), server.Parent.String(), "parent")
+				assert.NotEqual(t, client.Trace.GetSpanID().String(), server.Trace.GetSpanID().String(), "span id")
+			} else {
+				assert.Equal(t, client.Trace.GetSpanID().String(), server.Trace.GetSpanID().String(), "shared span id")
+				assert.Equal(t, client.Parent.GetSpanID().String(), server.Parent.GetSpanID().String(), "parent span id")
+			}
+		})
+	}
+}
+
+func TestPropagatorStateAndBaggage(t *testing.T) {
+	client := xoptrace.NewBundle()
+	client.Trace.TraceID().SetRandom()
+	client.Trace.SpanID().SetRandom()
+	client.State.SetString("congo=t61rcWkgMzE")
+	require.NoError(t, client.Baggage.Set("tenant", "acme corp"))
+
+	carrier := xoptrace.MapCarrier{}
+	xoptrace.Default.Inject(carrier, client)
+	assert.Equal(t, "congo=t61rcWkgMzE", carrier["tracestate"])
+	assert.Equal(t, "tenant=acme%20corp", carrier["baggage"])
+
+	server := xoptrace.NewBundle()
+	require.True(t, xoptrace.Default.Extract(carrier, &server))
+	assert.Equal(t, client.State.String(), server.State.String(), "state")
+	v, _ := server.Baggage.Get("tenant")
+	assert.Equal(t, "acme corp", v, "baggage")
+	assert.ElementsMatch(t, []string{"traceparent", "tracestate", "baggage"}, xoptrace.Default.Fields())
+}
@@ -1,20 +1,195 @@
 package xoptrace
 
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// MaxStateEntries is the maximum number of entries in the "tracestate"
+// header. See https://www.w3.org/TR/trace-context/#tracestate-limits
+const MaxStateEntries = 32
+
+var (
+	stateKeyRE   = regexp.MustCompile(`\A(?:[a-z][-a-z0-9_*/]{0,255}|[a-z0-9][-a-z0-9_*/]{0,240}@[a-z][-a-z0-9_*/]{0,13})\z`)
+	stateValueRE = regexp.MustCompile(`\A[\x20-\x2b\x2d-\x3c\x3e-\x7e]{0,255}[\x21-\x2b\x2d-\x3c\x3e-\x7e]\z`)
+)
+
 // State tracks the contents of key/values that are passed
 // through a trace in the "tracestate" header.
+// See https://www.w3.org/TR/trace-context/#tracestate-header
 //
-// TODO: allow state to be used and modified
+// Entries are kept in header order: the most recently updated
+// entry is first.  Like Baggage, State is only modified through its
+// pointer methods and those replace rather than modify the underlying
+// storage so copies are not affected by changes to the original.
 type State struct {
-	// TODO: allow state to be manipulated
-	// 	state       []struct {
-	// 		vendor string // must match `\a(?:[a-z][-a-z0-9_*/]{0,255}|[a-z0-9][-a-z0-9_*/]{0,240}@[a-z]{1,14})\z`
-	// 		key    string // upto 256 characters, all non-whitespace printable ascii except "," and "="
-	// 	}
+	entries  []StateEntry
 	asString string
 }
 
-func (s *State) SetString(h string) { s.asString = h }
-func (s State) IsZero() bool        { return s.asString == "" }
-func (s State) String() string      { return s.asString }
-func (s State) Copy() State         { return s }
-func (s State) Bytes() []byte       { return []byte(s.asString) } // TODO: improve performance
+// StateEntry is one vendor key/value pair from the "tracestate" header.
+//
+// Keys must match `[a-z][-a-z0-9_*/]{0,255}` or, for multi-tenant
+// keys, `[a-z0-9][-a-z0-9_*/]{0,240}@[a-z][-a-z0-9_*/]{0,13}`.
+// Values are up to 256 characters of printable ASCII except
+// "," and "=" and may not end with a space.
+type StateEntry struct {
+	Key   string
+	Value string
+}
+
+// ParseState parses a "tracestate" header. Unlike SetString, it returns
+// an error if any entry is invalid, if a key is repeated, or if
+// there are more than MaxStateEntries entries.
+func ParseState(h string) (State, error) {
+	var s State
+	err := s.parse(h, true)
+	return s, err
+}
+
+// SetString parses a "tracestate" header, replacing the current
+// contents.  Invalid entries are dropped. When a key is repeated, only
+// the first (leftmost) entry is kept.  Entries beyond MaxStateEntries
+// are dropped.
+func (s *State) SetString(h string) { _ = s.parse(h, false) }
+
+func (s State) IsZero() bool   { return len(s.entries) == 0 }
+func (s State) String() string { return s.asString }
+func (s State) Copy() State    { return s }
+func (s State) Bytes() []byte  { return []byte(s.asString) } // TODO: improve performance
+func (s State) Len() int       { return len(s.entries) }
+
+// Get returns the value for a vendor key
+func (s State) Get(key string) (string, bool) {
+	if i := s.index(key); i != -1 {
+		return s.entries[i].Value, true
+	}
+	return "", false
+}
+
+// Entries returns a copy of the entries in header order
+func (s State) Entries() []StateEntry {
+	e := make([]StateEntry, len(s.entries))
+	copy(e, s.entries)
+	return e
+}
+
+// Set adds or updates a vendor entry. As required by the W3C
+// specification, the entry is moved to the front of the list.
+// If that makes the list too long, the last entry is dropped.
+func (s *State) Set(key string, value string) error {
+	entry := StateEntry{Key: key, Value: value}
+	if err := entry.validate(); err != nil {
+		return err
+	}
+	entries := make([]StateEntry, 1, len(s.entries)+1)
+	entries[0] = entry
+	for _, e := range s.entries {
+		if e.Key != key {
+			entries = append(entries, e)
+		}
+	}
+	if len(entries) > MaxStateEntries {
+		entries = entries[:MaxStateEntries]
+	}
+	s.entries = entries
+	s.asString = serializeState(entries)
+	return nil
+}
+
+// Delete removes a vendor entry. It is not an error if the entry
+// is not present.
+func (s *State) Delete(key string) {
+	i := s.index(key)
+	if i == -1 {
+		return
+	}
+	entries := make([]StateEntry, 0, len(s.entries)-1)
+	entries = append(entries, s.entries[:i]...)
+	entries = append(entries, s.entries[i+1:]...)
+	s.entries = entries
+	s.asString = serializeState(entries)
+}
+
+func (s State) index(key string) int {
+	for i, e := range s.entries {
+		if e.Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *State) parse(h string, strict bool) error {
+	var entries []StateEntry
+	var firstErr error
+	fail := func(err error) {
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	for _, raw := range strings.Split(h, ",") {
+		raw = strings.Trim(raw, " \t")
+		if raw == "" {
+			// empty list members are allowed
+			continue
+		}
+		k, v, ok := strings.Cut(raw, "=")
+		if !ok {
+			fail(errors.Errorf("tracestate entry (%s) is missing '='", raw))
+			continue
+		}
+		entry := StateEntry{Key: k, Value: v}
+		if err := entry.validate(); err != nil {
+			fail(err)
+			continue
+		}
+		dup := false
+		for _, e := range entries {
+			if e.Key == k {
+				dup = true
+				break
+			}
+		}
+		if dup {
+			fail(errors.Errorf("duplicate tracestate key (%s)", k))
+			continue
+		}
+		if len(entries) >= MaxStateEntries {
+			fail(errors.Errorf("tracestate cannot have more than %d entries", MaxStateEntries))
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if strict && firstErr != nil {
+		return firstErr
+	}
+	s.entries = entries
+	s.asString = serializeState(entries)
+	return firstErr
+}
+
+func (e StateEntry) validate() error {
+	if !stateKeyRE.MatchString(e.Key) {
+		return errors.Errorf("invalid tracestate key (%s)", e.Key)
+	}
+	if !stateValueRE.MatchString(e.Value) {
+		return errors.Errorf("invalid tracestate value (%s) for %s", e.Value, e.Key)
+	}
+	return nil
+}
+
+func serializeState(entries []StateEntry) string {
+	var b []byte
+	for i, e := range entries {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = append(b, e.Key...)
+		b = append(b, '=')
+		b = append(b, e.Value...)
+	}
+	return string(b)
+}
@@ -0,0 +1,119 @@
+package xoptrace_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/xoplog/xop-go/xoptrace"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateParse(t *testing.T) {
+	cases := []struct {
+		name    string
+		input   string
+		want    string
+		entries []xoptrace.StateEntry
+		bad     bool
+	}{
+		{
+			name:    "single",
+			input:   "congo=t61rcWkgMzE",
+			want:    "congo=t61rcWkgMzE",
+			entries: []xoptrace.StateEntry{{Key: "congo", Value: "t61rcWkgMzE"}},
+		},
+		{
+			name:  "multi-tenant and whitespace",
+			input: "rojo=00f067aa0ba902b7 , ,t0@vendor=a b",
+			want:  "rojo=00f067aa0ba902b7,t0@vendor=a b",
+			entries: []xoptrace.StateEntry{
+				{Key: "rojo", Value: "00f067aa0ba902b7"},
+				{Key: "t0@vendor", Value: "a b"},
+			},
+		},
+		{
+			name:    "bad key",
+			input:   "Upper=x,ok=y",
+			want:    "ok=y",
+			entries: []xoptrace.StateEntry{{Key: "ok", Value: "y"}},
+			bad:     true,
+		},
+		{
+			name:    "bad value",
+			input:   "a=x=y,b=",
+			want:    "",
+			entries: []xoptrace.StateEntry{},
+			bad:     true,
+		},
+		{
+			name:    "duplicate",
+			input:   "a=1,b=2,a=3",
+			want:    "a=1,b=2",
+			entries: []xoptrace.StateEntry{{Key: "a", Value: "1"}, {Key: "b", Value: "2"}},
+			bad:     true,
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var s xoptrace.State
+			s.SetString(tc.input)
+			assert.Equal(t, tc.want, s.String(), "string")
+			assert.Equal(t, tc.entries, s.Entries(), "entries")
+			_, err := xoptrace.ParseState(tc.input)
+			if tc.bad {
+				assert.Error(t, err, "strict parse")
+			} else {
+				assert.NoError(t, err, "strict parse")
+				again, err := xoptrace.ParseState(s.String())
+				require.NoError(t, err)
+				assert.Equal(t, s.String(), again.String(), "round trip")
+			}
+		})
+	}
+}
+
+func TestStateSet(t *testing.T) {
+	var s xoptrace.State
+	s.SetString("rojo=1,congo=2")
+	orig := s.Copy()
+	require.NoError(t, s.Set("congo", "3"))
+	assert.Equal(t, "congo=3,rojo=1", s.String(), "updated moves to front")
+	require.NoError(t, s.Set("xop", "x"))
+	assert.Equal(t, "xop=x,congo=3,rojo=1", s.String(), "added at front")
+	assert.Equal(t, "rojo=1,congo=2", orig.String(), "copy not modified")
+	v, ok := s.Get("congo")
+	assert.True(t, ok)
+	assert.Equal(t, "3", v)
+
+	assert.Error(t, s.Set("BAD", "x"))
+	assert.Error(t, s.Set("ok", "has,comma"))
+	assert.Error(t, s.Set("ok", "trailing "))
+	assert.Equal(t, "xop=x,congo=3,rojo=1", s.String(), "unchanged after errors")
+
+	s.Delete("congo")
+	s.Delete("missing")
+	assert.Equal(t, "xop=x,rojo=1", s.String())
+}
+
+func TestStateLimit(t *testing.T) {
+	parts := make([]string, xoptrace.MaxStateEntries+1)
+	for i := range parts {
+		parts[i] = "k" + strconv.Itoa(i) + "=v"
+	}
+	h := strings.Join(parts, ",")
+	var s xoptrace.State
+	s.SetString(h)
+	assert.Equal(t, xoptrace.MaxStateEntries, s.Len())
+	_, err := xoptrace.ParseState(h)
+	assert.Error(t, err)
+
+	require.NoError(t, s.Set("new", "v"))
+	assert.Equal(t, xoptrace.MaxStateEntries, s.Len())
+	assert.Equal(t, "new", s.Entries()[0].Key)
+	_, ok := s.Get("k" + strconv.Itoa(xoptrace.MaxStateEntries-1))
+	assert.False(t, ok, "last entry dropped")
+}
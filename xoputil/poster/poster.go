@@ -0,0 +1,62 @@
+// Package poster sends encoded payloads to HTTP collectors. It is
+// shared by the base loggers that POST to a server, like xopotlp and
+// xopzipkin.
+package poster
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// Poster holds the client and headers used for each POST
+type Poster struct {
+	Client *http.Client
+	Header http.Header
+}
+
+// New returns a Poster that uses http.DefaultClient
+func New() Poster {
+	return Poster{
+		Client: http.DefaultClient,
+		Header: make(http.Header),
+	}
+}
+
+// AddHeader adds a header, for example for authentication, that
+// is sent with every request
+func (p *Poster) AddHeader(key, value string) {
+	if p.Header == nil {
+		p.Header = make(http.Header)
+	}
+	p.Header.Add(key, value)
+}
+
+// Post sends enc as application/json to url. Responses that are
+// not 2xx are returned as errors.
+func (p *Poster) Post(url string, enc []byte) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(enc))
+	if err != nil {
+		return errors.Wrap(err, "build request")
+	}
+	for k, v := range p.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "post to %s", req.URL)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("post to %s: %s", req.URL, resp.Status)
+	}
+	return nil
+}
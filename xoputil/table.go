@@ -0,0 +1,57 @@
+package xoputil
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// AlignedTable renders a table as text with padded columns:
+//
+//	name  | count
+//	------+------
+//	alice | 3
+//
+// Rows may have more or fewer cells than the header. Missing cells
+// are only at the ends of rows.
+func AlignedTable(header []string, rows [][]string) string {
+	var widths []int
+	measure := func(row []string) {
+		for i, cell := range row {
+			w := utf8.RuneCountInString(cell)
+			if i >= len(widths) {
+				widths = append(widths, w)
+			} else if w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+	measure(header)
+	for _, row := range rows {
+		measure(row)
+	}
+	var b strings.Builder
+	write := func(row []string) {
+		var line strings.Builder
+		for i, cell := range row {
+			if i != 0 {
+				line.WriteString(" | ")
+			}
+			line.WriteString(cell)
+			line.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)))
+		}
+		b.WriteString(strings.TrimRight(line.String(), " "))
+		b.WriteByte('\n')
+	}
+	write(header)
+	for i, w := range widths {
+		if i != 0 {
+			b.WriteString("-+-")
+		}
+		b.WriteString(strings.Repeat("-", w))
+	}
+	b.WriteByte('\n')
+	for _, row := range rows {
+		write(row)
+	}
+	return b.String()
+}
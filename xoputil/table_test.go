@@ -0,0 +1,21 @@
+package xoputil_test
+
+import (
+	"testing"
+
+	"github.com/xoplog/xop-go/xoputil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlignedTable(t *testing.T) {
+	assert.Equal(t, ""+
+		"name  | count | é\n"+
+		"------+-------+---\n"+
+		"alice | 3\n"+
+		"bo    |       | ü2\n",
+		xoputil.AlignedTable(
+			[]string{"name", "count", "é"},
+			[][]string{{"alice", "3"}, {"bo", "", "ü2"}},
+		))
+}
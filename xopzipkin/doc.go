@@ -0,0 +1,35 @@
+/*
+Package xopzipkin is a xop base logger (xopbase.Logger) that sends
+spans to Zipkin (or any server that accepts Zipkin v2 JSON) without
+depending upon a Zipkin client library.
+
+Spans are sent once they are finished (log.Done has been called).
+Each Flush of a request POSTs the spans that have finished since the
+previous Flush as one batch to the URL given to New, normally
+"http://host:9411/api/v2/spans".
+
+# Mapping
+
+The request's source becomes the local endpoint's service name.
+
+Span metadata becomes tags. Attributes with Multiple set are JSON
+arrays of strings. Enums are their names, times are RFC3339, links
+are traceparent strings, and models are their encoded form. The
+span.kind attribute sets the Zipkin kind (SERVER, CLIENT, PRODUCER,
+or CONSUMER) instead of being a tag.
+
+Lines become annotations:
+
+	level: message key=value ...
+
+Templates are expanded. Model, link, and table lines have the model,
+trace, or table appended. Metrics are annotations too:
+
+	metric: key=value
+
+The first Error or Alert line of a span sets the "error" tag.
+
+Zipkin has no place for stack traces, data types, the request's
+baggage or parent, or attribute definitions so this is a lossy format.
+*/
+package xopzipkin
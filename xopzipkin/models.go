@@ -0,0 +1,103 @@
+package xopzipkin
+
+import (
+	"sync"
+	"time"
+
+	"github.com/xoplog/xop-go/xopbase"
+	"github.com/xoplog/xop-go/xopbase/xopbaseutil"
+	"github.com/xoplog/xop-go/xopnum"
+	"github.com/xoplog/xop-go/xoptrace"
+	"github.com/xoplog/xop-go/xoputil/poster"
+
+	"github.com/google/uuid"
+)
+
+var _ xopbase.Logger = &Logger{}
+var _ xopbase.Request = &request{}
+var _ xopbase.Span = &span{}
+var _ xopbase.Line = &line{}
+var _ xopbase.Prefilling = &prefilling{}
+var _ xopbase.Prefilled = &prefilled{}
+
+type Option func(*Logger)
+
+type Logger struct {
+	id     uuid.UUID
+	url    string
+	poster poster.Poster
+}
+
+type request struct {
+	span
+	sourceInfo xopbase.SourceInfo
+	errorFunc  func(error)
+	errorCount int32
+	alertCount int32
+	boring     int32 // 1 = boring
+	mu         sync.Mutex
+	spans      []*span
+}
+
+type span struct {
+	xopbaseutil.SpanMetadata
+	logger      *Logger
+	request     *request
+	bundle      xoptrace.Bundle
+	name        string
+	startTime   time.Time
+	endTime     int64
+	done        int32              // 1 once Done has been called with final
+	dirty       int32              // 1 if the span should be sent by the next Flush
+	annotations []zipkinAnnotation // protected by request.mu
+	errorMsg    string             // protected by request.mu
+}
+
+type builder struct {
+	span *span
+	kv   []keyValue
+}
+
+type keyValue struct {
+	key   string
+	value string
+}
+
+type prefilling struct {
+	*builder
+}
+
+type prefilled struct {
+	*builder
+	prefillMsg string
+}
+
+type line struct {
+	*builder
+	prefillMsg string
+	level      xopnum.Level
+	timestamp  time.Time
+}
+
+// zipkinSpan is a span in the Zipkin v2 JSON format
+type zipkinSpan struct {
+	TraceID       string             `json:"traceId"`
+	ID            string             `json:"id"`
+	ParentID      string             `json:"parentId,omitempty"`
+	Name          string             `json:"name,omitempty"`
+	Kind          string             `json:"kind,omitempty"`
+	Timestamp     int64              `json:"timestamp"`
+	Duration      int64              `json:"duration,omitempty"`
+	LocalEndpoint *zipkinEndpoint    `json:"localEndpoint,omitempty"`
+	Annotations   []zipkinAnnotation `json:"annotations,omitempty"`
+	Tags          map[string]string  `json:"tags,omitempty"`
+}
+
+type zipkinEndpoint struct {
+	ServiceName string `json:"serviceName,omitempty"`
+}
+
+type zipkinAnnotation struct {
+	Timestamp int64  `json:"timestamp"`
+	Value     string `json:"value"`
+}
@@ -0,0 +1,399 @@
+package xopzipkin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/xoplog/xop-go/xopat"
+	"github.com/xoplog/xop-go/xopbase"
+	"github.com/xoplog/xop-go/xopbase/xopbaseutil"
+	"github.com/xoplog/xop-go/xopconst"
+	"github.com/xoplog/xop-go/xopnum"
+	"github.com/xoplog/xop-go/xoptrace"
+	"github.com/xoplog/xop-go/xoputil"
+	"github.com/xoplog/xop-go/xoputil/poster"
+
+	"github.com/google/uuid"
+	"github.com/muir/list"
+	"github.com/pkg/errors"
+)
+
+// New creates a Logger that POSTs spans to a Zipkin server. The url
+// is the full URL of the span collection endpoint, for example
+// "http://localhost:9411/api/v2/spans".
+//
+// Spans are sent synchronously from Flush. Failures are reported
+// to the error reporter (see xop.Config.ErrorReporter).
+func New(url string, opts ...Option) *Logger {
+	log := &Logger{
+		id:     uuid.New(),
+		url:    url,
+		poster: poster.New(),
+	}
+	for _, f := range opts {
+		f(log)
+	}
+	return log
+}
+
+// WithHTTPClient overrides http.DefaultClient
+func WithHTTPClient(client *http.Client) Option {
+	return func(log *Logger) {
+		log.poster.Client = client
+	}
+}
+
+// WithHeader adds a header, for example for authentication, to
+// the requests sent to Zipkin
+func WithHeader(key, value string) Option {
+	return func(log *Logger) {
+		log.poster.AddHeader(key, value)
+	}
+}
+
+func (logger *Logger) ID() string           { return logger.id.String() }
+func (logger *Logger) Buffered() bool       { return true }
+func (logger *Logger) ReferencesKept() bool { return false }
+
+func (logger *Logger) Request(_ context.Context, ts time.Time, bundle xoptrace.Bundle, name string, sourceInfo xopbase.SourceInfo) xopbase.Request {
+	r := &request{
+		span: span{
+			logger:    logger,
+			bundle:    bundle,
+			name:      name,
+			startTime: ts,
+		},
+		sourceInfo: sourceInfo,
+		errorFunc:  func(error) {},
+	}
+	r.request = r
+	r.spans = []*span{&r.span}
+	return r
+}
+
+// Flush sends the spans that have finished since the previous Flush
+// in one batch. Spans that have not finished keep their annotations
+// until they do. A span that is finished more than once is sent more
+// than once: Zipkin merges the copies.
+//
+// Flush is skipped for boring requests that have no lines at the
+// Error or Alert level.
+func (r *request) Flush() {
+	if atomic.LoadInt32(&r.boring) == 1 && r.GetErrorCount() == 0 && r.GetAlertCount() == 0 {
+		return
+	}
+	var batch []zipkinSpan
+	func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		for _, s := range r.spans {
+			if atomic.SwapInt32(&s.dirty, 0) == 0 {
+				continue
+			}
+			batch = append(batch, s.zipkin())
+			s.annotations = nil
+		}
+	}()
+	if len(batch) == 0 {
+		return
+	}
+	enc, err := json.Marshal(batch)
+	if err != nil {
+		r.errorFunc(errors.Wrap(err, "encode zipkin spans"))
+		return
+	}
+	err = r.logger.poster.Post(r.logger.url, enc)
+	if err != nil {
+		r.errorFunc(err)
+	}
+}
+
+func (r *request) Final() {}
+
+// Boring is honored for requests. Spans are kept until Flush, so
+// boring requests are simply not flushed.
+func (r *request) Boring(b bool) bool {
+	if b {
+		atomic.StoreInt32(&r.boring, 1)
+	} else {
+		atomic.StoreInt32(&r.boring, 0)
+	}
+	return true
+}
+
+func (r *request) SetErrorReporter(reporter func(error)) { r.errorFunc = reporter }
+func (r *request) GetErrorCount() int32                  { return atomic.LoadInt32(&r.errorCount) }
+func (r *request) GetAlertCount() int32                  { return atomic.LoadInt32(&r.alertCount) }
+
+func (s *span) Span(_ context.Context, ts time.Time, bundle xoptrace.Bundle, name string, _ string) xopbase.Span {
+	n := &span{
+		logger:    s.logger,
+		request:   s.request,
+		bundle:    bundle,
+		name:      name,
+		startTime: ts,
+	}
+	s.request.mu.Lock()
+	defer s.request.mu.Unlock()
+	s.request.spans = append(s.request.spans, n)
+	return n
+}
+
+// Done marks a span to be sent by the next Flush. Spans are only sent
+// once they are final but lines logged after that (a bug in the
+// application) will cause them to be sent again.
+func (s *span) Done(t time.Time, final bool) {
+	xoputil.AtomicMaxInt64(&s.endTime, t.UnixNano())
+	if final {
+		atomic.StoreInt32(&s.done, 1)
+	}
+	s.resend()
+}
+
+// resend marks a finished span to be sent again by the next Flush
+func (s *span) resend() {
+	if atomic.LoadInt32(&s.done) == 1 {
+		atomic.StoreInt32(&s.dirty, 1)
+	}
+}
+
+// Metric values are recorded as annotations
+func (s *span) Metric(k *xopat.MetricAttribute, v float64, t time.Time) {
+	xoputil.AtomicMaxInt64(&s.endTime, t.UnixNano())
+	s.annotate(t, "metric: "+k.Key().String()+"="+strconv.FormatFloat(v, 'g', -1, 64))
+}
+
+func (s *span) Boring(bool) bool { return false }
+func (s *span) ID() string       { return s.logger.id.String() }
+
+func (s *span) annotate(t time.Time, value string) {
+	s.request.mu.Lock()
+	defer s.request.mu.Unlock()
+	s.annotations = append(s.annotations, zipkinAnnotation{
+		Timestamp: t.UnixMicro(),
+		Value:     value,
+	})
+	s.resend()
+}
+
+// zipkin must be called from Flush with the request lock held
+func (s *span) zipkin() zipkinSpan {
+	z := zipkinSpan{
+		TraceID:     s.bundle.Trace.GetTraceID().String(),
+		ID:          s.bundle.Trace.GetSpanID().String(),
+		Name:        s.name,
+		Timestamp:   s.startTime.UnixMicro(),
+		Annotations: list.Copy(s.annotations),
+		LocalEndpoint: &zipkinEndpoint{
+			ServiceName: s.request.sourceInfo.Source,
+		},
+	}
+	// Zipkin requires a positive duration for finished spans
+	z.Duration = (atomic.LoadInt64(&s.endTime) - s.startTime.UnixNano()) / int64(time.Microsecond)
+	if z.Duration < 1 {
+		z.Duration = 1
+	}
+	if !s.bundle.Parent.GetSpanID().IsZero() && s.bundle.Parent.GetTraceID() == s.bundle.Trace.GetTraceID() {
+		z.ParentID = s.bundle.Parent.GetSpanID().String()
+	}
+	tags := make(map[string]string)
+	s.SpanMetadata.Map.Range(func(k string, tracker *xopbaseutil.MetadataTracker) bool {
+		tracker.Mu.Lock()
+		defer tracker.Mu.Unlock()
+		if k == xopconst.SpanKind.Key().String() {
+			if kind, ok := tracker.Value.(xopat.Enum); ok {
+				switch xopconst.SpanKindEnum(kind.Int64()) {
+				case xopconst.SpanKindServer, xopconst.SpanKindClient, xopconst.SpanKindProducer, xopconst.SpanKindConsumer:
+					z.Kind = xopconst.SpanKindEnum(kind.Int64()).String()
+					return true
+				}
+			}
+		}
+		tags[k] = tagValue(tracker.Value)
+		return true
+	})
+	if s.errorMsg != "" {
+		tags["error"] = s.errorMsg
+	}
+	if len(tags) != 0 {
+		z.Tags = tags
+	}
+	return z
+}
+
+// tagValue converts span metadata into a Zipkin tag. Attributes
+// with Multiple set become JSON arrays of strings.
+func tagValue(v interface{}) string {
+	switch v := v.(type) {
+	case []interface{}:
+		values := make([]string, len(v))
+		for i, e := range v {
+			values[i] = tagValue(e)
+		}
+		enc, _ := json.Marshal(values)
+		return string(enc)
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case xoptrace.Trace:
+		return v.String()
+	case xopat.Enum:
+		return v.String()
+	case xopbase.ModelArg:
+		v.Encode()
+		return string(v.Encoded)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func (s *span) builder() *builder {
+	return &builder{
+		span: s,
+	}
+}
+
+func (s *span) NoPrefill() xopbase.Prefilled {
+	return &prefilled{
+		builder: s.builder(),
+	}
+}
+
+func (s *span) StartPrefill() xopbase.Prefilling {
+	return &prefilling{
+		builder: s.builder(),
+	}
+}
+
+func (p *prefilling) PrefillComplete(m string) xopbase.Prefilled {
+	return &prefilled{
+		builder:    p.builder,
+		prefillMsg: m,
+	}
+}
+
+func (p *prefilled) Line(level xopnum.Level, t time.Time, _ []runtime.Frame) xopbase.Line {
+	xoputil.AtomicMaxInt64(&p.span.endTime, t.UnixNano())
+	if level >= xopnum.ErrorLevel {
+		if level >= xopnum.AlertLevel {
+			_ = atomic.AddInt32(&p.span.request.alertCount, 1)
+		} else {
+			_ = atomic.AddInt32(&p.span.request.errorCount, 1)
+		}
+	}
+	return &line{
+		builder: &builder{
+			span: p.span,
+			kv:   list.Copy(p.kv),
+		},
+		prefillMsg: p.prefillMsg,
+		level:      level,
+		timestamp:  t,
+	}
+}
+
+func (l *line) Msg(m string) {
+	l.send(l.prefillMsg+m, l.kv, "")
+}
+
+var templateRE = regexp.MustCompile(`\{.+?\}`)
+
+func (l *line) Template(m string) {
+	used := make(map[string]struct{})
+	msg := templateRE.ReplaceAllStringFunc(l.prefillMsg+m, func(k string) string {
+		k = k[1 : len(k)-1]
+		for _, kv := range l.kv {
+			if kv.key == k {
+				used[k] = struct{}{}
+				return kv.value
+			}
+		}
+		return "''"
+	})
+	unused := make([]keyValue, 0, len(l.kv))
+	for _, kv := range l.kv {
+		if _, ok := used[kv.key]; !ok {
+			unused = append(unused, kv)
+		}
+	}
+	l.send(msg, unused, "")
+}
+
+func (l *line) Model(m string, v xopbase.ModelArg) {
+	v.Encode()
+	l.send(l.prefillMsg+m, l.kv, " "+string(v.Encoded))
+}
+
+func (l *line) Link(m string, v xoptrace.Trace) {
+	l.send(l.prefillMsg+m, l.kv, " "+v.String())
+}
+
+func (l *line) Table(m string, v xopbase.SimpleTable) {
+	l.send(l.prefillMsg+m, l.kv, "\n"+xoputil.AlignedTable(v.Header(), v.Rows()))
+}
+
+// send adds an annotation: "level: message k=v ...".  The first
+// Error or Alert line also becomes the span's error tag.
+func (l *line) send(msg string, kv []keyValue, suffix string) {
+	var b strings.Builder
+	b.WriteString(l.level.String())
+	b.WriteString(": ")
+	b.WriteString(msg)
+	for _, kv := range kv {
+		b.WriteString(" ")
+		b.WriteString(kv.key)
+		b.WriteString("=")
+		b.WriteString(kv.value)
+	}
+	b.WriteString(suffix)
+	s := l.span
+	s.request.mu.Lock()
+	defer s.request.mu.Unlock()
+	s.annotations = append(s.annotations, zipkinAnnotation{
+		Timestamp: l.timestamp.UnixMicro(),
+		Value:     b.String(),
+	})
+	if l.level >= xopnum.ErrorLevel && s.errorMsg == "" {
+		s.errorMsg = msg
+	}
+	s.resend()
+}
+
+func (b *builder) add(k string, v string) {
+	b.kv = append(b.kv, keyValue{key: k, value: v})
+}
+
+func (b *builder) Enum(k *xopat.EnumAttribute, v xopat.Enum) { b.add(k.Key().String(), v.String()) }
+func (b *builder) Bool(k xopat.K, v bool)                    { b.add(k.String(), strconv.FormatBool(v)) }
+func (b *builder) Time(k xopat.K, v time.Time)               { b.add(k.String(), v.Format(time.RFC3339Nano)) }
+func (b *builder) Duration(k xopat.K, v time.Duration)       { b.add(k.String(), v.String()) }
+
+func (b *builder) Any(k xopat.K, v xopbase.ModelArg) {
+	v.Encode()
+	b.add(k.String(), string(v.Encoded))
+}
+
+func (b *builder) Int64(k xopat.K, v int64, _ xopbase.DataType) {
+	b.add(k.String(), strconv.FormatInt(v, 10))
+}
+
+func (b *builder) Uint64(k xopat.K, v uint64, _ xopbase.DataType) {
+	b.add(k.String(), strconv.FormatUint(v, 10))
+}
+
+func (b *builder) Float64(k xopat.K, v float64, _ xopbase.DataType) {
+	b.add(k.String(), strconv.FormatFloat(v, 'g', -1, 64))
+}
+
+func (b *builder) String(k xopat.K, v string, _ xopbase.DataType) {
+	b.add(k.String(), v)
+}
@@ -0,0 +1,233 @@
+package xopzipkin_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xoplog/xop-go"
+	"github.com/xoplog/xop-go/xopbase"
+	"github.com/xoplog/xop-go/xopconst"
+	"github.com/xoplog/xop-go/xopnum"
+	"github.com/xoplog/xop-go/xoptest"
+	"github.com/xoplog/xop-go/xoptest/xoptestutil"
+	"github.com/xoplog/xop-go/xoptrace"
+	"github.com/xoplog/xop-go/xopzipkin"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type zipkinSpan struct {
+	TraceID       string `json:"traceId"`
+	ID            string `json:"id"`
+	ParentID      string `json:"parentId"`
+	Name          string `json:"name"`
+	Kind          string `json:"kind"`
+	Timestamp     int64  `json:"timestamp"`
+	Duration      int64  `json:"duration"`
+	LocalEndpoint struct {
+		ServiceName string `json:"serviceName"`
+	} `json:"localEndpoint"`
+	Annotations []struct {
+		Timestamp int64  `json:"timestamp"`
+		Value     string `json:"value"`
+	} `json:"annotations"`
+	Tags map[string]string `json:"tags"`
+}
+
+type collector struct {
+	mu      sync.Mutex
+	paths   []string
+	auth    []string
+	batches [][]zipkinSpan
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	var batch []zipkinSpan
+	if err := json.Unmarshal(body, &batch); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.paths = append(c.paths, r.URL.Path)
+	c.auth = append(c.auth, r.Header.Get("Authorization"))
+	c.batches = append(c.batches, batch)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func TestZipkin(t *testing.T) {
+	c := &collector{}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	seed := xop.NewSeed(
+		xop.WithBase(xopzipkin.New(server.URL+"/api/v2/spans",
+			xopzipkin.WithHeader("Authorization", "Bearer token"))),
+		xop.WithSettings(func(settings *xop.LogSettings) {
+			settings.SynchronousFlush(true)
+		}),
+	)
+	log := seed.Request(t.Name())
+	log.Span().Enum(xopconst.SpanKind, xopconst.SpanKindServer)
+	log.Span().String(xopconst.URL, "/hello")
+	log.Info().String(xop.Key("color"), "blue").Int(xop.Key("count"), 3).Msg("a line")
+	log.Info().String(xop.Key("who"), "world").Template("hello {who}")
+
+	child := log.Sub().Fork("a span")
+	child.Span().Enum(xopconst.SpanKind, xopconst.SpanKindClient)
+	child.Error().Msg("oops")
+	child.Done()
+	log.Flush()
+
+	c.mu.Lock()
+	require.Len(t, c.batches, 1, "only the finished span is sent")
+	require.Len(t, c.batches[0], 1)
+	childSpan := c.batches[0][0]
+	c.mu.Unlock()
+	assert.Equal(t, "a span", childSpan.Name)
+	assert.Equal(t, "CLIENT", childSpan.Kind)
+	assert.Equal(t, "oops", childSpan.Tags["error"])
+	assert.NotContains(t, childSpan.Tags, "span.kind")
+	require.Len(t, childSpan.Annotations, 1)
+	assert.Equal(t, "error: oops", childSpan.Annotations[0].Value)
+
+	log.Done()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	assert.Equal(t, []string{"/api/v2/spans", "/api/v2/spans"}, c.paths)
+	assert.Equal(t, []string{"Bearer token", "Bearer token"}, c.auth)
+	require.Len(t, c.batches, 2)
+	require.Len(t, c.batches[1], 1, "child is not resent")
+	requestSpan := c.batches[1][0]
+	assert.Equal(t, t.Name(), requestSpan.Name)
+	assert.Equal(t, "SERVER", requestSpan.Kind)
+	assert.Equal(t, "/hello", requestSpan.Tags["http.url"])
+	assert.Equal(t, requestSpan.TraceID, childSpan.TraceID)
+	assert.Equal(t, requestSpan.ID, childSpan.ParentID)
+	assert.Empty(t, requestSpan.ParentID)
+	assert.Len(t, requestSpan.TraceID, 32)
+	assert.Len(t, requestSpan.ID, 16)
+	assert.NotZero(t, requestSpan.Timestamp)
+	assert.NotZero(t, requestSpan.Duration)
+	require.Len(t, requestSpan.Annotations, 2)
+	assert.Equal(t, "info: a line color=blue count=3", requestSpan.Annotations[0].Value)
+	assert.Equal(t, "info: hello world", requestSpan.Annotations[1].Value)
+}
+
+func TestZipkinMessageCases(t *testing.T) {
+	for _, mc := range xoptestutil.MessageCases {
+		mc := mc
+		t.Run(mc.Name, func(t *testing.T) {
+			c := &collector{}
+			server := httptest.NewServer(c)
+			defer server.Close()
+
+			var reported []error
+			tLog := xoptest.New(t)
+			seed := xop.NewSeed(
+				xop.WithBase(xopzipkin.New(server.URL+"/api/v2/spans")),
+				xop.WithBase(tLog),
+				xop.WithConfigChanges(func(config *xop.Config) {
+					config.ErrorReporter = func(err error) { reported = append(reported, err) }
+				}),
+				xop.WithSettings(func(settings *xop.LogSettings) {
+					settings.SynchronousFlush(true)
+				}),
+			)
+			if len(mc.SeedMods) != 0 {
+				seed = seed.Copy(mc.SeedMods...)
+			}
+			log := seed.Request(t.Name())
+			mc.Do(t, log, tLog)
+			assert.Empty(t, reported, "errors")
+
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			sent := make(map[string]struct{})
+			for _, batch := range c.batches {
+				for _, span := range batch {
+					sent[span.ID] = struct{}{}
+				}
+			}
+			for _, span := range tLog.Recorder().Spans {
+				assert.Contains(t, sent, span.Bundle.Trace.GetSpanID().String(), "span sent")
+			}
+		})
+	}
+}
+
+func TestZipkinError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	var reported []error
+	seed := xop.NewSeed(
+		xop.WithBase(xopzipkin.New(server.URL+"/api/v2/spans")),
+		xop.WithConfigChanges(func(config *xop.Config) {
+			config.ErrorReporter = func(err error) { reported = append(reported, err) }
+		}),
+		xop.WithSettings(func(settings *xop.LogSettings) {
+			settings.SynchronousFlush(true)
+		}),
+	)
+	log := seed.Request(t.Name())
+	log.Info().Msg("lost")
+	log.Done()
+	require.NotEmpty(t, reported)
+	assert.Contains(t, reported[0].Error(), "503")
+}
+
+func TestBoringZipkin(t *testing.T) {
+	c := &collector{}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	seed := xop.NewSeed(
+		xop.WithBase(xopzipkin.New(server.URL+"/api/v2/spans")),
+		xop.WithSettings(func(settings *xop.LogSettings) {
+			settings.SynchronousFlush(true)
+		}),
+	)
+	log := seed.Request("boring")
+	log.Boring()
+	log.Info().Msg("nothing to see")
+	log.Done()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	assert.Empty(t, c.batches, "boring request not sent")
+}
+
+func TestZipkinLineAfterDone(t *testing.T) {
+	c := &collector{}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	bundle := xoptrace.NewBundle()
+	bundle.Trace.RebuildSetNonZero()
+	request := xopzipkin.New(server.URL+"/api/v2/spans").
+		Request(context.Background(), time.Now(), bundle, t.Name(), xopbase.SourceInfo{Source: "test"})
+	request.NoPrefill().Line(xopnum.InfoLevel, time.Now(), nil).Msg("on time")
+	request.Done(time.Now(), true)
+	request.Flush()
+	request.NoPrefill().Line(xopnum.InfoLevel, time.Now(), nil).Msg("late")
+	request.Flush()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	require.Len(t, c.batches, 2, "request is sent again")
+	require.Len(t, c.batches[1], 1)
+	require.Len(t, c.batches[1][0].Annotations, 1)
+	assert.Equal(t, "info: late", c.batches[1][0].Annotations[0].Value)
+}